@@ -0,0 +1,139 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AddressAbbreviation selects the steps taken to shorten an address line
+// that is wider than its column. The steps can be combined and are applied
+// in the order below until the line fits; a line that is still too wide is
+// wrapped.
+type AddressAbbreviation int
+
+const (
+	// AbbreviateStreetNames abbreviates common words of street names,
+	// e.g. “Bahnhofstrasse” to “Bahnhofstr.” or “Avenue” to “Av.”.
+	AbbreviateStreetNames AddressAbbreviation = 1 << iota
+
+	// DropBuildingNumberSuffixes removes the letters after the digits of
+	// a building number at the end of a line, e.g. “12bis” becomes “12”.
+	DropBuildingNumberSuffixes
+
+	// EllipsizeAddressLines cuts the line and ends it with “…”.
+	EllipsizeAddressLines
+)
+
+// streetWords maps words of street names to their abbreviations.
+var streetWords = map[string]string{
+	"Strasse":   "Str.",
+	"Straße":    "Str.",
+	"Street":    "St.",
+	"Road":      "Rd.",
+	"Avenue":    "Av.",
+	"Boulevard": "Bd",
+	"Chemin":    "Ch.",
+	"Route":     "Rte",
+	"Place":     "Pl.",
+	"Piazza":    "P.za",
+	"Viale":     "V.le",
+}
+
+// streetSuffixes maps endings of compound German street names to their
+// abbreviations.
+var streetSuffixes = []struct{ suffix, abbreviation string }{
+	{"strasse", "str."},
+	{"straße", "str."},
+}
+
+// AbbreviateAddress shortens the address lines wider than maxWidth, given
+// as a multiple of the font size in Helvetica, as selected by abbreviation.
+// It returns the lines together with one description per shortened line.
+func AbbreviateAddress(lines []string, maxWidth float64,
+	abbreviation AddressAbbreviation) ([]string, []string) {
+	if abbreviation == 0 {
+		return lines, nil
+	}
+	var abbreviated, changes []string
+	for _, line := range lines {
+		short := line
+		if stringWidth(short) > maxWidth && abbreviation&AbbreviateStreetNames != 0 {
+			short = abbreviateStreetNames(short)
+		}
+		if stringWidth(short) > maxWidth && abbreviation&DropBuildingNumberSuffixes != 0 {
+			short = dropBuildingNumberSuffix(short)
+		}
+		if stringWidth(short) > maxWidth && abbreviation&EllipsizeAddressLines != 0 {
+			short = shortenToWidth(short, maxWidth)
+		}
+		if short != line {
+			changes = append(changes, fmt.Sprintf("Address line “%v” abbreviated to “%v”", line, short))
+		}
+		abbreviated = append(abbreviated, short)
+	}
+	return abbreviated, changes
+}
+
+func abbreviateStreetNames(line string) string {
+	words := strings.Split(line, " ")
+	for n, word := range words {
+		if short, ok := streetWords[word]; ok {
+			words[n] = short
+			continue
+		}
+		for _, s := range streetSuffixes {
+			if strings.HasSuffix(word, s.suffix) && len(word) > len(s.suffix) {
+				words[n] = strings.TrimSuffix(word, s.suffix) + s.abbreviation
+				break
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func dropBuildingNumberSuffix(line string) string {
+	i := strings.LastIndex(line, " ")
+	if i < 0 {
+		return line
+	}
+	number := line[i+1:]
+	digits := strings.IndexFunc(number, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits <= 0 {
+		return line
+	}
+	return line[:i+1] + number[:digits]
+}
+
+// AddressAbbreviations returns the abbreviations that the layout of the
+// options makes in the addresses of the receipt and of the payment part.
+func AddressAbbreviations(p Payload, opts RenderOptions) ([]string, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	_, receipt, err := informationSection(p, opts.language(), WidthForReceipt(8),
+		receiptPartInformation, opts.Layout)
+	if err != nil {
+		return nil, err
+	}
+	_, payment, err := informationSection(p, opts.language(), WidthForPaymentPart(10),
+		paymentPartInformation, opts.Layout)
+	if err != nil {
+		return nil, err
+	}
+	return append(receipt, payment...), nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAbbreviateAddress(t *testing.T) {
+	lines := []string{
+		"Verein zur Förderung der Kultur",
+		"Untere Bahnhofstrasse 123bis",
+		"8000 Zürich",
+	}
+	var testdata = []struct {
+		abbreviation AddressAbbreviation
+		expected     []string
+		changes      int
+	}{
+		{0, lines, 0},
+		{AbbreviateStreetNames, []string{
+			"Verein zur Förderung der Kultur",
+			"Untere Bahnhofstr. 123bis",
+			"8000 Zürich",
+		}, 1},
+		{AbbreviateStreetNames | DropBuildingNumberSuffixes, []string{
+			"Verein zur Förderung der Kultur",
+			"Untere Bahnhofstr. 123",
+			"8000 Zürich",
+		}, 1},
+		{AbbreviateStreetNames | DropBuildingNumberSuffixes | EllipsizeAddressLines, []string{
+			"Verein zur Förderung …",
+			"Untere Bahnhofstr. 123",
+			"8000 Zürich",
+		}, 2},
+	}
+	for _, item := range testdata {
+		got, changes := AbbreviateAddress(lines, 11, item.abbreviation)
+		if !reflect.DeepEqual(item.expected, got) {
+			t.Errorf("Abbreviation %v: expected %#v, got: %#v", item.abbreviation, item.expected, got)
+		}
+		if len(changes) != item.changes {
+			t.Errorf("Abbreviation %v: expected %d changes, got: %v", item.abbreviation, item.changes, changes)
+		}
+	}
+}
+
+func TestAddressAbbreviations(t *testing.T) {
+	data := examplePayload1
+	data.Creditor.Address = StructuredAddress{
+		StreetName:     "Avenue du Général-Guisan et de la Gare",
+		BuildingNumber: "12a",
+		PostCode:       "1000",
+		TownName:       "Lausanne",
+	}
+	opts := RenderOptions{Language: "fr"}
+	if changes, err := AddressAbbreviations(data, opts); err != nil || len(changes) != 0 {
+		t.Errorf("Expected no abbreviations, got: %v, %v", changes, err)
+	}
+	opts.Layout.AddressAbbreviation = AbbreviateStreetNames
+	changes, err := AddressAbbreviations(data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Address line “Avenue du Général-Guisan et de la Gare 12a” abbreviated to “Av. du Général-Guisan et de la Gare 12a”"}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Errorf("Expected %#v, got: %#v", expected, changes)
+	}
+	if _, err := AddressAbbreviations(Payload{}, opts); err == nil {
+		t.Error("Expected error for empty payload")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ToStructured converts a combined address to a structured address by
+// splitting the first line into street name and building number and the
+// second line into post code and town. The countryHint is the two-letter
+// country code of the address; it selects the expected format of the post
+// code and may be empty if unknown. An error is returned if the address
+// cannot be split reliably; such addresses must be converted by hand.
+func (ca CombinedAddress) ToStructured(countryHint string) (StructuredAddress, error) {
+	sa, _, err := ca.ToStructuredWithWarnings(countryHint)
+	return sa, err
+}
+
+// ToStructuredWithWarnings converts a combined address like ToStructured
+// and also returns the ambiguities of the conversion, such as a street line
+// without building number or a post code whose format is unknown. Addresses
+// with warnings are converted, but should be checked by hand.
+func (ca CombinedAddress) ToStructuredWithWarnings(countryHint string) (StructuredAddress, []string, error) {
+	var sa StructuredAddress
+	var warnings []string
+	sa.StreetName, sa.BuildingNumber, warnings = splitStreetLine(ca.AddressLine1)
+	postCode, town, townWarnings, err := splitPostCodeTown(ca.AddressLine2, countryHint)
+	if err != nil {
+		return StructuredAddress{}, nil, err
+	}
+	sa.PostCode, sa.TownName = postCode, town
+	if err := sa.Validate(); err != nil {
+		return StructuredAddress{}, nil, err
+	}
+	return sa, append(warnings, townWarnings...), nil
+}
+
+// StructureAddresses converts the combined addresses of all parties of the
+// payload to structured addresses with ToStructuredWithWarnings, as needed
+// for version 2.3 of the guidelines, which no longer permits combined
+// addresses. The warnings are prefixed with the party, e.g. “Creditor: ”.
+func (p Payload) StructureAddresses() (Payload, []string, error) {
+	var warnings []string
+	for _, party := range []struct {
+		field  string
+		entity *Entity
+	}{
+		{"Creditor", &p.Creditor},
+		{"UltimateCreditor", &p.UltimateCreditor},
+		{"UltimateDebtor", &p.UltimateDebtor},
+	} {
+		ca, ok := party.entity.Address.(CombinedAddress)
+		if !ok {
+			continue
+		}
+		sa, w, err := ca.ToStructuredWithWarnings(NormalizeCountryCode(party.entity.CountryCode))
+		if err != nil {
+			return Payload{}, nil, fmt.Errorf("%v: %v", party.field, err)
+		}
+		party.entity.Address = sa
+		for _, warning := range w {
+			warnings = append(warnings, party.field+": "+warning)
+		}
+	}
+	return p, warnings, nil
+}
+
+var (
+	// “Bahnhofstrasse 12a”, “Via Cantonale 3/5”.
+	streetThenNumber = regexp.MustCompile(`^(.*[^\s,]),?\s+(\d+\s?[a-zA-Z]?(?:[/-]\d+[a-zA-Z]?)?)$`)
+	// “12 rue du Lac”, “12bis, avenue de la Gare”.
+	numberThenStreet = regexp.MustCompile(`^(\d+[a-zA-Z]{0,3}),?\s+(\D.*)$`)
+	// Post office boxes have no building number.
+	postOfficeBox = regexp.MustCompile(`(?i)^(postfach|case postale|casella postale|boîte postale|bp|p\.?\s?o\.?\s?box)\b`)
+)
+
+// splitStreetLine splits an address line into street name and building
+// number. If no building number can be found, the whole line is returned
+// as street name. The warnings report splits that may be wrong.
+func splitStreetLine(line string) (street, number string, warnings []string) {
+	line = strings.Join(strings.Fields(line), " ")
+	switch {
+	case line == "":
+	case postOfficeBox.MatchString(line):
+		return line, "", []string{fmt.Sprintf("Post office box kept as street name: %v", line)}
+	default:
+		if m := streetThenNumber.FindStringSubmatch(line); m != nil {
+			street, number = m[1], strings.ReplaceAll(m[2], " ", "")
+		} else if m := numberThenStreet.FindStringSubmatch(line); m != nil {
+			street, number = m[2], m[1]
+		} else {
+			return line, "", []string{fmt.Sprintf("No building number found: %v", line)}
+		}
+		if strings.ContainsAny(street, "0123456789") {
+			warnings = append(warnings, fmt.Sprintf("Street name contains digits: %v", street))
+		}
+		return street, number, warnings
+	}
+	return line, "", nil
+}
+
+// postCodeFormats gives the format of post codes for some countries.
+// Post codes of other countries are assumed to be the first word of the
+// line that contains a digit.
+var postCodeFormats = map[string]*regexp.Regexp{
+	"CH": regexp.MustCompile(`^[0-9]{4}$`),
+	"LI": regexp.MustCompile(`^[0-9]{4}$`),
+	"AT": regexp.MustCompile(`^[0-9]{4}$`),
+	"DE": regexp.MustCompile(`^[0-9]{5}$`),
+	"FR": regexp.MustCompile(`^[0-9]{5}$`),
+	"IT": regexp.MustCompile(`^[0-9]{5}$`),
+}
+
+// countryPrefix matches the country prefix that is sometimes written in
+// front of the post code, such as “CH-8000” or “FL-9490”.
+var countryPrefix = regexp.MustCompile(`^[A-Z]{1,3}-`)
+
+// splitPostCodeTown splits an address line into post code and town. The
+// warnings report splits that may be wrong.
+func splitPostCodeTown(line, countryHint string) (postCode, town string, warnings []string, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return "", "", nil, fmt.Errorf("Cannot split post code and town: %v", line)
+	}
+	postCode = countryPrefix.ReplaceAllString(fields[0], "")
+	town = strings.Join(fields[1:], " ")
+	if format, ok := postCodeFormats[strings.ToUpper(countryHint)]; ok {
+		if !format.MatchString(postCode) {
+			return "", "", nil, fmt.Errorf("Invalid post code for country %v: %v", countryHint, line)
+		}
+	} else if !strings.ContainsAny(postCode, "0123456789") {
+		return "", "", nil, fmt.Errorf("Cannot split post code and town: %v", line)
+	} else {
+		warnings = append(warnings, fmt.Sprintf("Unknown post code format for country %q: %v", countryHint, line))
+	}
+	if strings.ContainsAny(town, "0123456789") {
+		warnings = append(warnings, fmt.Sprintf("Town contains digits: %v", town))
+	}
+	return postCode, town, warnings, nil
+}
+
+// parseAddressCountries lists the countries whose address formats
+// ParseAddress understands.
+var parseAddressCountries = map[string]bool{"CH": true, "LI": true, "DE": true, "FR": true}
+
+// ParseAddress extracts a structured address from the lines of a free-form
+// address in the format of Switzerland, Liechtenstein, Germany or France,
+// given by countryCode. The lines may include the name of the recipient and
+// a country line; the post code and town are taken from the last line that
+// starts with a post code of the country, and street and building number
+// from the nearest line above it that contains a building number or a post
+// office box, or else from the line directly above it.
+func ParseAddress(lines []string, countryCode string) (StructuredAddress, error) {
+	country := strings.ToUpper(strings.TrimSpace(countryCode))
+	if !parseAddressCountries[country] {
+		return StructuredAddress{}, fmt.Errorf("Unsupported country for address parsing: %v", countryCode)
+	}
+	var cleaned []string
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	townLine := -1
+	for i := len(cleaned) - 1; i >= 0 && townLine < 0; i-- {
+		if _, _, _, err := splitPostCodeTown(cleaned[i], country); err == nil {
+			townLine = i
+		}
+	}
+	if townLine < 0 {
+		return StructuredAddress{}, fmt.Errorf("No post code and town found: %v", strings.Join(lines, ", "))
+	}
+	var sa StructuredAddress
+	sa.PostCode, sa.TownName, _, _ = splitPostCodeTown(cleaned[townLine], country)
+	if townLine > 0 {
+		streetLine := townLine - 1
+		for i := townLine - 1; i >= 0; i-- {
+			if street, number, _ := splitStreetLine(cleaned[i]); number != "" || postOfficeBox.MatchString(street) {
+				streetLine = i
+				break
+			}
+		}
+		sa.StreetName, sa.BuildingNumber, _ = splitStreetLine(cleaned[streetLine])
+	}
+	if err := sa.Validate(); err != nil {
+		return StructuredAddress{}, err
+	}
+	return sa, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCombinedToStructured(t *testing.T) {
+	var testdata = []struct {
+		input    CombinedAddress
+		country  string
+		expected StructuredAddress
+	}{
+		{CombinedAddress{"Bahnhofstrasse 12a", "8001 Zürich"}, "CH",
+			StructuredAddress{"Bahnhofstrasse", "12a", "8001", "Zürich"}},
+		{CombinedAddress{"Musterstrasse 7 b", "CH-3000 Bern"}, "CH",
+			StructuredAddress{"Musterstrasse", "7b", "3000", "Bern"}},
+		{CombinedAddress{"12, rue du Lac", "1200 Genève"}, "CH",
+			StructuredAddress{"rue du Lac", "12", "1200", "Genève"}},
+		{CombinedAddress{"Via Cantonale 3/5", "6900 Lugano"}, "",
+			StructuredAddress{"Via Cantonale", "3/5", "6900", "Lugano"}},
+		{CombinedAddress{"Postfach 123", "FL-9490 Vaduz"}, "LI",
+			StructuredAddress{"Postfach 123", "", "9490", "Vaduz"}},
+		{CombinedAddress{"", "10115 Berlin"}, "DE",
+			StructuredAddress{"", "", "10115", "Berlin"}},
+		{CombinedAddress{"Hauptplatz 1", "A-8010 Graz"}, "AT",
+			StructuredAddress{"Hauptplatz", "1", "8010", "Graz"}},
+	}
+	for _, item := range testdata {
+		got, err := item.input.ToStructured(item.country)
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", item.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, item.expected) {
+			t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", item.expected, got)
+		}
+	}
+}
+
+func TestCombinedToStructuredErrors(t *testing.T) {
+	var testdata = []struct {
+		input   CombinedAddress
+		country string
+		err     string
+	}{
+		{CombinedAddress{"Bahnhofstrasse 1", ""}, "CH",
+			"Cannot split post code and town: "},
+		{CombinedAddress{"Bahnhofstrasse 1", "Zürich"}, "CH",
+			"Cannot split post code and town: Zürich"},
+		{CombinedAddress{"Bahnhofstrasse 1", "80010 Zürich"}, "CH",
+			"Invalid post code for country CH: 80010 Zürich"},
+		{CombinedAddress{"Bahnhofstrasse 1", "Bad Ragaz"}, "",
+			"Cannot split post code and town: Bad Ragaz"},
+	}
+	for _, item := range testdata {
+		_, err := item.input.ToStructured(item.country)
+		if err == nil || err.Error() != item.err {
+			t.Errorf("Item %v: expected error %#v, got: %v", item.input, item.err, err)
+		}
+	}
+}
+
+func TestCombinedToStructuredWarnings(t *testing.T) {
+	var testdata = []struct {
+		input    CombinedAddress
+		country  string
+		warnings []string
+	}{
+		{CombinedAddress{"Bahnhofstrasse 12a", "8001 Zürich"}, "CH", nil},
+		{CombinedAddress{"Postfach 123", "FL-9490 Vaduz"}, "LI",
+			[]string{"Post office box kept as street name: Postfach 123"}},
+		{CombinedAddress{"Im Dorf", "3000 Bern"}, "CH",
+			[]string{"No building number found: Im Dorf"}},
+		{CombinedAddress{"Route 66 12", "8000 Zürich 1"}, "CH",
+			[]string{"Street name contains digits: Route 66", "Town contains digits: Zürich 1"}},
+		{CombinedAddress{"Main Street 5", "1010 Wien"}, "",
+			[]string{`Unknown post code format for country "": 1010 Wien`}},
+	}
+	for i, item := range testdata {
+		_, warnings, err := item.input.ToStructuredWithWarnings(item.country)
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(warnings, item.warnings) {
+			t.Errorf("Item %v: expected %q, got: %q", i, item.warnings, warnings)
+		}
+	}
+}
+
+func TestStructureAddresses(t *testing.T) {
+	p := minimalCorrectPayload
+	p.Creditor.Address = CombinedAddress{"Im Dorf", "3000 Bern"}
+	p.UltimateDebtor = Entity{Name: "Pia Rutschmann",
+		Address: StructuredAddress{"Marktgasse", "28", "9400", "Rorschach"}, CountryCode: "CH"}
+	converted, warnings, err := p.StructureAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(converted.Creditor.Address, StructuredAddress{"Im Dorf", "", "3000", "Bern"}) ||
+		converted.UltimateDebtor.Address != p.UltimateDebtor.Address {
+		t.Errorf("Unexpected addresses: %v %v", converted.Creditor.Address, converted.UltimateDebtor.Address)
+	}
+	if len(warnings) != 1 || warnings[0] != "Creditor: No building number found: Im Dorf" {
+		t.Errorf("Unexpected warnings: %q", warnings)
+	}
+	converted.Version = SpecVersion23
+	if err := converted.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	p.Creditor.Address = CombinedAddress{"Im Dorf", "Bern"}
+	if _, _, err := p.StructureAddresses(); err == nil || err.Error() != "Creditor: Cannot split post code and town: Bern" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	var testdata = []struct {
+		lines    []string
+		country  string
+		expected StructuredAddress
+	}{
+		{[]string{"Robert Schneider AG", "Rue du Lac 1268", "2501 Biel"}, "CH",
+			StructuredAddress{"Rue du Lac", "1268", "2501", "Biel"}},
+		{[]string{"Pia Rutschmann", "c/o Muster AG", "Marktgasse 28", "  9400  Rorschach ", "Schweiz"}, "ch",
+			StructuredAddress{"Marktgasse", "28", "9400", "Rorschach"}},
+		{[]string{"Muster GmbH", "Musterstraße 12a", "Hinterhaus", "D-10115 Berlin"}, "DE",
+			StructuredAddress{"Musterstraße", "12a", "10115", "Berlin"}},
+		{[]string{"M. Dupont", "12 rue de la Paix", "75002 Paris"}, "FR",
+			StructuredAddress{"rue de la Paix", "12", "75002", "Paris"}},
+		{[]string{"Société Dupont", "BP 45", "69001 Lyon"}, "FR",
+			StructuredAddress{"BP 45", "", "69001", "Lyon"}},
+		{[]string{"Gemeinde Vaduz", "Im Städtle", "FL-9490 Vaduz"}, "LI",
+			StructuredAddress{"Im Städtle", "", "9490", "Vaduz"}},
+		{[]string{"9490 Vaduz"}, "LI",
+			StructuredAddress{"", "", "9490", "Vaduz"}},
+	}
+	for i, item := range testdata {
+		got, err := ParseAddress(item.lines, item.country)
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, item.expected) {
+			t.Errorf("Item %v: expected %#v, got: %#v", i, item.expected, got)
+		}
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	var testdata = []struct {
+		lines   []string
+		country string
+		err     string
+	}{
+		{[]string{"Main Street 5", "1010 Wien"}, "AT", "Unsupported country for address parsing: AT"},
+		{[]string{"Bahnhofstrasse 1", "Zürich"}, "CH", "No post code and town found: Bahnhofstrasse 1, Zürich"},
+		{[]string{"Musterstraße 12", "1011 Berlin"}, "DE", "No post code and town found: Musterstraße 12, 1011 Berlin"},
+	}
+	for i, item := range testdata {
+		_, err := ParseAddress(item.lines, item.country)
+		if err == nil || err.Error() != item.err {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, item.err, err)
+		}
+	}
+}
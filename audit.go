@@ -0,0 +1,112 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"sync"
+	"time"
+)
+
+// AuditRecord proves what was rendered for one bill: the payload exactly as
+// encoded in the QR code, a hash of the render options, and a fingerprint
+// of the output document that contains the bill.
+type AuditRecord struct {
+	Time time.Time
+
+	// Page is the position of the bill in the output document.
+	Page int
+
+	// Payload is the serialized payload, i.e. the content of the QR code.
+	Payload string
+
+	// Options is the hex encoded SHA-256 hash of the render options, as
+	// returned by RenderOptions.Hash.
+	Options string
+
+	// Output is the hex encoded SHA-256 hash of the output document.
+	Output string
+}
+
+// AuditSink stores audit records. Implementations must be safe for
+// concurrent use.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// Hash returns the hex encoded SHA-256 hash of all options that affect the
+// output, with the language that is actually used for rendering. Of a
+// pre-rendered QR image, only its use is part of the hash, since it must
+// match the payload anyway. Options that are not set leave the hash of
+// earlier releases unchanged.
+func (opts RenderOptions) Hash() string {
+	b, _ := json.Marshal(struct {
+		Language   Language
+		Separator  Separator
+		Draft      bool
+		Layout     Layout
+		QRImage    bool `json:",omitempty"`
+		VectorQR   bool `json:",omitempty"`
+		Preprinted bool `json:",omitempty"`
+	}{opts.language(), opts.Separator, opts.Draft, opts.Layout,
+		opts.QRImage != nil, opts.VectorQR, opts.Preprinted})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// newAuditRecord returns the record for data without output fingerprint.
+func newAuditRecord(data Payload, opts RenderOptions, page int) (AuditRecord, error) {
+	var payload bytes.Buffer
+	if err := data.Serialize(&payload); err != nil {
+		return AuditRecord{}, err
+	}
+	return AuditRecord{
+		Time:    time.Now(),
+		Page:    page,
+		Payload: payload.String(),
+		Options: opts.Hash(),
+	}, nil
+}
+
+// fingerprint returns the hex encoded hash computed by h.
+func fingerprint(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryAudit keeps audit records in memory, e.g. for tests.
+type MemoryAudit struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+// Record appends record to the records in memory.
+func (ma *MemoryAudit) Record(ctx context.Context, record AuditRecord) error {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	ma.records = append(ma.records, record)
+	return nil
+}
+
+// Records returns a copy of all records in the order they were recorded.
+func (ma *MemoryAudit) Records() []AuditRecord {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	return append([]AuditRecord(nil), ma.records...)
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileAudit(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	records := []AuditRecord{
+		{Time: time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC), Page: 0, Payload: "SPC", Options: "a", Output: "b"},
+		{Time: time.Date(2019, 5, 1, 12, 0, 1, 0, time.UTC), Page: 1, Payload: "SPC", Options: "a", Output: "b"},
+	}
+	for _, record := range records {
+		// Each record is written by a new instance, as by separate runs.
+		if err := (&FileAudit{Path: path}).Record(ctx, record); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var actual []AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, record)
+	}
+	if !reflect.DeepEqual(records, actual) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", records, actual)
+	}
+}
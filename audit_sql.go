@@ -0,0 +1,51 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// SQLAudit keeps audit records in a database table. The default statement
+// expects a table created like this:
+//
+//	CREATE TABLE swissqr_audit (time TIMESTAMP NOT NULL, page INTEGER NOT NULL,
+//	    payload TEXT NOT NULL, options CHAR(64) NOT NULL, output CHAR(64) NOT NULL);
+type SQLAudit struct {
+	DB *sql.DB
+
+	// Insert adds a record. Default uses “?” placeholders.
+	Insert string
+}
+
+const defaultInsertAuditStatement = "INSERT INTO swissqr_audit (time, page, payload, options, output) VALUES (?, ?, ?, ?, ?)"
+
+// Record inserts record into the database.
+func (sa SQLAudit) Record(ctx context.Context, record AuditRecord) error {
+	if sa.DB == nil {
+		return errors.New("No database specified.")
+	}
+	insert := sa.Insert
+	if insert == "" {
+		insert = defaultInsertAuditStatement
+	}
+	_, err := sa.DB.ExecContext(ctx, insert, record.Time, record.Page,
+		record.Payload, record.Options, record.Output)
+	return err
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestRenderOptionsHash(t *testing.T) {
+	a := RenderOptions{Language: "de"}
+	b := RenderOptions{Language: "de", Draft: true}
+	if a.Hash() == b.Hash() {
+		t.Error("Expected different hashes for different options")
+	}
+	if a.Hash() != (RenderOptions{Language: "de"}).Hash() {
+		t.Error("Expected equal hashes for equal options")
+	}
+	if len(a.Hash()) != 64 {
+		t.Errorf("Unexpected hash: %v", a.Hash())
+	}
+}
+
+func TestRenderOptionsHashCoversAllFields(t *testing.T) {
+	// Each option that differs from base changes the output. A new field
+	// of RenderOptions must be added to Hash and here.
+	base := RenderOptions{Language: DE}
+	variants := map[string]RenderOptions{
+		"Language":         {Language: FR},
+		"FallbackLanguage": {Language: "de-CH", FallbackLanguage: FR},
+		"Separator":        {Language: DE, Separator: BorderSeparator},
+		"Draft":            {Language: DE, Draft: true},
+		"Layout":           {Language: DE, Layout: Layout{OffsetY: 5}},
+		"QRImage":          {Language: DE, QRImage: image.NewGray(image.Rect(0, 0, 1, 1))},
+		"VectorQR":         {Language: DE, VectorQR: true},
+		"Preprinted":       {Language: DE, Preprinted: true},
+	}
+	fields := reflect.TypeOf(base)
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		variant, ok := variants[name]
+		if !ok {
+			t.Errorf("Field %v: not covered by the test", name)
+		} else if variant.Hash() == base.Hash() {
+			t.Errorf("Field %v: not part of the hash", name)
+		}
+	}
+	if len(variants) != fields.NumField() {
+		t.Errorf("Expected %v fields, got %v", len(variants), fields.NumField())
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bankmaster looks up the bank of a Swiss or Liechtenstein IBAN in
+// the bank master data published by SIX, e.g. to show the creditor bank
+// before a bill is paid. The data is not part of the package; download the
+// CSV version of the bank master file from SIX and load it with Load.
+package bankmaster
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/krepost/swissqr"
+)
+
+// Bank is an entry of the bank master data.
+type Bank struct {
+	// IID is the institution identification, without leading zeros.
+	IID string
+
+	BIC  string
+	Name string
+
+	PostCode string
+	Town     string
+}
+
+// Directory maps IIDs to banks.
+type Directory struct {
+	banks map[string]Bank
+}
+
+// Column headers of the bank master CSV file. The name column is found by
+// its prefix.
+const (
+	iidColumn      = "IID"
+	bicColumn      = "BIC"
+	namePrefix     = "Bank/institution name"
+	postCodeColumn = "Post code"
+	townColumn     = "Town"
+)
+
+// Load reads the bank master file in CSV format, with fields separated by
+// “;” and a header row naming the columns IID, BIC, Bank/institution name,
+// Post code and Town; other columns are ignored, and only IID and the name
+// are required. If an IID occurs more than once, the first entry is kept.
+func Load(r io.Reader) (*Directory, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read bank master header: %v", err)
+	}
+	columns := map[string]int{iidColumn: -1, bicColumn: -1, namePrefix: -1, postCodeColumn: -1, townColumn: -1}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for column, index := range columns {
+			if index < 0 && (strings.EqualFold(name, column) ||
+				column == namePrefix && strings.HasPrefix(strings.ToLower(name), strings.ToLower(namePrefix))) {
+				columns[column] = i
+			}
+		}
+	}
+	if columns[iidColumn] < 0 || columns[namePrefix] < 0 {
+		return nil, fmt.Errorf("Bank master file must have the columns IID and %v: %v", namePrefix, strings.Join(header, ";"))
+	}
+	d := &Directory{banks: make(map[string]Bank)}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+		cell := func(column string) string {
+			i := columns[column]
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		iid := normalizeIID(cell(iidColumn))
+		if iid == "" {
+			continue
+		}
+		if _, ok := d.banks[iid]; ok {
+			continue
+		}
+		d.banks[iid] = Bank{
+			IID:      iid,
+			BIC:      cell(bicColumn),
+			Name:     cell(namePrefix),
+			PostCode: cell(postCodeColumn),
+			Town:     cell(townColumn),
+		}
+	}
+	return d, nil
+}
+
+// LookupBank returns the bank of the IID in a CH or LI account, including
+// QR-IBANs, whose QR-IID is listed in the bank master data as well.
+func (d *Directory) LookupBank(a swissqr.AccountNumber) (Bank, bool) {
+	if a.IBAN == nil {
+		return Bank{}, false
+	}
+	bank, ok := d.banks[normalizeIID(a.IBAN.IID())]
+	return bank, ok
+}
+
+// Len returns the number of banks in the directory.
+func (d *Directory) Len() int {
+	return len(d.banks)
+}
+
+// normalizeIID removes leading zeros, since the bank master data lists
+// IIDs without them, while IBANs contain five digits.
+func normalizeIID(iid string) string {
+	trimmed := strings.TrimLeft(iid, "0")
+	if trimmed == "" && iid != "" {
+		return "0"
+	}
+	return trimmed
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bankmaster
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krepost/swissqr"
+)
+
+const bankMasterExample = "\ufeffGroup;IID;Valid on;IID type;Bank/institution name;Street name;Post code;Town;Country code;BIC\n" +
+	"07;790;20230101;HEADQUARTERS;Berner Kantonalbank AG;Bundesplatz 8;3011;Bern;CH;KBBECH22XXX\n" +
+	"07;30790;20230101;QR;Berner Kantonalbank AG;Bundesplatz 8;3011;Bern;CH;KBBECH22XXX\n" +
+	"08;8810;20230101;HEADQUARTERS;LGT Bank AG;Herrengasse 12;9490;Vaduz;LI;BLFLLI2XXXX\n" +
+	"08;8810;20230101;BRANCH;LGT Bank AG, Filiale;;9490;Vaduz;LI;\n"
+
+func TestLookupBank(t *testing.T) {
+	d, err := Load(strings.NewReader(bankMasterExample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d.Len() != 3 {
+		t.Errorf("Expected 3 banks, got %d", d.Len())
+	}
+	for i, test := range []struct {
+		iban string
+		name string
+		bic  string
+	}{
+		{"CH33 0079 0016 2385 2957 0", "Berner Kantonalbank AG", "KBBECH22XXX"},
+		{"CH55 3079 0016 2385 2957 0", "Berner Kantonalbank AG", "KBBECH22XXX"},
+		{"LI21 0881 0000 2324 013A A", "LGT Bank AG", "BLFLLI2XXXX"},
+		{"CH58 0079 1123 0008 8901 2", "", ""},
+	} {
+		account, err := swissqr.ParseIBAN(test.iban)
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+			continue
+		}
+		bank, ok := d.LookupBank(swissqr.AccountNumber{IBAN: account})
+		if ok != (test.name != "") || bank.Name != test.name || bank.BIC != test.bic {
+			t.Errorf("Item %v: expected %v %v, got %+v, %v", i, test.name, test.bic, bank, ok)
+		}
+	}
+	if _, ok := d.LookupBank(swissqr.AccountNumber{}); ok {
+		t.Error("Expected no bank for empty account")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for i, test := range []struct {
+		input    string
+		expected string
+	}{
+		{"", "Could not read bank master header"},
+		{"IID;BIC\n790;KBBECH22XXX\n", "Bank master file must have the columns IID and Bank/institution name"},
+	} {
+		_, err := Load(strings.NewReader(test.input))
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
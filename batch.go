@@ -0,0 +1,101 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// InvoiceJob is an invoice rendered by GenerateBatch.
+type InvoiceJob struct {
+	Payload Payload
+	Options SeqOptions
+
+	// Output receives the PDF document with the invoice; nothing is
+	// written if the job fails. Each job needs its own writer, since jobs
+	// are rendered concurrently.
+	Output io.Writer
+}
+
+// GenerateBatch renders each job into a PDF document of its own, as
+// RenderSeq renders a single payload, with concurrency jobs rendered in
+// parallel. Like RenderBatch, it reports the outcome of every job in the
+// result, whose items are in the order of the jobs. If ctx is cancelled,
+// the jobs not started yet fail with the error of ctx, which is returned
+// as well.
+func GenerateBatch(ctx context.Context, jobs []InvoiceJob, concurrency int) (BatchResult, error) {
+	if concurrency < 1 {
+		return BatchResult{}, fmt.Errorf("Concurrency must be positive: %d", concurrency)
+	}
+	items := make([]BatchItem, len(jobs))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				items[i] = runJob(ctx, jobs[i], i)
+			}
+		}()
+	}
+	for i := range jobs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	result := BatchResult{Items: items}
+	for _, item := range items {
+		if item.Err != nil {
+			result.Failed++
+		} else {
+			result.Rendered++
+		}
+	}
+	return result, ctx.Err()
+}
+
+// runJob renders the job at position i of the batch.
+func runJob(ctx context.Context, job InvoiceJob, i int) BatchItem {
+	item := BatchItem{Index: i, Page: -1}
+	if item.Err = ctx.Err(); item.Err != nil {
+		return item
+	}
+	// The document is buffered, so that nothing is written for a job
+	// that fails.
+	var buffer bytes.Buffer
+	result, err := renderSeq(ctx, slices.Values([]Payload{job.Payload}), &buffer, job.Options, false)
+	if len(result.Items) > 0 {
+		item.Warnings = result.Items[0].Warnings
+		if result.Items[0].Err != nil {
+			err = result.Items[0].Err
+		}
+	}
+	if item.Err = err; err != nil {
+		return item
+	}
+	if _, item.Err = buffer.WriteTo(job.Output); item.Err == nil {
+		item.Page = 0
+	}
+	return item
+}
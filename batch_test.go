@@ -0,0 +1,126 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	payloads := []Payload{examplePayload1, {}, examplePayload2, examplePayload3}
+	var jobs []InvoiceJob
+	var outputs []*bytes.Buffer
+	for _, data := range payloads {
+		output := new(bytes.Buffer)
+		outputs = append(outputs, output)
+		jobs = append(jobs, InvoiceJob{
+			Payload: data,
+			Options: SeqOptions{RenderOptions: RenderOptions{Language: "de"}},
+			Output:  output,
+		})
+	}
+	jobs[3].Options.Language = "xx"
+	jobs[3].Options.FallbackLanguage = "sv"
+	result, err := GenerateBatch(context.Background(), jobs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rendered != 2 || result.Failed != 2 {
+		t.Errorf("Expected 2 rendered and 2 failed, got: %+v", result)
+	}
+	for i, item := range result.Items {
+		if item.Index != i {
+			t.Errorf("Item %v: unexpected index %v", i, item.Index)
+		}
+		rendered := i == 0 || i == 2
+		if rendered != (item.Err == nil) || rendered != (item.Page == 0) {
+			t.Errorf("Item %v: unexpected result: %+v", i, item)
+		}
+		if rendered && !bytes.HasPrefix(outputs[i].Bytes(), []byte("%PDF")) {
+			t.Errorf("Item %v: expected PDF, got: %q", i, outputs[i].String())
+		}
+		if !rendered && outputs[i].Len() != 0 {
+			t.Errorf("Item %v: expected no output, got %v bytes", i, outputs[i].Len())
+		}
+	}
+	if err := result.Items[1].Err; err == nil || err.Error() != "No account specified" {
+		t.Errorf("Expected error %#v, got: %v", "No account specified", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = GenerateBatch(ctx, jobs, 4)
+	if err != context.Canceled || result.Failed != len(jobs) {
+		t.Errorf("Expected cancelled batch, got: %+v, %v", result, err)
+	}
+
+	if _, err := GenerateBatch(context.Background(), jobs, 0); err == nil ||
+		!strings.Contains(err.Error(), "Concurrency must be positive") {
+		t.Errorf("Expected error for concurrency 0, got: %v", err)
+	}
+}
+
+// contextRegistry is a registry that records the contexts it is called with.
+type contextRegistry struct {
+	MemoryRegistry
+	mu       sync.Mutex
+	contexts []context.Context
+}
+
+func (r *contextRegistry) Register(ctx context.Context, reference string) error {
+	r.mu.Lock()
+	r.contexts = append(r.contexts, ctx)
+	r.mu.Unlock()
+	return r.MemoryRegistry.Register(ctx, reference)
+}
+
+func TestGenerateBatchPassesContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "batch")
+	registry := new(contextRegistry)
+	job := InvoiceJob{
+		Payload: examplePayload2,
+		Options: SeqOptions{RenderOptions: RenderOptions{Language: "de"}, Registry: registry},
+		Output:  new(bytes.Buffer),
+	}
+	if _, err := GenerateBatch(ctx, []InvoiceJob{job}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(registry.contexts) != 1 || registry.contexts[0].Value(key{}) != "batch" {
+		t.Errorf("Expected the context of the batch, got: %v", registry.contexts)
+	}
+}
+
+func BenchmarkGenerateBatch(b *testing.B) {
+	jobs := make([]InvoiceJob, 64)
+	for i := range jobs {
+		jobs[i] = InvoiceJob{
+			Payload: examplePayload2,
+			Options: SeqOptions{RenderOptions: RenderOptions{Language: "de"}},
+			Output:  new(bytes.Buffer),
+		}
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateBatch(context.Background(), jobs, 8); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -15,22 +15,114 @@
 package swissqr
 
 import (
-	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // ValidateCharacterSet validates that s only contains characters that are
 // allowed according to the Swiss Implementation Guidelines for Customer-Bank
-// Messages Credit Transfer.
+// Messages Credit Transfer, as applied in version 2.0 of the QR-bill
+// guidelines; SpecVersion.ValidateCharacterSet validates with the character
+// set of other versions. The error is a *ValidationError with code
+// CodeInvalidCharacter.
 func ValidateCharacterSet(s string) error {
+	// Most payloads are plain ASCII, which is checked byte by byte with a
+	// table; decoding runes is only needed from the first non-ASCII byte.
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b >= utf8.RuneSelf {
+			return validateRunes(s, i)
+		}
+		if !validASCII[b] {
+			return validationError("", CodeInvalidCharacter, s, "Rune %#U not allowed in string: %v", rune(b), s)
+		}
+	}
+	return nil
+}
+
+// validateRunes validates s rune by rune, starting at byte offset start.
+func validateRunes(s string, start int) error {
+	for _, r := range s[start:] {
+		if !isValidRune(r) {
+			return validationError("", CodeInvalidCharacter, s, "Rune %#U not allowed in string: %v", r, s)
+		}
+	}
+	return nil
+}
+
+// ValidateCharacterSet validates that s only contains characters that are
+// allowed in version v of the implementation guidelines: the characters of
+// the package-level ValidateCharacterSet for version 2.0, and from version
+// 2.3 on the printable characters of the Unicode blocks Basic Latin,
+// Latin-1 Supplement and Latin Extended-A, “Ș”, “ș”, “Ț”, “ț” and “€”.
+func (v SpecVersion) ValidateCharacterSet(s string) error {
+	if v == SpecVersion20 {
+		return ValidateCharacterSet(s)
+	}
 	for _, r := range s {
-		if !strings.ContainsRune(validRunes, r) {
-			return fmt.Errorf("Rune %#U not allowed in string: %v", r, s)
+		if !isExtendedRune(r) {
+			return validationError("", CodeInvalidCharacter, s, "Rune %#U not allowed in string: %v", r, s)
 		}
 	}
 	return nil
 }
 
+// isValidRune reports whether r is permitted in version v.
+func (v SpecVersion) isValidRune(r rune) bool {
+	if v == SpecVersion20 {
+		return isValidRune(r)
+	}
+	return isExtendedRune(r)
+}
+
+// isExtendedRune reports whether r is permitted from version 2.3 on.
+func isExtendedRune(r rune) bool {
+	switch {
+	case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0x17f, r >= 0x218 && r <= 0x21b, r == '€':
+		return true
+	}
+	return false
+}
+
+// extendedRunes contains the runes permitted from version 2.3 on.
+var extendedRunes = func() string {
+	var b strings.Builder
+	for r := rune(0); r <= '€'; r++ {
+		if isExtendedRune(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}()
+
+// isValidRune reports whether r is permitted by ValidateCharacterSet.
+func isValidRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return validASCII[r]
+	}
+	return strings.ContainsRune(validRunes, r)
+}
+
+// isDigits reports whether s only contains the digits 0-9.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validASCII marks the ASCII characters contained in validRunes.
+var validASCII = func() (valid [utf8.RuneSelf]bool) {
+	for _, r := range validRunes {
+		if r < utf8.RuneSelf {
+			valid[r] = true
+		}
+	}
+	return valid
+}()
+
 // Only these runes are allowed on payment slips in Switzerland.
 var validRunes = "abcdefghijklmnopqrstuvwxyz" +
 	"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789" +
@@ -50,3 +50,64 @@ func TestInvalidCharacters(t *testing.T) {
 		}
 	}
 }
+
+func TestInvalidASCIICharacters(t *testing.T) {
+	for _, s := range []string{"a|b", "x^2", "tab\there", "Müller | Söhne"} {
+		if err := ValidateCharacterSet(s); err == nil {
+			t.Errorf("Expected error for %q; got no error.", s)
+		}
+	}
+}
+
+func BenchmarkValidateCharacterSetASCII(b *testing.B) {
+	s := "Robert Schneider AG, Rue du Lac 1268, 2501 Biel (Order 12345/2020)"
+	for i := 0; i < b.N; i++ {
+		if err := ValidateCharacterSet(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateCharacterSetLatin1(b *testing.B) {
+	s := "Pia-Maria Rutschmann-Schnyder, Grosse Marktgasse 28, 9400 Rörschach"
+	for i := 0; i < b.N; i++ {
+		if err := ValidateCharacterSet(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestExtendedCharacterSet(t *testing.T) {
+	testData := []struct {
+		s     string
+		valid bool
+	}{
+		{"Pia Rutschmann", true},
+		{"Søren Østergaard", true},
+		{"Łódź", true},
+		{"Ștefan Țiriac", true},
+		{"100 €", true},
+		{"Григорий", false},
+		{"a|b", true},
+		{"tab\there", false},
+	}
+	for i, data := range testData {
+		err := SpecVersion23.ValidateCharacterSet(data.s)
+		if (err == nil) != data.valid {
+			t.Errorf("Item %v: expected valid %v for %q, got: %v", i, data.valid, data.s, err)
+		}
+		if (SpecVersion20.ValidateCharacterSet(data.s) == nil) != (ValidateCharacterSet(data.s) == nil) {
+			t.Errorf("Item %v: expected version 2.0 to use ValidateCharacterSet", i)
+		}
+	}
+
+	p := examplePayload1
+	p.Creditor.Name = "Søren Østergaard"
+	if err := p.Validate(); err == nil {
+		t.Errorf("Expected error for version 2.0")
+	}
+	p.Version = SpecVersion23
+	if err := p.Validate(); err != nil {
+		t.Errorf("Unexpected error for version 2.3: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// CodeDeprecated reports data that is valid today but will no longer
+	// be accepted by a later version of the standard.
+	CodeDeprecated ErrorCode = "deprecated"
+
+	// CodeSameParty reports a debtor who is the creditor.
+	CodeSameParty ErrorCode = "same_party"
+
+	// CodeUnusualAmount reports an amount that is valid but unusually large.
+	CodeUnusualAmount ErrorCode = "unusual_amount"
+)
+
+// largeAmount is the amount from which Check warns of an unusually large
+// amount.
+const largeAmount = 100000.0
+
+// ValidationResult is the result of Payload.Check. Errors lists the
+// violations that make the payload unusable, as returned by ValidateAll.
+// Warnings lists data that is valid but probably a mistake, or that will
+// no longer be accepted by later versions of the standard; it is up to the
+// caller whether to proceed.
+type ValidationResult struct {
+	Errors   []error
+	Warnings []*ValidationError
+}
+
+// Valid returns true if there are no errors. Warnings are ignored.
+func (r ValidationResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Check validates the payload like ValidateAll and additionally warns of
+// combined addresses, which are retired in November 2025, of an ultimate
+// debtor with the name of the creditor, and of amounts of 100 000 or more.
+func (p Payload) Check() ValidationResult {
+	r := ValidationResult{Errors: p.ValidateAll()}
+	for _, party := range []struct {
+		field  string
+		entity Entity
+	}{
+		{"Creditor", p.Creditor},
+		{"UltimateCreditor", p.UltimateCreditor},
+		{"UltimateDebtor", p.UltimateDebtor},
+	} {
+		// Later versions reject combined addresses, so that ValidateAll
+		// reports them already.
+		if ca, ok := party.entity.Address.(CombinedAddress); ok && p.Version.allowsCombinedAddress() {
+			r.Warnings = append(r.Warnings, validationError(party.field+".Address", CodeDeprecated, ca,
+				"Combined addresses are not accepted from November 2025: %v", party.entity.Name))
+		}
+	}
+	if name := strings.TrimSpace(p.UltimateDebtor.Name); name != "" &&
+		strings.EqualFold(name, strings.TrimSpace(p.Creditor.Name)) {
+		r.Warnings = append(r.Warnings, validationError("UltimateDebtor.Name", CodeSameParty, p.UltimateDebtor.Name,
+			"Debtor is the creditor: %v", p.UltimateDebtor.Name))
+	}
+	if p.CurrencyAmount.Amount >= largeAmount {
+		r.Warnings = append(r.Warnings, validationError("CurrencyAmount.Amount", CodeUnusualAmount, p.CurrencyAmount.Amount,
+			"Unusually large amount: %.2f %v", p.CurrencyAmount.Amount, p.CurrencyAmount.Currency))
+	}
+	return r
+}
+
+// Profile selects how strictly CheckProfile treats a payload.
+type Profile int
+
+const (
+	// StandardProfile enforces the rules of Validate and warns as Check.
+	StandardProfile Profile = iota
+
+	// StrictProfile turns the warnings of Check into errors: combined
+	// addresses, a debtor who is the creditor, and amounts of 100 000 or
+	// more. Other recommendations of the implementation guidelines are not
+	// checked.
+	StrictProfile
+
+	// LenientProfile accepts what banks process in practice: characters
+	// outside the character set of Validate are only warnings, since banks
+	// replace them when processing the payment, and so are country codes
+	// given as ISO 3166-1 alpha-3 codes, which CountryCodeFromAlpha3
+	// converts. Payloads accepted only by this profile cannot be serialized
+	// unchanged; Sanitize fixes both.
+	LenientProfile
+)
+
+// String returns the name of the profile, e.g. “strict”.
+func (profile Profile) String() string {
+	switch profile {
+	case StandardProfile:
+		return "standard"
+	case StrictProfile:
+		return "strict"
+	case LenientProfile:
+		return "lenient"
+	}
+	return fmt.Sprintf("Profile(%d)", int(profile))
+}
+
+// CheckProfile checks the payload like Check with the severity of the
+// findings adapted to the given profile.
+func (p Payload) CheckProfile(profile Profile) ValidationResult {
+	r := p.Check()
+	switch profile {
+	case StandardProfile:
+	case StrictProfile:
+		for _, w := range r.Warnings {
+			r.Errors = append(r.Errors, w)
+		}
+		r.Warnings = nil
+	case LenientProfile:
+		var errs []error
+		var demoted []*ValidationError
+		for _, err := range r.Errors {
+			if ve, ok := err.(*ValidationError); ok && lenientError(ve) {
+				demoted = append(demoted, ve)
+			} else {
+				errs = append(errs, err)
+			}
+		}
+		// ValidateAll skips the rules involving several fields if any
+		// field is invalid, so they remain to be checked.
+		if len(errs) == 0 && len(demoted) > 0 {
+			errs = p.crossViolations()
+		}
+		r.Errors = errs
+		r.Warnings = append(demoted, r.Warnings...)
+	default:
+		r.Errors = append(r.Errors, validationError("", CodeUnsupported, profile, "Unknown profile: %v", profile))
+	}
+	return r
+}
+
+// lenientError reports whether LenientProfile demotes err to a warning.
+func lenientError(err *ValidationError) bool {
+	if err.Code == CodeInvalidCharacter {
+		return true
+	}
+	if strings.HasSuffix(err.Field, "CountryCode") {
+		_, convertErr := CountryCodeFromAlpha3(err.Value)
+		return convertErr == nil
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	structured := Entity{
+		Name:        "Robert Schneider AG",
+		Address:     StructuredAddress{StreetName: "Rue du Lac", BuildingNumber: "1268", PostCode: "2501", TownName: "Biel"},
+		CountryCode: "CH",
+	}
+	payload := minimalCorrectPayload
+	payload.Creditor = structured
+	if r := payload.Check(); !r.Valid() || len(r.Warnings) != 0 {
+		t.Errorf("Expected no errors and warnings, got: %v %v", r.Errors, r.Warnings)
+	}
+
+	payload.UltimateDebtor = minimalCorrectEntity
+	payload.UltimateDebtor.Name = "ROBERT SCHNEIDER AG "
+	payload.CurrencyAmount.Amount = 250000
+	r := payload.Check()
+	if !r.Valid() {
+		t.Errorf("Expected no errors, got: %v", r.Errors)
+	}
+	var fields []string
+	for _, w := range r.Warnings {
+		fields = append(fields, w.Field+" "+string(w.Code))
+	}
+	expected := "UltimateDebtor.Address deprecated, UltimateDebtor.Name same_party, CurrencyAmount.Amount unusual_amount"
+	if strings.Join(fields, ", ") != expected {
+		t.Errorf("Expected warnings %v, got: %v", expected, strings.Join(fields, ", "))
+	}
+
+	payload.CurrencyAmount.Currency = "USD"
+	if r := payload.Check(); r.Valid() || len(r.Warnings) != 3 {
+		t.Errorf("Expected an error and three warnings, got: %v %v", r.Errors, r.Warnings)
+	}
+}
+
+func TestCheckProfile(t *testing.T) {
+	payload := minimalCorrectPayload
+	payload.Creditor.Name = "Søren Østergaard"
+	var testdata = []struct {
+		profile  Profile
+		errors   int
+		warnings int
+	}{
+		{StandardProfile, 1, 1},
+		{StrictProfile, 2, 0},
+		{LenientProfile, 0, 2},
+		{Profile(7), 2, 1},
+	}
+	for i, item := range testdata {
+		r := payload.CheckProfile(item.profile)
+		if len(r.Errors) != item.errors || len(r.Warnings) != item.warnings {
+			t.Errorf("Item %v: expected %d errors and %d warnings, got: %v %v", i,
+				item.errors, item.warnings, r.Errors, r.Warnings)
+		}
+	}
+
+	// The rules involving several fields still apply to lenient checks.
+	payload.Account = NewIBANOrDie("CH4431999123000889012")
+	if r := payload.CheckProfile(LenientProfile); r.Valid() {
+		t.Errorf("Expected error for QR-IBAN without QR reference, got: %v", r.Warnings)
+	}
+
+	// Alpha-3 country codes are accepted by lenient checks only.
+	payload = minimalCorrectPayload
+	payload.Creditor.CountryCode = "CHE"
+	if r := payload.CheckProfile(StandardProfile); r.Valid() {
+		t.Errorf("Expected error for alpha-3 country code, got: %v", r.Warnings)
+	}
+	if r := payload.CheckProfile(LenientProfile); !r.Valid() || len(r.Warnings) != 2 {
+		t.Errorf("Expected two warnings, got: %v %v", r.Errors, r.Warnings)
+	}
+	payload.Creditor.CountryCode = "XYZ"
+	if r := payload.CheckProfile(LenientProfile); r.Valid() {
+		t.Errorf("Expected error for unknown country code, got: %v", r.Warnings)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/krepost/swissqr"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("decode", "[invoice.pdf | image.png | scan.txt]", stderr)
+	usage := fs.Usage
+	fs.Usage = func() {
+		usage()
+		io.WriteString(stderr, "Images can be PNG, JPEG or GIF files. In a PDF file, the QR code must be an embedded image.\n")
+	}
+	name, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	b, err := readInput(name, stdin)
+	if err != nil {
+		return err
+	}
+	text, err := qrText(b)
+	if err != nil {
+		return err
+	}
+	data, err := swissqr.Parse(text)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// qrText returns the text of the QR code in a PDF file or an image, or the
+// text itself for the output of a scanner.
+func qrText(b []byte) (string, error) {
+	if bytes.HasPrefix(b, []byte("%PDF")) {
+		return pdfQRText(b)
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		// Not an image: text from a scanner.
+		return swissqr.NormalizeScan(b, swissqr.KeyboardMatching), nil
+	}
+	return imageQRText(img)
+}
+
+// pdfQRText returns the text of the first QR code among the images embedded
+// in a PDF file, page by page. A QR code drawn as vector graphics, as with
+// the VectorQR option, is not found, since the pages are not rasterized.
+func pdfQRText(b []byte) (string, error) {
+	pages, err := api.ExtractImagesRaw(bytes.NewReader(b), nil, model.NewDefaultConfiguration())
+	if err != nil {
+		return "", fmt.Errorf("Invalid PDF file: %v", err)
+	}
+	for _, images := range pages {
+		for _, objNr := range slices.Sorted(maps.Keys(images)) {
+			img, _, err := image.Decode(images[objNr])
+			if err != nil {
+				// Not a PNG, JPEG or GIF image.
+				continue
+			}
+			if text, err := imageQRText(img); err == nil {
+				return text, nil
+			}
+		}
+	}
+	return "", errors.New("No QR code found in the images of the PDF file")
+}
+
+// imageQRText returns the text of the QR code in an image.
+func imageQRText(img image.Image) (string, error) {
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", err
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, hints)
+	if err != nil {
+		return "", fmt.Errorf("No QR code found: %v", err)
+	}
+	return result.GetText(), nil
+}
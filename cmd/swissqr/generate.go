@@ -0,0 +1,126 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/krepost/swissqr"
+)
+
+// styles maps the values of the flag -style to separators.
+var styles = map[string]swissqr.Separator{
+	"plain":    swissqr.NoSeparator,
+	"border":   swissqr.BorderSeparator,
+	"scissors": swissqr.ScissorsSeparator,
+}
+
+func runGenerate(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("generate", "[payload.json]", stderr)
+	lang := fs.String("lang", "de", "language of the invoice: de, fr, it, rm or en")
+	style := fs.String("style", "plain", "separator: plain, border or scissors")
+	draft := fs.Bool("draft", false, "render an unpayable draft")
+	slip := fs.Bool("slip", false, "create a page of the size of the invoice instead of A4")
+	format := fs.String("format", "", "output format: pdf or png; derived from -o by default")
+	output := fs.String("o", "-", "output file, or - for standard output")
+	dir := fs.String("dir", "", "directory that -o is relative to; -o may then name subdirectories, which are created")
+	dpi := fs.Int("dpi", 150, "resolution of PNG images")
+	name, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	language, err := swissqr.ParseLanguage(*lang)
+	if err != nil {
+		return err
+	}
+	separator, ok := styles[*style]
+	if !ok {
+		return fmt.Errorf("unknown style: %v", *style)
+	}
+	if *format == "" {
+		*format = "pdf"
+		if strings.EqualFold(filepath.Ext(*output), ".png") {
+			*format = "png"
+		}
+	}
+	if *format != "pdf" && *format != "png" {
+		return fmt.Errorf("unknown format: %v", *format)
+	}
+	if *dir != "" && *output == "-" {
+		return errors.New("-dir requires an output file given by -o")
+	}
+
+	b, err := readInput(name, stdin)
+	if err != nil {
+		return err
+	}
+	data, err := decodePayload(b, isYAML(name))
+	if err != nil {
+		return err
+	}
+	opts := swissqr.RenderOptions{Language: language, Separator: separator, Draft: *draft}
+
+	// The invoice is rendered before the output file is created, so that
+	// errors leave no empty file behind.
+	var buffer bytes.Buffer
+	if *format == "png" {
+		img, err := swissqr.RenderImageWithOptions(data, opts, *dpi)
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(&buffer, img); err != nil {
+			return err
+		}
+	} else {
+		generateOpts := []swissqr.Option{swissqr.WithRenderOptions(opts)}
+		if *slip {
+			generateOpts = append(generateOpts, swissqr.WithSlipOnly())
+		}
+		if err := swissqr.GeneratePDF(&buffer, data, language, generateOpts...); err != nil {
+			return err
+		}
+	}
+	if *output == "-" {
+		_, err := buffer.WriteTo(stdout)
+		return err
+	}
+	storage, name := swissqr.DirStorage{Dir: *dir}, *output
+	if *dir == "" {
+		storage.Dir, name = filepath.Dir(*output), filepath.Base(*output)
+	}
+	return writeOutput(storage, name, buffer.Bytes())
+}
+
+// writeOutput stores b under name in s.
+func writeOutput(s swissqr.Storage, name string, b []byte) error {
+	w, err := s.Create(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/krepost/swissqr"
+)
+
+// errUsage reports invalid flags or arguments; the flag package has printed
+// the usage already.
+var errUsage = errors.New("usage")
+
+// exitStatus returns the exit status for an error of a command.
+func exitStatus(err error) int {
+	if err == errUsage {
+		return 2
+	}
+	return 1
+}
+
+// newFlagSet returns a flag set for the command that prints its errors and
+// usage to stderr.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("swissqr "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		io.WriteString(stderr, "Usage: swissqr "+name+" [flags] "+args+"\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and returns the single file name argument, or
+// “-” if there is none.
+func parseFlags(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", errUsage
+	}
+	switch fs.NArg() {
+	case 0:
+		return "-", nil
+	case 1:
+		return fs.Arg(0), nil
+	}
+	fs.Usage()
+	return "", errUsage
+}
+
+// readInput reads the named file, or stdin for “-”.
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// isYAML tells whether the file name has a YAML extension.
+func isYAML(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// decodePayload decodes a payload in JSON or, if yaml is set, in the YAML
+// format of swissqr.LoadYAML. The payload is not validated yet, so that
+// validate can report all problems.
+func decodePayload(b []byte, yamlInput bool) (swissqr.Payload, error) {
+	if yamlInput {
+		return swissqr.PayloadFromYAML(b)
+	}
+	var p swissqr.Payload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return swissqr.Payload{}, err
+	}
+	return p, nil
+}
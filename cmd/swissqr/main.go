@@ -0,0 +1,84 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+// Command swissqr creates and checks Swiss QR invoices from the command
+// line.
+//
+// Usage:
+//
+//	swissqr generate [flags] payload.json
+//	swissqr validate payload.json
+//	swissqr decode invoice.pdf
+//
+// Payloads are read in the JSON representation of package swissqr, or in
+// the YAML format of swissqr.LoadYAML if the file name ends in .yaml or .yml.
+// A file name of “-” or none reads standard input. The decode command reads
+// PDF files, PNG, JPEG or GIF images, and the text of a scanner; in a PDF
+// file, the QR code must be an embedded image.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a subcommand of swissqr.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"generate", "write the invoice for a payload as PDF or PNG", runGenerate},
+	{"validate", "print the validation problems of a payload or QR text", runValidate},
+	{"decode", "print the payload of a QR code in a PDF file or an image as JSON", runDecode},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			if err := c.run(args[1:], stdin, stdout, stderr); err != nil {
+				if err != errUsage && err != errInvalid {
+					fmt.Fprintf(stderr, "swissqr %v: %v\n", c.name, err)
+				}
+				return exitStatus(err)
+			}
+			return 0
+		}
+	}
+	fmt.Fprintf(stderr, "swissqr: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: swissqr <command> [flags] [file]")
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10v %v\n", c.name, c.summary)
+	}
+}
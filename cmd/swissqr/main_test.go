@@ -0,0 +1,194 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krepost/swissqr"
+)
+
+// examplePayload is a valid payload in the JSON representation.
+func examplePayload(t *testing.T) string {
+	data := swissqr.Payload{
+		Account: swissqr.NewIBANOrDie("CH58 0079 1123 0008 8901 2"),
+		Creditor: swissqr.Entity{
+			Name: "Robert Schneider AG",
+			Address: swissqr.StructuredAddress{
+				StreetName:     "Rue du Lac",
+				BuildingNumber: "1268",
+				PostCode:       "2501",
+				TownName:       "Biel",
+			},
+			CountryCode: "CH",
+		},
+		CurrencyAmount: swissqr.PaymentAmount{Amount: 3949.75, Currency: swissqr.CHF},
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "payload.json")
+	if err := os.WriteFile(input, []byte(examplePayload(t)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var testdata = []struct {
+		args   []string
+		stdin  string
+		status int
+		prefix string
+	}{
+		{[]string{"generate", input}, "", 0, "%PDF"},
+		{[]string{"generate", "-style", "scissors", "-lang", "fr", "-slip"}, examplePayload(t), 0, "%PDF"},
+		{[]string{"generate", "-format", "png", "-dpi", "50", "-"}, examplePayload(t), 0, "\x89PNG"},
+		{[]string{"generate", "-style", "dotted", input}, "", 1, ""},
+		{[]string{"generate", "-format", "gif", input}, "", 1, ""},
+		{[]string{"generate", "-lang", "xx", input}, "", 1, ""},
+		{[]string{"generate"}, "{}", 1, ""},
+		{[]string{"generate", "-unknown"}, "", 2, ""},
+		{[]string{"generate", "a", "b"}, "", 2, ""},
+		{[]string{"unknown"}, "", 2, ""},
+		{nil, "", 2, ""},
+	}
+	for i, item := range testdata {
+		var stdout, stderr bytes.Buffer
+		status := run(item.args, strings.NewReader(item.stdin), &stdout, &stderr)
+		if status != item.status {
+			t.Errorf("Item %v: expected status %v, got: %v %v", i, item.status, status, stderr.String())
+		}
+		if !strings.HasPrefix(stdout.String(), item.prefix) {
+			t.Errorf("Item %v: expected output %q, got: %.10q", i, item.prefix, stdout.String())
+		}
+		if status != 0 && stderr.Len() == 0 {
+			t.Errorf("Item %v: expected error message", i)
+		}
+	}
+
+	output := filepath.Join(dir, "invoice.png")
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"generate", "-o", output, "-dpi", "50", input}, nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("Unexpected status %v: %v", status, stderr.String())
+	}
+	if b, err := os.ReadFile(output); err != nil || !bytes.HasPrefix(b, []byte("\x89PNG")) {
+		t.Errorf("Expected PNG file, got: %.10q, %v", b, err)
+	}
+
+	if status := run([]string{"generate", "-dir", dir, "-o", "2019/05/3139.pdf", input}, nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("Unexpected status %v: %v", status, stderr.String())
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "2019", "05", "3139.pdf")); err != nil || !bytes.HasPrefix(b, []byte("%PDF")) {
+		t.Errorf("Expected PDF file, got: %.10q, %v", b, err)
+	}
+	for i, args := range [][]string{{"-dir", dir, "-o", "../3139.pdf"}, {"-dir", dir}} {
+		stderr.Reset()
+		if status := run(append(append([]string{"generate"}, args...), input), nil, &stdout, &stderr); status != 1 {
+			t.Errorf("Item %v: expected status 1, got: %v", i, status)
+		}
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	for _, test := range []struct {
+		input     string
+		yamlInput bool
+	}{
+		{examplePayload(t), false},
+		{"creditor:\n  name: Robert Schneider AG\n  town: Biel\n", true},
+	} {
+		p, err := decodePayload([]byte(test.input), test.yamlInput)
+		if err != nil {
+			t.Errorf("YAML %v: unexpected error: %v", test.yamlInput, err)
+		} else if p.Creditor.Name != "Robert Schneider AG" {
+			t.Errorf("YAML %v: unexpected payload: %+v", test.yamlInput, p)
+		}
+	}
+	if !isYAML("payload.YML") || isYAML("payload.json") {
+		t.Error("Unexpected YAML detection")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := `{"Account": "CH58 0079 1123 0008 8901 2", "Creditor": {"Name": "", "CountryCode": "XX"},
+		"CurrencyAmount": {"Amount": -1, "Currency": "CHF"}}`
+	qrText := "SPC\n0200\n1\nCH5800791123000889012\nS\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+		"\n\n\n\n\n\n\n3949.75\nCHF\n\n\n\n\n\n\n\nNON\n\n\nEPD\n"
+	var testdata = []struct {
+		stdin  string
+		status int
+		output []string
+	}{
+		{examplePayload(t), 0, []string{"Valid."}},
+		{invalid, 1, []string{
+			"Creditor (CdtrInf/Cdtr): Name must be specified.",
+			"CurrencyAmount (CcyAmt): Amount cannot be negative: -1",
+		}},
+		{qrText, 0, []string{"Valid."}},
+		{strings.Replace(qrText, "CHF", "USD", 1), 1, []string{"QR text (QR code data): Currency must be CHF or EUR: USD"}},
+		{"[", 1, nil},
+	}
+	for i, item := range testdata {
+		var stdout, stderr bytes.Buffer
+		status := run([]string{"validate"}, strings.NewReader(item.stdin), &stdout, &stderr)
+		if status != item.status {
+			t.Errorf("Item %v: expected status %v, got: %v %v", i, item.status, status, stderr.String())
+		}
+		var output []string
+		if stdout.Len() > 0 {
+			output = strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+		}
+		if strings.Join(output, "\n") != strings.Join(item.output, "\n") {
+			t.Errorf("Item %v: expected %q, got: %q", i, item.output, output)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	qrText := "SPC\r\n0200\r\n1\r\nCH5800791123000889012\r\nS\r\nRobert Schneider AG\r\nRue du Lac\r\n1268\r\n2501\r\nBiel\r\nCH\r\n" +
+		"\r\n\r\n\r\n\r\n\r\n\r\n\r\n3949.75\r\nCHF\r\n\r\n\r\n\r\n\r\n\r\n\r\n\r\nNON\r\n\r\n\r\nEPD\r\n"
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"decode"}, strings.NewReader(qrText), &stdout, &stderr); status != 0 {
+		t.Fatalf("Unexpected status %v: %v", status, stderr.String())
+	}
+	var p swissqr.Payload
+	if err := json.Unmarshal(stdout.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Creditor.Name != "Robert Schneider AG" || p.CurrencyAmount.Amount != 3949.75 {
+		t.Errorf("Unexpected payload: %v", stdout.String())
+	}
+
+	for i, stdin := range []string{"%PDF-1.4\n", "SPC\r\n0100\r\n"} {
+		stdout.Reset()
+		stderr.Reset()
+		if status := run([]string{"decode"}, strings.NewReader(stdin), &stdout, &stderr); status != 1 {
+			t.Errorf("Item %v: expected status 1, got: %v", i, status)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("Item %v: expected error message", i)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/krepost/swissqr"
+)
+
+// errInvalid reports that problems were found; they have been printed.
+var errInvalid = errors.New("invalid payload")
+
+// fieldCheck validates one field of a payload. Element is the name of the
+// data element in the Swiss Implementation Guidelines QR-bill.
+type fieldCheck struct {
+	field   string
+	element string
+	check   func(p swissqr.Payload) error
+}
+
+var fieldChecks = []fieldCheck{
+	{"Account", "CdtrInf/IBAN", func(p swissqr.Payload) error {
+		return p.Account.Validate()
+	}},
+	{"Creditor", "CdtrInf/Cdtr", func(p swissqr.Payload) error {
+		return p.Creditor.ValidateAs(swissqr.CreditorRole)
+	}},
+	{"UltimateCreditor", "UltmtCdtr", func(p swissqr.Payload) error {
+		return p.UltimateCreditor.ValidateAs(swissqr.UltimateCreditorRole)
+	}},
+	{"CurrencyAmount", "CcyAmt", func(p swissqr.Payload) error {
+		return p.CurrencyAmount.Validate()
+	}},
+	{"UltimateDebtor", "UltmtDbtr", func(p swissqr.Payload) error {
+		return p.UltimateDebtor.ValidateAs(swissqr.UltimateDebtorRole)
+	}},
+	{"Reference", "RmtInf/Ref", func(p swissqr.Payload) error {
+		return p.Reference.Validate()
+	}},
+	{"AdditionalInformation", "RmtInf/AddInf", func(p swissqr.Payload) error {
+		return p.AdditionalInformation.Validate()
+	}},
+	{"AlternativeProcedureParameters", "AltPmtInf", func(p swissqr.Payload) error {
+		return p.AlternativeProcedureParameters.Validate()
+	}},
+}
+
+// problem is a validation problem of a field.
+type problem struct {
+	field   string
+	element string
+	err     error
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%v (%v): %v", p.field, p.element, p.err)
+}
+
+// validatePayload returns the problems of all fields. The rules that
+// involve several fields, such as the reference type required by a
+// QR-IBAN, are only checked if all fields are valid on their own.
+func validatePayload(p swissqr.Payload) []problem {
+	var problems []problem
+	for _, c := range fieldChecks {
+		if err := c.check(p); err != nil {
+			problems = append(problems, problem{c.field, c.element, err})
+		}
+	}
+	if len(problems) == 0 {
+		if err := p.Validate(); err != nil {
+			problems = append(problems, problem{"Account, Reference", "CdtrInf/IBAN, RmtInf/Tp", err})
+		}
+	}
+	return problems
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("validate", "[payload.json | qr.txt]", stderr)
+	name, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	b, err := readInput(name, stdin)
+	if err != nil {
+		return err
+	}
+	var problems []problem
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("SPC")) {
+		// Raw text of a QR code, which Parse validates as a whole.
+		if _, err := swissqr.Parse(string(bytes.TrimSpace(b))); err != nil {
+			problems = append(problems, problem{"QR text", "QR code data", err})
+		}
+	} else {
+		data, err := decodePayload(b, isYAML(name))
+		if err != nil {
+			return err
+		}
+		problems = validatePayload(data)
+	}
+	if len(problems) == 0 {
+		fmt.Fprintln(stdout, "Valid.")
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Fprintln(stdout, p)
+	}
+	return errInvalid
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+// TestConcurrentUse exercises the package from several goroutines at once.
+// Run it with “go test -race” to detect shared mutable state.
+func TestConcurrentUse(t *testing.T) {
+	payloads := []Payload{examplePayload1, examplePayload2, examplePayload3}
+	languages := []Language{DE, FR, IT, EN}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := payloads[i%len(payloads)]
+			language := languages[i%len(languages)]
+			var buffer bytes.Buffer
+			if err := data.Serialize(&buffer); err != nil {
+				t.Error(err)
+			}
+			if _, err := CreateQR(data); err != nil {
+				t.Error(err)
+			}
+			if err := WriteQRSVG(ioutil.Discard, data); err != nil {
+				t.Error(err)
+			}
+			// Each goroutine needs its own document.
+			doc := pdf.New()
+			canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+			if err := DrawInvoiceWithBorder(canvas, data, language); err != nil {
+				t.Error(err)
+			}
+			canvas.Close()
+			if err := doc.Encode(ioutil.Discard); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// CountryCodeFromAlpha3 converts an ISO 3166-1 alpha-3 country code such as
+// “CHE” to the alpha-2 code “CH” required by the Swiss QR standard. Lower
+// case and surrounding whitespace are accepted as by NormalizeCountryCode.
+func CountryCodeFromAlpha3(code string) (string, error) {
+	alpha3 := NormalizeCountryCode(code)
+	if len(alpha3) != 3 {
+		return "", fmt.Errorf("Invalid alpha-3 country code: %v", code)
+	}
+	// ParseRegion also accepts alpha-2 codes and numeric codes, which map
+	// to a different alpha-3 code.
+	region, err := language.ParseRegion(alpha3)
+	if err != nil || region.ISO3() != alpha3 || !countryCodes[region.String()] {
+		return "", fmt.Errorf("Unknown alpha-3 country code: %v", code)
+	}
+	return region.String(), nil
+}
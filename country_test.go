@@ -0,0 +1,47 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "testing"
+
+func TestCountryCodeFromAlpha3(t *testing.T) {
+	var testdata = []struct {
+		input    string
+		expected string
+		message  string
+	}{
+		{"CHE", "CH", ""},
+		{"LIE", "LI", ""},
+		{"deu", "DE", ""},
+		{" AUT ", "AT", ""},
+		{"GBR", "GB", ""},
+		{"CH", "", "Invalid alpha-3 country code: CH"},
+		{"756", "", "Unknown alpha-3 country code: 756"},
+		{"ABC", "", "Unknown alpha-3 country code: ABC"},
+		{"ZZZ", "", "Unknown alpha-3 country code: ZZZ"},
+	}
+	for i, item := range testdata {
+		code, err := CountryCodeFromAlpha3(item.input)
+		if item.message == "" && err != nil {
+			t.Errorf("Item %v: expected no error, got: %v", i, err)
+		}
+		if item.message != "" && (err == nil || err.Error() != item.message) {
+			t.Errorf("Item %v: expected error %q, got: %v", i, item.message, err)
+		}
+		if code != item.expected {
+			t.Errorf("Item %v: expected %q, got: %q", i, item.expected, code)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ColumnMapping names the columns of a CSV file read by LoadPayloadsCSV.
+// Each field holds the header of the column with the data of the field; an
+// empty header means that the file has no such column. Headers are matched
+// without regard to case and surrounding spaces.
+type ColumnMapping struct {
+	// Comma is the field delimiter; zero means “,”. Spreadsheets with
+	// German or French settings export CSV files with “;”.
+	Comma rune
+
+	Account  string
+	Creditor EntityColumns
+
+	// Amount may contain “'” as thousands separator; an empty amount
+	// leaves a box for the amount. Currency defaults to CHF.
+	Amount   string
+	Currency string
+
+	Debtor EntityColumns
+
+	// ReferenceType is QRR, SCOR or NON; if empty, the type is derived
+	// from the reference, as in the YAML format of LoadYAML.
+	ReferenceType string
+	Reference     string
+
+	Message string
+
+	// BillInformation holds the bill information in the format of the QR
+	// code, “//S1/10/…”.
+	BillInformation string
+}
+
+// EntityColumns names the columns of the creditor or the debtor. The
+// address is combined if the address lines are filled in, unless the
+// column AddressType says “structured” or “combined”.
+type EntityColumns struct {
+	Name         string
+	AddressType  string
+	Street       string
+	Building     string
+	PostCode     string
+	Town         string
+	AddressLine1 string
+	AddressLine2 string
+	Country      string
+}
+
+// DefaultColumnMapping uses the keys of the YAML format as headers, e.g.
+// “creditor.name” or “reference”.
+var DefaultColumnMapping = ColumnMapping{
+	Account:         "account",
+	Creditor:        entityColumns("creditor."),
+	Amount:          "amount",
+	Currency:        "currency",
+	Debtor:          entityColumns("debtor."),
+	ReferenceType:   "reference_type",
+	Reference:       "reference",
+	Message:         "message",
+	BillInformation: "bill",
+}
+
+func entityColumns(prefix string) EntityColumns {
+	return EntityColumns{
+		Name:         prefix + "name",
+		AddressType:  prefix + "address_type",
+		Street:       prefix + "street",
+		Building:     prefix + "building",
+		PostCode:     prefix + "postcode",
+		Town:         prefix + "town",
+		AddressLine1: prefix + "line1",
+		AddressLine2: prefix + "line2",
+		Country:      prefix + "country",
+	}
+}
+
+// LoadPayloadsCSV reads a CSV file with a header row and one invoice per
+// row, e.g. exported from a spreadsheet, and converts each row to a payload.
+// Columns that are not part of the mapping are ignored, but every column of
+// the mapping must be present. Each payload is validated; the payloads of
+// valid rows are returned in the order of the file, and each invalid row
+// yields an error that gives its row number, counting the header as row 1.
+func LoadPayloadsCSV(r io.Reader, mapping ColumnMapping) ([]Payload, []error) {
+	reader := csv.NewReader(r)
+	if mapping.Comma != 0 {
+		reader.Comma = mapping.Comma
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []error{fmt.Errorf("Could not read CSV header: %v", err)}
+	}
+	columns, err := mapping.columns(header)
+	if err != nil {
+		return nil, []error{err}
+	}
+	var payloads []Payload
+	var errs []error
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Row %d: %v", row, err))
+			break
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		p, err := columns.payload(record)
+		if err == nil {
+			err = p.Validate()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Row %d: %v", row, err))
+			continue
+		}
+		payloads = append(payloads, p)
+	}
+	return payloads, errs
+}
+
+// csvColumns holds the index of each mapped column, or -1.
+type csvColumns struct {
+	account, amount, currency, referenceType, reference, message, bill int
+	creditor, debtor                                                   entityIndices
+}
+
+type entityIndices struct {
+	name, addressType, street, building, postCode, town, line1, line2, country int
+}
+
+func (m ColumnMapping) columns(header []string) (csvColumns, error) {
+	index := make(map[string]int)
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheets may start the file with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	lookup := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			missing = append(missing, name)
+			return -1
+		}
+		return i
+	}
+	entity := func(e EntityColumns) entityIndices {
+		return entityIndices{
+			name:        lookup(e.Name),
+			addressType: lookup(e.AddressType),
+			street:      lookup(e.Street),
+			building:    lookup(e.Building),
+			postCode:    lookup(e.PostCode),
+			town:        lookup(e.Town),
+			line1:       lookup(e.AddressLine1),
+			line2:       lookup(e.AddressLine2),
+			country:     lookup(e.Country),
+		}
+	}
+	c := csvColumns{
+		account:       lookup(m.Account),
+		creditor:      entity(m.Creditor),
+		amount:        lookup(m.Amount),
+		currency:      lookup(m.Currency),
+		debtor:        entity(m.Debtor),
+		referenceType: lookup(m.ReferenceType),
+		reference:     lookup(m.Reference),
+		message:       lookup(m.Message),
+		bill:          lookup(m.BillInformation),
+	}
+	if len(missing) > 0 {
+		return csvColumns{}, fmt.Errorf("Missing CSV columns: %v", strings.Join(missing, ", "))
+	}
+	return c, nil
+}
+
+func (c csvColumns) payload(record []string) (Payload, error) {
+	cell := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	var p Payload
+	var err error
+	if p.Account, err = parseAccount(cell(c.account)); err != nil {
+		return Payload{}, err
+	}
+	if p.Creditor, err = c.creditor.entity(cell); err != nil {
+		return Payload{}, err
+	}
+	currency := cell(c.currency)
+	if currency == "" {
+		currency = CHF
+	}
+	amount := strings.NewReplacer("'", "", "’", "", " ", "").Replace(cell(c.amount))
+	if p.CurrencyAmount, err = parseAmount(amount, currency); err != nil {
+		return Payload{}, err
+	}
+	if p.UltimateDebtor, err = c.debtor.entity(cell); err != nil {
+		return Payload{}, err
+	}
+	if p.Reference, err = newReference(cell(c.reference), cell(c.referenceType)); err != nil {
+		return Payload{}, err
+	}
+	p.AdditionalInformation.UnstructuredMessage = cell(c.message)
+	if err := parsePaymentBillInformation(&p.AdditionalInformation, cell(c.bill)); err != nil {
+		return Payload{}, err
+	}
+	return p, nil
+}
+
+func (e entityIndices) entity(cell func(int) string) (Entity, error) {
+	name, country := cell(e.name), cell(e.country)
+	structured := StructuredAddress{
+		StreetName:     cell(e.street),
+		BuildingNumber: cell(e.building),
+		PostCode:       cell(e.postCode),
+		TownName:       cell(e.town),
+	}
+	combined := CombinedAddress{AddressLine1: cell(e.line1), AddressLine2: cell(e.line2)}
+	if name == "" && country == "" && structured == (StructuredAddress{}) && combined == (CombinedAddress{}) {
+		return Entity{}, nil
+	}
+	return newEntity(name, country, cell(e.addressType), structured, combined)
+}
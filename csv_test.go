@@ -0,0 +1,83 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadPayloadsCSV(t *testing.T) {
+	input := "\ufeffaccount,creditor.name,creditor.street,creditor.building,creditor.postcode,creditor.town,creditor.country," +
+		"amount,currency,debtor.name,debtor.line1,debtor.line2,debtor.country,reference,message,bill,notes\n" +
+		"CH44 3199 9123 0008 8901 2,Robert Schneider AG,Rue du Lac,1268,2501,Biel,CH," +
+		"1'949.75,CHF,Pia Rutschmann,Marktgasse 28,9400 Rorschach,CH,21 00000 00003 13947 14300 09017,Auftrag,//S1/10/10201409,first\n" +
+		"CH5800791123000889012,Robert Schneider AG,Rue du Lac,1268,2501,Biel,CH,,,,,,,,,,\n" +
+		",,,,,,,,,,,,,,,,\n" +
+		"CH5800791123000889012,Robert Schneider AG,Rue du Lac,1268,2501,Biel,CH,many,CHF,,,,,,,,\n" +
+		"CH5800791123000889012,,,,,,,10,CHF,,,,,,,,\n"
+	mapping := DefaultColumnMapping
+	mapping.Creditor.AddressType = ""
+	mapping.Creditor.AddressLine1 = ""
+	mapping.Creditor.AddressLine2 = ""
+	mapping.Debtor.AddressType = ""
+	mapping.Debtor.Street = ""
+	mapping.Debtor.Building = ""
+	mapping.Debtor.PostCode = ""
+	mapping.Debtor.Town = ""
+	mapping.ReferenceType = ""
+	payloads, errs := LoadPayloadsCSV(strings.NewReader(input), mapping)
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 payloads, got %d: %v", len(payloads), errs)
+	}
+	if amount := payloads[0].CurrencyAmount.Amount; amount != 1949.75 {
+		t.Errorf("Expected amount 1949.75, got %v", amount)
+	}
+	if _, ok := payloads[0].UltimateDebtor.Address.(CombinedAddress); !ok {
+		t.Errorf("Expected combined address, got %T", payloads[0].UltimateDebtor.Address)
+	}
+	if payloads[0].Reference.Number == nil {
+		t.Error("Expected QR reference")
+	}
+	if payloads[1].CurrencyAmount.Mode != AmountBox || payloads[1].CurrencyAmount.Currency != CHF {
+		t.Errorf("Expected amount box in CHF, got %+v", payloads[1].CurrencyAmount)
+	}
+	expected := []string{"Row 5: Invalid amount: many", "Row 6: "}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got: %v", len(expected), errs)
+	}
+	for i, err := range errs {
+		if !strings.HasPrefix(err.Error(), expected[i]) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, expected[i], err)
+		}
+	}
+}
+
+func TestLoadPayloadsCSVErrors(t *testing.T) {
+	for i, test := range []struct {
+		input    string
+		mapping  ColumnMapping
+		expected string
+	}{
+		{"", DefaultColumnMapping, "Could not read CSV header"},
+		{"account,amount\n", DefaultColumnMapping, "Missing CSV columns: creditor.name"},
+		{"IBAN;Betrag\nCH5800791123000889012;x\n", ColumnMapping{Comma: ';', Account: "iban", Amount: "Betrag"}, "Row 2: Invalid amount: x"},
+	} {
+		_, errs := LoadPayloadsCSV(strings.NewReader(test.input), test.mapping)
+		if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, errs)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "image"
+
+// The functions in this file take the language as a string, as the entry
+// points did before the Language type was introduced, so that callers that
+// keep the language in a string variable can upgrade step by step. All
+// other functions take a Language; convert a string s with Language(s).
+
+// RenderImageString is like RenderImage with the language given as a
+// string.
+//
+// Deprecated: Use RenderImage with Language(s).
+func RenderImageString(data Payload, s string, dpi int) (image.Image, error) {
+	return RenderImage(data, Language(s), dpi)
+}
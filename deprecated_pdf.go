@@ -0,0 +1,42 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"io"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+// The functions in this file take the language as a string, like the
+// function in deprecated.go.
+
+// DrawInvoiceString is like DrawInvoice with the language given as a
+// string.
+//
+// Deprecated: Use DrawInvoice with Language(s).
+func DrawInvoiceString(canvas *pdf.Canvas, data Payload, s string) error {
+	return DrawInvoice(canvas, data, Language(s))
+}
+
+// GeneratePDFString is like GeneratePDF with the language given as a
+// string.
+//
+// Deprecated: Use GeneratePDF with Language(s).
+func GeneratePDFString(w io.Writer, data Payload, s string, opts ...Option) error {
+	return GeneratePDF(w, data, Language(s), opts...)
+}
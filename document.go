@@ -0,0 +1,217 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// LineItem is one position of an invoice. Prices are net, i.e.,
+// excluding VAT.
+type LineItem struct {
+	Description string
+	Quantity    float64
+	UnitPrice   float64
+	VATPercent  float64
+}
+
+// InvoiceDocument combines the line items of an invoice with the payload of
+// its QR bill. The amount of the QR bill is always computed from the line
+// items, so that the printed table and the QR code cannot disagree.
+type InvoiceDocument struct {
+	Items []LineItem
+
+	// VATNumber is the UID of the creditor, e.g. “CHE-106.017.086 MWST”.
+	// Only the digits are used in the bill information.
+	VATNumber string
+
+	// Bill contains all QR bill data except the amount and the VAT
+	// details of the structured bill information.
+	Bill Payload
+}
+
+// Net returns the net amount of a line item, rounded to 0.01.
+func (li LineItem) Net() float64 {
+	return roundTo(li.Quantity*li.UnitPrice, 0.01)
+}
+
+// Net returns the sum of all line items, excluding VAT.
+func (d InvoiceDocument) Net() float64 {
+	sum := 0.0
+	for _, item := range d.Items {
+		sum += item.Net()
+	}
+	return roundTo(sum, 0.01)
+}
+
+// NetByRate returns the net amount per VAT rate.
+func (d InvoiceDocument) NetByRate() map[float64]float64 {
+	net := map[float64]float64{}
+	for _, item := range d.Items {
+		net[item.VATPercent] = roundTo(net[item.VATPercent]+item.Net(), 0.01)
+	}
+	return net
+}
+
+// VATByRate returns the VAT amount per rate. VAT is computed on the net
+// sum of each rate and rounded to 0.01. Rates without VAT are omitted.
+func (d InvoiceDocument) VATByRate() map[float64]float64 {
+	vat := map[float64]float64{}
+	for rate, sum := range d.NetByRate() {
+		if rate != 0 {
+			vat[rate] = roundTo(sum*rate/100.0, 0.01)
+		}
+	}
+	return vat
+}
+
+// VAT returns the total VAT amount.
+func (d InvoiceDocument) VAT() float64 {
+	sum := 0.0
+	for _, amount := range d.VATByRate() {
+		sum += amount
+	}
+	return roundTo(sum, 0.01)
+}
+
+// Total returns the amount payable, including VAT. Amounts in CHF are
+// rounded to 0.05 as is customary in Switzerland; other currencies are
+// rounded to 0.01.
+func (d InvoiceDocument) Total() float64 {
+	precision := 0.01
+	if d.Bill.CurrencyAmount.Currency == CHF {
+		precision = 0.05
+	}
+	return roundTo(d.Net()+d.VAT(), precision)
+}
+
+// Payload returns the QR bill payload with the amount set to Total(). The
+// VAT number and VAT rates of the structured bill information are filled in
+// from the line items: a single rate if all items have the same rate, and
+// otherwise each rate together with the net amount it applies to.
+func (d InvoiceDocument) Payload() Payload {
+	p := d.Bill
+	p.CurrencyAmount.Amount = d.Total()
+	info := &p.AdditionalInformation.StructuredMessage
+	if d.VATNumber != "" {
+		info.VATNumber = vatDigits(d.VATNumber)
+	}
+	net := d.NetByRate()
+	rates := sortedRates(net)
+	switch {
+	case len(rates) == 1 && rates[0] != 0:
+		info.VATRates = TaxRates{TaxRate{RatePercent: rates[0]}}
+	case len(rates) > 1:
+		info.VATRates = TaxRates{}
+		for _, rate := range rates {
+			info.VATRates = append(info.VATRates, TaxRate{RatePercent: rate, Amount: net[rate]})
+		}
+	}
+	return p
+}
+
+// vatDigits strips the “CHE” prefix, separators and suffix from a UID.
+func vatDigits(uid string) string {
+	digits := []rune{}
+	for _, r := range uid {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	return string(digits)
+}
+
+// sortedRates returns the keys of a map by rate in ascending order.
+func sortedRates(m map[float64]float64) []float64 {
+	rates := make([]float64, 0, len(m))
+	for rate := range m {
+		rates = append(rates, rate)
+	}
+	sort.Float64s(rates)
+	return rates
+}
+
+// Table returns the line items and totals as a table for a Letter.
+func (d InvoiceDocument) Table(language Language) (LetterTable, error) {
+	if err := checkLanguage(language); err != nil {
+		return LetterTable{}, err
+	}
+	labels, ok := documentLabels[language]
+	if !ok {
+		labels = documentLabels[EN] // Registered with RegisterLanguage.
+	}
+	format := displayFormat(language)
+	table := LetterTable{
+		Header: []string{labels.description, labels.quantity, labels.unitPrice, labels.amount},
+	}
+	for _, item := range d.Items {
+		table.Rows = append(table.Rows, []string{
+			item.Description,
+			fmt.Sprintf("%g", item.Quantity),
+			format.amount(item.UnitPrice),
+			format.amount(item.Net()),
+		})
+	}
+	table.Totals = append(table.Totals,
+		[]string{labels.subtotal, "", "", format.amount(d.Net())})
+	vat := d.VATByRate()
+	for _, rate := range sortedRates(vat) {
+		table.Totals = append(table.Totals, []string{
+			fmt.Sprintf("%v %g%%", labels.vat, rate), "", "", format.amount(vat[rate]),
+		})
+	}
+	total := d.Total()
+	if rounding := roundTo(total-d.Net()-d.VAT(), 0.01); rounding != 0 {
+		table.Totals = append(table.Totals,
+			[]string{labels.rounding, "", "", format.amount(rounding)})
+	}
+	table.Totals = append(table.Totals, []string{
+		labels.total + " " + d.Bill.CurrencyAmount.Currency, "", "", format.amount(total),
+	})
+	return table, nil
+}
+
+// Letter returns a copy of letter whose table and QR bill are taken from
+// the invoice document.
+func (d InvoiceDocument) Letter(letter Letter, language Language) (Letter, error) {
+	table, err := d.Table(language)
+	if err != nil {
+		return Letter{}, err
+	}
+	letter.Table = table
+	letter.Bill = d.Payload()
+	return letter, nil
+}
+
+// roundTo rounds x to the nearest multiple of precision.
+func roundTo(x float64, precision float64) float64 {
+	steps := math.Round(x / precision)
+	// Round again to two decimals to remove representation noise.
+	return math.Round(steps*precision*100) / 100
+}
+
+// documentLabels contains the localized labels of the line item table.
+var documentLabels = map[Language]struct {
+	description, quantity, unitPrice, amount string
+	subtotal, vat, rounding, total           string
+}{
+	DE: {"Beschreibung", "Menge", "Preis", "Betrag", "Zwischensumme", "MWST", "Rundung", "Total"},
+	FR: {"Description", "Quantité", "Prix", "Montant", "Sous-total", "TVA", "Arrondi", "Total"},
+	IT: {"Descrizione", "Quantità", "Prezzo", "Importo", "Subtotale", "IVA", "Arrotondamento", "Totale"},
+	EN: {"Description", "Quantity", "Price", "Amount", "Subtotal", "VAT", "Rounding", "Total"},
+	RM: {"Descripziun", "Quantitad", "Pretsch", "Import", "Subtotal", "TPV", "Arrundaziun", "Total"},
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"reflect"
+	"testing"
+)
+
+var exampleDocument = InvoiceDocument{
+	Items: []LineItem{
+		{Description: "Gartenarbeiten", Quantity: 12.5, UnitPrice: 95, VATPercent: 7.7},
+		{Description: "Entsorgung Schnittmaterial", Quantity: 1, UnitPrice: 129.9, VATPercent: 7.7},
+		{Description: "Setzlinge", Quantity: 30, UnitPrice: 2.36, VATPercent: 2.5},
+	},
+	Bill: examplePayload1,
+}
+
+func TestInvoiceDocumentTotals(t *testing.T) {
+	var testdata = []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"net", exampleDocument.Net(), 1388.20},
+		{"vat", exampleDocument.VAT(), 103.21}, // 101.44 + 1.77.
+		{"total", exampleDocument.Total(), 1491.40},
+		{"payload", exampleDocument.Payload().CurrencyAmount.Amount, 1491.40},
+	}
+	for _, data := range testdata {
+		if data.actual != data.expected {
+			t.Errorf("%v: expected %v, got %v", data.name, data.expected, data.actual)
+		}
+	}
+}
+
+func TestInvoiceDocumentTotalEUR(t *testing.T) {
+	doc := exampleDocument
+	doc.Bill.CurrencyAmount.Currency = EUR
+	if expected, actual := 1491.41, doc.Total(); expected != actual {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
+
+func TestInvoiceDocumentTable(t *testing.T) {
+	table, err := exampleDocument.Table("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"Zwischensumme", "", "", "1 388.20"},
+		{"MWST 2.5%", "", "", "1.77"},
+		{"MWST 7.7%", "", "", "101.44"},
+		{"Rundung", "", "", "-0.01"},
+		{"Total CHF", "", "", "1 491.40"},
+	}
+	if !reflect.DeepEqual(expected, table.Totals) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, table.Totals)
+	}
+	if len(table.Rows) != 3 {
+		t.Errorf("Expected 3 rows, got %v", len(table.Rows))
+	}
+}
+
+func TestInvoiceDocumentBillInformation(t *testing.T) {
+	var testdata = []struct {
+		items    []LineItem
+		expected string
+	}{
+		{
+			items:    exampleDocument.Items,
+			expected: "//S1/30/106017086/32/2.5:70.8;7.7:1317.4",
+		},
+		{
+			items:    exampleDocument.Items[:2],
+			expected: "//S1/30/106017086/32/7.7",
+		},
+		{
+			items: []LineItem{
+				{Description: "Buch", Quantity: 1, UnitPrice: 29, VATPercent: 0},
+				{Description: "Versand", Quantity: 1, UnitPrice: 9, VATPercent: 7.7},
+			},
+			expected: "//S1/30/106017086/32/0:29;7.7:9",
+		},
+		{
+			items:    []LineItem{{Description: "Spende", Quantity: 1, UnitPrice: 50}},
+			expected: "//S1/30/106017086",
+		},
+	}
+	for i, data := range testdata {
+		doc := InvoiceDocument{
+			Items:     data.items,
+			VATNumber: "CHE-106.017.086 MWST",
+			Bill:      examplePayload3,
+		}
+		p := doc.Payload()
+		if err := p.Validate(); err != nil {
+			t.Errorf("Item %v: expected no error, got %v", i, err)
+		}
+		if actual := p.AdditionalInformation.StructuredMessage.ToString(); actual != data.expected {
+			t.Errorf("Item %v: expected %#v, got %#v", i, data.expected, actual)
+		}
+	}
+}
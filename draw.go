@@ -0,0 +1,586 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// RenderInvoice draws a Swiss QR Invoice with the given renderer as selected
+// by the options. The lower left corner of the invoice is the origin of the
+// renderer moved by the offset of the layout. DrawInvoiceWithOptions calls
+// it with the renderer for a PDF canvas.
+func RenderInvoice(r Renderer, data Payload, opts RenderOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	r.Push()
+	defer r.Pop()
+	r.Translate(opts.Layout.OffsetX.points(), opts.Layout.OffsetY.points())
+	r.SetGrey(0)
+	r.SetLineWidth(0.75)
+	invoice := &invoiceDrawer{
+		r:          r,
+		data:       data,
+		language:   opts.language(),
+		layout:     opts.Layout.withDefaults(),
+		qrImage:    opts.QRImage,
+		vectorQR:   opts.VectorQR,
+		preprinted: opts.Preprinted,
+	}
+	if opts.Draft {
+		invoice.preview = true
+		invoice.grey = previewGrey
+		r.SetGrey(invoice.grey)
+	}
+	if err := invoice.drawReceiptPart(); err != nil {
+		return err
+	}
+	if err := invoice.drawPaymentPart(); err != nil {
+		return err
+	}
+	var err error
+	separator := opts.Separator
+	if opts.Preprinted {
+		separator = NoSeparator
+	}
+	switch separator {
+	case BorderSeparator:
+		err = drawBorderWithText(r, invoice.language, invoice.grey)
+	case ScissorsSeparator:
+		err = drawSeparatorWithScissors(r, invoice.grey)
+	}
+	if err != nil {
+		return err
+	}
+	if opts.Draft {
+		return invoice.drawDraftBanner()
+	}
+	return nil
+}
+
+type invoiceDrawer struct {
+	r        Renderer
+	data     Payload
+	language Language
+	preview  bool    // Draw a draft that cannot be paid.
+	grey     float64 // Colour of all elements; 0 is black.
+	layout   Layout
+	qrImage  image.Image // Pre-rendered QR code, or nil.
+	vectorQR bool        // Draw the QR code as paths.
+
+	// Leave out the static elements printed on the paper.
+	preprinted bool
+}
+
+type layoutOptions struct {
+	headerSize float64
+	textSize   float64
+	leading    float64
+	left, top  float64
+	maxHeight  float64
+	maxWidth   float64
+	boxWidth   float64
+	boxHeight  float64
+}
+
+// text draws s in the given font with its base line starting at x, y.
+func (i *invoiceDrawer) text(x, y float64, s string, style FontStyle, size float64) error {
+	if err := i.r.SetFont(style, size); err != nil {
+		return err
+	}
+	i.r.Text(x, y, s)
+	return nil
+}
+
+// heading draws a title or a heading unless the invoice is printed on
+// preprinted paper.
+func (i *invoiceDrawer) heading(x, y float64, s string, size float64) error {
+	if i.preprinted {
+		return nil
+	}
+	return i.text(x, y, s, BoldFont, size)
+}
+
+// width returns the width of s in the given font.
+func (i *invoiceDrawer) width(s string, style FontStyle, size float64) (float64, error) {
+	if err := i.r.SetFont(style, size); err != nil {
+		return 0, err
+	}
+	return i.r.TextWidth(s), nil
+}
+
+// createQR creates the QR code image for the invoice.
+func (i *invoiceDrawer) createQR() (image.Image, error) {
+	if i.preview {
+		return createDraftQR(i.data, float32(i.grey))
+	}
+	if i.qrImage != nil {
+		if err := checkQRImage(i.qrImage, i.data); err != nil {
+			return nil, err
+		}
+		return i.qrImage, nil
+	}
+	return CreateQR(i.data)
+}
+
+func (i *invoiceDrawer) drawReceiptPart() error {
+	if title, err := TitleSection(i.data, i.language); err != nil {
+		return err
+	} else {
+		// Font size 11.
+		if err := i.heading(0.5*PointsPerCm, 10.0*PointsPerCm-11, title.Receipt, 11); err != nil {
+			return err
+		}
+	}
+
+	if amt, err := AmountSection(i.data, i.language); err != nil {
+		return err
+	} else {
+		err = i.drawAmount(amt, layoutOptions{
+			headerSize: 6,
+			textSize:   8,
+			leading:    9,
+			left:       0.5 * PointsPerCm,
+			top:        3.7 * PointsPerCm,
+			maxHeight:  1.4 * PointsPerCm,
+			maxWidth:   5.2 * PointsPerCm,
+			boxWidth:   i.layout.ReceiptAmountBox.Width.points(),
+			boxHeight:  i.layout.ReceiptAmountBox.Height.points(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if info, _, err := informationSection(i.data, i.language,
+		WidthForReceipt(8),
+		receiptPartInformation, i.layout); err != nil {
+		return err
+	} else {
+		err = i.drawParagraphs(info, layoutOptions{
+			headerSize: 6,
+			textSize:   8,
+			leading:    9,
+			left:       0.5 * PointsPerCm,
+			top:        9.3 * PointsPerCm,
+			maxHeight:  5.6 * PointsPerCm,
+			boxWidth:   i.layout.ReceiptDebtorBox.Width.points(),
+			boxHeight:  i.layout.ReceiptDebtorBox.Height.points(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	heading := localized(AcceptancePointHeading, i.language)
+	width, err := i.width(heading, BoldFont, 6)
+	if err != nil {
+		return err
+	}
+	return i.heading(5.7*PointsPerCm-width, 2.3*PointsPerCm-6, heading, 6)
+}
+
+func (i *invoiceDrawer) drawPaymentPart() error {
+	if title, err := TitleSection(i.data, i.language); err != nil {
+		return err
+	} else {
+		// Font size 11.
+		if err := i.heading(6.7*PointsPerCm, 10.0*PointsPerCm-11, title.PaymentPart, 11); err != nil {
+			return err
+		}
+	}
+
+	if amt, err := AmountSection(i.data, i.language); err != nil {
+		return err
+	} else {
+		err = i.drawAmount(amt, layoutOptions{
+			headerSize: 8,
+			textSize:   10,
+			leading:    11,
+			left:       6.7 * PointsPerCm,
+			top:        3.7 * PointsPerCm,
+			maxHeight:  2.2 * PointsPerCm,
+			maxWidth:   5.1 * PointsPerCm,
+			boxWidth:   i.layout.PaymentAmountBox.Width.points(),
+			boxHeight:  i.layout.PaymentAmountBox.Height.points(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if i.vectorQR {
+		if err := i.drawVectorQR(); err != nil {
+			return err
+		}
+	} else if qrImage, err := i.createQR(); err != nil {
+		return err
+	} else {
+		// 46×46 mm image; at least 5 mm margin.
+		// Payment part starts at 61.5 mm indent.
+		i.r.Image(qrImage, 6.7*PointsPerCm, 4.3*PointsPerCm, 11.3*PointsPerCm, 8.9*PointsPerCm)
+	}
+
+	if section, _, err := informationSection(i.data, i.language,
+		WidthForPaymentPart(10),
+		paymentPartInformation, i.layout); err != nil {
+		return err
+	} else {
+		err = i.drawParagraphs(section, layoutOptions{
+			headerSize: 8,
+			textSize:   10,
+			leading:    11,
+			left:       11.9 * PointsPerCm,
+			top:        10.0 * PointsPerCm,
+			maxHeight:  8.5 * PointsPerCm,
+			boxWidth:   i.layout.PaymentDebtorBox.Width.points(),
+			boxHeight:  i.layout.PaymentDebtorBox.Height.points(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	y := 1.5*PointsPerCm - 8
+	for _, ap := range i.data.AlternativeProcedureParameters {
+		label := ap.LocalizedLabel(i.language) + ": "
+		width, err := i.width(label, BoldFont, 7)
+		if err != nil {
+			return err
+		}
+		if err := i.text(6.7*PointsPerCm, y, label, BoldFont, 7); err != nil {
+			return err
+		}
+		// 13.8 cm is the total width; the font size is 7 pt.
+		remainingWidth := (13.8*PointsPerCm - width) / 7.0
+		procedure := shortenToWidth(ap.Procedure, remainingWidth)
+		if err := i.text(6.7*PointsPerCm+width, y, procedure, RegularFont, 7); err != nil {
+			return err
+		}
+		y -= 8
+	}
+	return nil
+}
+
+// drawVectorQR draws the QR code at the same position and in the same size
+// as the image drawn by drawPaymentPart, with one rectangle per run of dark
+// modules in a row. The Swiss cross is drawn on top.
+func (i *invoiceDrawer) drawVectorQR() error {
+	qrCode, err := qrModules(i.data, i.preview)
+	if err != nil {
+		return err
+	}
+	// The coordinates of the 1086×1086 pixels image are mapped to the
+	// 46×46 mm square; pixel rows are counted from the top.
+	const size = 1086
+	left, top := 6.7*PointsPerCm, 8.9*PointsPerCm
+	scale := 4.6 * PointsPerCm / size
+	rectangle := func(path *Path, x0, y0, x1, y1 float64) {
+		path.Rectangle(left+x0*scale, top-y1*scale, left+x1*scale, top-y0*scale)
+	}
+	n := qrCode.Bounds().Dx()
+	module := float64(size) / float64(n)
+	modules := new(Path)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if qrCode.At(x, y) != color.Black {
+				continue
+			}
+			start := x
+			for x+1 < n && qrCode.At(x+1, y) == color.Black {
+				x++
+			}
+			rectangle(modules, float64(start)*module, float64(y)*module,
+				float64(x+1)*module, float64(y+1)*module)
+		}
+	}
+	i.r.Fill(modules)
+	i.r.Push()
+	defer i.r.Pop()
+	for _, elem := range swissCross {
+		if elem.color == color.White {
+			i.r.SetGrey(1)
+		} else {
+			i.r.SetGrey(i.grey)
+		}
+		r := elem.rect
+		path := new(Path)
+		rectangle(path, float64(r.Min.X), float64(r.Min.Y), float64(r.Max.X), float64(r.Max.Y))
+		i.r.Fill(path)
+	}
+	return nil
+}
+
+// drawAmount draws a payment amount, or an empty box if requested.
+func (i *invoiceDrawer) drawAmount(amt AmountSectionData, layout layoutOptions) error {
+	i.r.Push()
+	defer i.r.Pop()
+	i.r.Translate(layout.left, layout.top-layout.headerSize)
+	currencyWidth, err := i.width(amt.CurrencyHeading, BoldFont, layout.headerSize)
+	if err != nil {
+		return err
+	}
+	amountWidth, err := i.width(amt.AmountHeading, BoldFont, layout.headerSize)
+	if err != nil {
+		return err
+	}
+	columnSeparation := currencyWidth + layout.headerSize
+	totalHeaderWidth := columnSeparation + amountWidth
+	if err := i.heading(0, 0, amt.CurrencyHeading, layout.headerSize); err != nil {
+		return err
+	}
+	if err := i.heading(columnSeparation, 0, amt.AmountHeading, layout.headerSize); err != nil {
+		return err
+	}
+	if err := i.text(0, -layout.leading, amt.CurrencyValue, RegularFont, layout.textSize); err != nil {
+		return err
+	}
+	if amt.AmountValue != "" {
+		return i.text(columnSeparation, -layout.leading, amt.AmountValue, RegularFont, layout.textSize)
+	}
+	if amt.EmptyBox && !i.preprinted {
+		left, top := layout.maxWidth-layout.boxWidth, layout.headerSize
+		if totalHeaderWidth+layout.boxWidth > layout.maxWidth {
+			top = -5 // Below the headings, aligned with the currency.
+		}
+		path := new(Path)
+		corners(path, left, top-layout.boxHeight, left+layout.boxWidth, top)
+		i.r.Stroke(path)
+	}
+	return nil
+}
+
+// textLine is a line of text positioned by layoutParagraphs.
+type textLine struct {
+	y     float64 // Base line relative to the first base line.
+	text  string
+	style FontStyle
+	size  float64
+}
+
+// drawParagraphs draws a slice of paragraphs following the layout options.
+// If a paragraph is empty, a box is drawn instead. If the paragraphs do not
+// fit, the leading and the paragraph spacing are reduced step by step down
+// to the minimums of the invoice layout.
+func (i *invoiceDrawer) drawParagraphs(section []Paragraph, layout layoutOptions) error {
+	leading, skip := layout.leading, float64(paragraphSpacing)
+	minLeading, minSkip := leading, skip
+	if i.layout.MinLeading != 0 {
+		minLeading = i.layout.MinLeading * layout.textSize
+		if minLeading > leading {
+			minLeading = leading
+		}
+	}
+	if i.layout.MinParagraphSpacing != 0 {
+		minSkip = i.layout.MinParagraphSpacing
+	}
+	const steps = 4
+	for step := 0; step <= steps; step++ {
+		layout.leading = leading - (leading-minLeading)*float64(step)/steps
+		paragraphSkip := skip - (skip-minSkip)*float64(step)/steps
+		lines, boxes, height := layoutParagraphs(section, layout, paragraphSkip)
+		if height <= layout.maxHeight {
+			return i.drawLines(lines, boxes, layout)
+		}
+	}
+	return errors.New("Invoice text height too large.")
+}
+
+// drawLines draws the lines and boxes returned by layoutParagraphs.
+func (i *invoiceDrawer) drawLines(lines []textLine, boxes *Path, layout layoutOptions) error {
+	i.r.Push()
+	defer i.r.Pop()
+	i.r.Translate(layout.left, layout.top-layout.headerSize)
+	if boxes != nil && !i.preprinted {
+		i.r.Stroke(boxes)
+	}
+	for _, line := range lines {
+		if line.style == BoldFont && i.preprinted {
+			continue // Heading.
+		}
+		if err := i.text(0, line.y, line.text, line.style, line.size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// layoutParagraphs sets the paragraphs with the given spacing, relative to
+// the first base line, and returns the lines together with the height of
+// the text, from the first to the last base line. The boxes for empty
+// paragraphs are returned as path, which is nil if there are no empty
+// paragraphs.
+func layoutParagraphs(section []Paragraph, layout layoutOptions,
+	paragraphSkip float64) ([]textLine, *Path, float64) {
+	var lines []textLine
+	var boxes *Path
+	y := 0.0
+	for n, s := range section {
+		if n > 0 {
+			y -= layout.leading + paragraphSkip
+		}
+		lines = append(lines, textLine{y, s.Heading, BoldFont, layout.headerSize})
+		if len(s.Lines) > 0 {
+			for _, line := range s.Lines {
+				y -= layout.leading
+				lines = append(lines, textLine{y, line, RegularFont, layout.textSize})
+			}
+		} else {
+			if boxes == nil {
+				boxes = new(Path)
+			}
+			corners(boxes, 0, y-5-layout.boxHeight, layout.boxWidth, y-5)
+			y -= layout.boxHeight + paragraphSkip
+		}
+	}
+	return lines, boxes, -y
+}
+
+// drawDraftBanner draws a large, slanted “DRAFT” text across the payment
+// part. It is assumed that the current point is at the lower left corner of
+// the invoice area.
+func (i *invoiceDrawer) drawDraftBanner() error {
+	i.r.Push()
+	defer i.r.Pop()
+	banner := localized(DraftHeading, i.language)
+	width, err := i.width(banner, BoldFont, 60)
+	if err != nil {
+		return err
+	}
+	i.r.Translate(13.6*PointsPerCm, 5.25*PointsPerCm)
+	i.r.Rotate(math.Pi / 8.0)
+	i.r.Text(-width/2.0, -20, banner)
+	return nil
+}
+
+// drawBorderWithText draws a border on top of the invoice and between the
+// receipt and the payment part in the given grey level, with the text that
+// the payment part is to be separated above it.
+func drawBorderWithText(r Renderer, language Language, grey float64) error {
+	r.Push()
+	defer r.Pop()
+	path := new(Path)
+	path.Move(0, 10.5*PointsPerCm)
+	path.Line(21.0*PointsPerCm, 10.5*PointsPerCm)
+	path.Move(6.2*PointsPerCm, 0)
+	path.Line(6.2*PointsPerCm, 10.5*PointsPerCm)
+	r.SetGrey(grey)
+	r.SetLineWidth(1.0)
+	r.Stroke(path)
+	sep, err := BorderText(language)
+	if err != nil {
+		return err
+	}
+	if err := r.SetFont(RegularFont, 6); err != nil {
+		return err
+	}
+	r.Text(10.5*PointsPerCm-r.TextWidth(sep)/2.0, 10.5*PointsPerCm+3, sep)
+	return nil
+}
+
+// drawSeparatorWithScissors draws a line between the receipt and the
+// payment part in the given grey level, with a scissors symbol on it.
+func drawSeparatorWithScissors(r Renderer, grey float64) error {
+	r.Push()
+	defer r.Pop()
+	path := new(Path)
+	path.Move(6.2*PointsPerCm, 0)
+	path.Line(6.2*PointsPerCm, 10.5*PointsPerCm)
+	r.SetGrey(grey)
+	r.SetLineWidth(1.0)
+	r.Stroke(path)
+	drawScissors(r, 6.2*PointsPerCm, 5.25*PointsPerCm, 0.6*PointsPerCm, -math.Pi/2.0)
+	return nil
+}
+
+// drawScissors draws the scissors symbol of DrawScissors, size long and
+// centered at x, y, in the current colour.
+func drawScissors(r Renderer, x, y, size, angle float64) {
+	sin, cos := math.Sincos(angle)
+	// pt maps coordinates of the symbol, measured in multiples of its
+	// length with the blades pointing to the right, to the renderer.
+	pt := func(u, v float64) (float64, float64) {
+		return x + (u*cos-v*sin)*size, y + (u*sin+v*cos)*size
+	}
+	r.Push()
+	defer r.Pop()
+
+	// Blades, running from the rings through the pivot at the origin to
+	// the tips.
+	blades := new(Path)
+	for _, s := range []float64{1, -1} {
+		blades.Move(pt(-0.21, 0.12*s))
+		blades.Line(pt(0, 0.035*s))
+		blades.Line(pt(0.5, -0.01*s))
+		blades.Line(pt(0.5, -0.05*s))
+		blades.Line(pt(0, -0.035*s))
+		blades.Line(pt(-0.17, 0.06*s))
+		blades.Close()
+	}
+	r.Fill(blades)
+
+	// Finger rings, approximated by Bézier curves.
+	const k = 0.5523 // Control point distance of a quarter circle.
+	const radius = 0.13
+	rings := new(Path)
+	curve := func(u1, v1, u2, v2, u3, v3 float64) {
+		x1, y1 := pt(u1, v1)
+		x2, y2 := pt(u2, v2)
+		x3, y3 := pt(u3, v3)
+		rings.Curve(x1, y1, x2, y2, x3, y3)
+	}
+	for _, s := range []float64{1, -1} {
+		u, v, c := -0.33, 0.2*s, radius
+		rings.Move(pt(u+c, v))
+		curve(u+c, v+k*c, u+k*c, v+c, u, v+c)
+		curve(u-k*c, v+c, u-c, v+k*c, u-c, v)
+		curve(u-c, v-k*c, u-k*c, v-c, u, v-c)
+		curve(u+k*c, v-c, u+c, v-k*c, u+c, v)
+		rings.Close()
+	}
+	r.SetLineWidth(size * 0.06)
+	r.Stroke(rings)
+}
+
+// corners adds corner marks around the given box to path.
+func corners(path *Path, minX, minY, maxX, maxY float64) {
+	// According to the standard, the corner marks should be 3 mm long.
+	size := 0.3 * PointsPerCm
+	// Lower left corner
+	path.Move(minX+size, minY)
+	path.Line(minX, minY)
+	path.Line(minX, minY+size)
+	// Upper left corner
+	path.Move(minX, maxY-size)
+	path.Line(minX, maxY)
+	path.Line(minX+size, maxY)
+	// Upper right corner
+	path.Move(maxX-size, maxY)
+	path.Line(maxX, maxY)
+	path.Line(maxX, maxY-size)
+	// Lower right corner
+	path.Move(maxX, minY+size)
+	path.Line(maxX, minY)
+	path.Line(maxX-size, minY)
+}
+
+// points converts a length in millimeters to points.
+func (m Millimeter) points() float64 {
+	return float64(m) / 10.0 * PointsPerCm
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"math"
+	"time"
+)
+
+// The number of days of a payment condition count from the invoice date, so
+// that “2:10;0:30” grants a discount of 2% within 10 days of the invoice
+// date and is payable net within 30 days.
+
+// DueDate returns the date by which an invoice of the given date must be
+// paid: the deadline of the condition without discount or, if all
+// conditions grant a discount, the latest deadline. It returns the zero
+// time if there are no conditions.
+func (c PaymentConditions) DueDate(invoiceDate time.Time) time.Time {
+	days, found := 0, false
+	for _, condition := range c {
+		if condition.DiscountPercent == 0 {
+			return invoiceDate.AddDate(0, 0, condition.NumberOfDays)
+		}
+		if !found || condition.NumberOfDays > days {
+			days, found = condition.NumberOfDays, true
+		}
+	}
+	if !found {
+		return time.Time{}
+	}
+	return invoiceDate.AddDate(0, 0, days)
+}
+
+// DiscountedAmount returns the amount to pay on payDate for an invoice of
+// the given date, applying the largest discount whose deadline has not
+// passed on payDate. The result is rounded to 0.01; without applicable
+// discount, the amount is returned unchanged.
+func (c PaymentConditions) DiscountedAmount(amount float64, invoiceDate, payDate time.Time) float64 {
+	pay := civilDate(payDate)
+	discount := 0.0
+	for _, condition := range c {
+		deadline := civilDate(invoiceDate.AddDate(0, 0, condition.NumberOfDays))
+		if !pay.After(deadline) && condition.DiscountPercent > discount {
+			discount = condition.DiscountPercent
+		}
+	}
+	if discount == 0 {
+		return amount
+	}
+	return math.Round(amount*(100-discount)) / 100
+}
+
+// DueDate returns the due date according to the payment conditions,
+// counting from the invoice date, or the zero time if the bill information
+// has no invoice date or no conditions.
+func (bi BillInformation) DueDate() time.Time {
+	if bi.InvoiceDate.Date.IsZero() {
+		return time.Time{}
+	}
+	return bi.Conditions.DueDate(bi.InvoiceDate.Date)
+}
+
+// DiscountedAmount returns the amount to pay on payDate according to the
+// payment conditions, counting from the invoice date. Without invoice date,
+// the amount is returned unchanged.
+func (bi BillInformation) DiscountedAmount(amount float64, payDate time.Time) float64 {
+	if bi.InvoiceDate.Date.IsZero() {
+		return amount
+	}
+	return bi.Conditions.DiscountedAmount(amount, bi.InvoiceDate.Date, payDate)
+}
+
+// civilDate returns midnight UTC of the calendar date of t, so that dates
+// can be compared regardless of time of day and location.
+func civilDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueDate(t *testing.T) {
+	invoiceDate := time.Date(2019, time.May, 12, 0, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		conditions PaymentConditions
+		expected   time.Time
+	}{
+		{nil, time.Time{}},
+		{PaymentConditions{{2, 10}, {0, 30}}, time.Date(2019, time.June, 11, 0, 0, 0, 0, time.UTC)},
+		{PaymentConditions{{3, 5}, {1, 20}}, time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		if actual := test.conditions.DueDate(invoiceDate); !actual.Equal(test.expected) {
+			t.Errorf("Item %v: expected %v, got %v", i, test.expected, actual)
+		}
+	}
+	bi := examplePayload2.AdditionalInformation.StructuredMessage
+	if actual, expected := bi.DueDate(), time.Date(2019, time.June, 11, 0, 0, 0, 0, time.UTC); !actual.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+	if actual := (BillInformation{Conditions: bi.Conditions}).DueDate(); !actual.IsZero() {
+		t.Errorf("Expected zero time without invoice date, got %v", actual)
+	}
+}
+
+func TestDiscountedAmount(t *testing.T) {
+	bi := BillInformation{
+		InvoiceDate: OneDate(2019, time.May, 12),
+		Conditions:  PaymentConditions{{3, 5}, {2, 10}, {0, 30}},
+	}
+	for i, test := range []struct {
+		payDate  time.Time
+		expected float64
+	}{
+		{time.Date(2019, time.May, 12, 0, 0, 0, 0, time.UTC), 1891.26},
+		{time.Date(2019, time.May, 17, 23, 59, 0, 0, time.UTC), 1891.26},
+		{time.Date(2019, time.May, 18, 0, 0, 0, 0, time.UTC), 1910.76},
+		{time.Date(2019, time.May, 22, 12, 0, 0, 0, time.UTC), 1910.76},
+		{time.Date(2019, time.May, 23, 0, 0, 0, 0, time.UTC), 1949.75},
+		{time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC), 1949.75},
+	} {
+		if actual := bi.DiscountedAmount(1949.75, test.payDate); actual != test.expected {
+			t.Errorf("Item %v: expected %v, got %v", i, test.expected, actual)
+		}
+	}
+	if actual := (BillInformation{Conditions: bi.Conditions}).DiscountedAmount(100, time.Now()); actual != 100 {
+		t.Errorf("Expected unchanged amount without invoice date, got %v", actual)
+	}
+}
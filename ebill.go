@@ -0,0 +1,147 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EBillType is the type of an eBill, the second parameter of the eBill
+// alternative procedure.
+type EBillType string
+
+const (
+	// EBillBill is a regular bill.
+	EBillBill EBillType = "B"
+
+	// EBillReminder is a reminder for an earlier bill.
+	EBillReminder EBillType = "R"
+)
+
+// EBillLabel is the label printed for the eBill alternative procedure.
+const EBillLabel = "eBill"
+
+// EBill describes the eBill alternative procedure, with which the debtor
+// receives the bill in the e-banking. The recipient is identified by exactly
+// one of EmailAddress, RecipientID and EnterpriseID.
+type EBill struct {
+	// Type defaults to EBillBill.
+	Type EBillType
+
+	// EmailAddress is the email address of the recipient.
+	EmailAddress string
+
+	// RecipientID is the 17-digit eBill recipient ID, e.g.
+	// “41010560425610173”.
+	RecipientID string
+
+	// EnterpriseID is the UID of a business recipient, e.g.
+	// “CHE-123.456.789”; separators are removed.
+	EnterpriseID string
+
+	// ReferencedBill is the reference of the bill a reminder refers to.
+	// It is only allowed for reminders.
+	ReferencedBill string
+}
+
+// Procedure validates the eBill parameters and returns the alternative
+// procedure “eBill/B/recipient” or, for reminders, “eBill/R/recipient” with
+// the referenced bill appended, labelled with EBillLabel.
+func (e EBill) Procedure() (AlternativeProcedure, error) {
+	parameters, err := e.parameters()
+	if err != nil {
+		return AlternativeProcedure{}, err
+	}
+	ap := AlternativeProcedure{Label: EBillLabel, Procedure: strings.Join(parameters, "/")}
+	if err := (AlternativeProcedures{ap}).Validate(); err != nil {
+		return AlternativeProcedure{}, err
+	}
+	return ap, nil
+}
+
+// parameters validates e and returns the parameters of the procedure.
+func (e EBill) parameters() ([]string, error) {
+	billType := e.Type
+	if billType == "" {
+		billType = EBillBill
+	}
+	if billType != EBillBill && billType != EBillReminder {
+		return nil, fmt.Errorf("Unknown eBill type: %v", billType)
+	}
+	enterpriseID := strings.NewReplacer("-", "", ".", "", " ", "").Replace(e.EnterpriseID)
+	var recipients []string
+	for _, r := range []string{e.EmailAddress, e.RecipientID, enterpriseID} {
+		if r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	if len(recipients) != 1 {
+		return nil, errors.New("eBill requires exactly one of email address, recipient ID and enterprise ID")
+	}
+	switch {
+	case e.EmailAddress != "":
+		if at := strings.Index(e.EmailAddress, "@"); at <= 0 || at == len(e.EmailAddress)-1 ||
+			strings.ContainsAny(e.EmailAddress, "/ ") {
+			return nil, fmt.Errorf("Invalid eBill email address: %v", e.EmailAddress)
+		}
+	case e.RecipientID != "":
+		if len(e.RecipientID) != 17 || !isDigits(e.RecipientID) {
+			return nil, fmt.Errorf("eBill recipient ID must have 17 digits: %v", e.RecipientID)
+		}
+	default:
+		if !strings.HasPrefix(enterpriseID, "CHE") || len(enterpriseID) != 12 || !isDigits(enterpriseID[3:]) {
+			return nil, fmt.Errorf("eBill enterprise ID must be a UID CHE-123.456.789: %v", e.EnterpriseID)
+		}
+	}
+	parameters := []string{"eBill", string(billType), recipients[0]}
+	if e.ReferencedBill != "" {
+		if billType != EBillReminder {
+			return nil, fmt.Errorf("Referenced bill only allowed for reminders: %v", e.ReferencedBill)
+		}
+		if strings.Contains(e.ReferencedBill, "/") {
+			return nil, fmt.Errorf("Invalid referenced bill: %v", e.ReferencedBill)
+		}
+		parameters = append(parameters, e.ReferencedBill)
+	}
+	return parameters, nil
+}
+
+// validateEBillProcedure checks the parameters of an eBill procedure
+// written by hand, as EBill.Procedure would.
+func validateEBillProcedure(procedure string) error {
+	parameters := strings.Split(procedure, "/")
+	if len(parameters) < 3 || len(parameters) > 4 {
+		return fmt.Errorf("eBill procedure must be eBill/type/recipient[/referenced bill]: %v", procedure)
+	}
+	e := EBill{Type: EBillType(parameters[1])}
+	switch recipient := parameters[2]; {
+	case strings.Contains(recipient, "@"):
+		e.EmailAddress = recipient
+	case strings.HasPrefix(recipient, "CHE"):
+		e.EnterpriseID = recipient
+	default:
+		e.RecipientID = recipient
+	}
+	if len(parameters) == 4 {
+		if parameters[3] == "" {
+			return fmt.Errorf("Empty referenced bill: %v", procedure)
+		}
+		e.ReferencedBill = parameters[3]
+	}
+	_, err := e.parameters()
+	return err
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEBillProcedure(t *testing.T) {
+	for i, test := range []struct {
+		ebill    EBill
+		expected string
+	}{
+		{EBill{EmailAddress: "peter@sample.ch"}, "eBill/B/peter@sample.ch"},
+		{EBill{RecipientID: "41010560425610173"}, "eBill/B/41010560425610173"},
+		{EBill{EnterpriseID: "CHE-123.456.789"}, "eBill/B/CHE123456789"},
+		{EBill{Type: EBillReminder, EmailAddress: "peter@sample.ch", ReferencedBill: "10201409"},
+			"eBill/R/peter@sample.ch/10201409"},
+	} {
+		ap, err := test.ebill.Procedure()
+		if err != nil {
+			t.Errorf("Item %v: expected no error, got: %v", i, err)
+			continue
+		}
+		if ap.Label != EBillLabel || ap.Procedure != test.expected {
+			t.Errorf("Item %v: expected %#v, got %#v", i, test.expected, ap)
+		}
+	}
+}
+
+func TestEBillProcedureErrors(t *testing.T) {
+	for i, test := range []struct {
+		ebill    EBill
+		expected string
+	}{
+		{EBill{}, "eBill requires exactly one of"},
+		{EBill{EmailAddress: "peter@sample.ch", RecipientID: "41010560425610173"}, "eBill requires exactly one of"},
+		{EBill{Type: "X", EmailAddress: "peter@sample.ch"}, "Unknown eBill type: X"},
+		{EBill{EmailAddress: "peter.sample.ch"}, "Invalid eBill email address"},
+		{EBill{RecipientID: "4101056042561017"}, "eBill recipient ID must have 17 digits"},
+		{EBill{EnterpriseID: "CHE-123.456"}, "eBill enterprise ID must be a UID"},
+		{EBill{EmailAddress: "peter@sample.ch", ReferencedBill: "1"}, "Referenced bill only allowed for reminders"},
+		{EBill{EmailAddress: strings.Repeat("a", 90) + "@sample.ch"}, "Maximum field length is 100 characters"},
+	} {
+		_, err := test.ebill.Procedure()
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	barcode_qr "github.com/boombuler/barcode/qr"
+)
+
+// DualPayload contains the payload of a Swiss QR code together with the
+// payload of an EPC QR code (“GiroCode”) for the same payment, so that
+// debtors outside Switzerland can pay a EUR invoice by SEPA credit transfer.
+type DualPayload struct {
+	SwissQR string
+	EPC     string
+
+	// Adaptations lists the data of the Swiss QR payload that could not
+	// be carried over to the EPC QR code unchanged.
+	Adaptations []string
+}
+
+// maxEPCPayloadLength is the maximum length of an EPC QR code payload.
+const maxEPCPayloadLength = 331
+
+// NewDualPayload serializes the payload both as Swiss QR payload and as EPC
+// QR payload according to EPC069-12, version 002. Only EUR payments to a
+// regular IBAN can be encoded in an EPC QR code: QR references are reserved
+// for QR-IBANs, which do not accept SEPA credit transfers.
+func NewDualPayload(p Payload) (DualPayload, error) {
+	var buffer bytes.Buffer
+	if err := p.Serialize(&buffer); err != nil {
+		return DualPayload{}, err
+	}
+	epc, adaptations, err := EPCPayload(p)
+	if err != nil {
+		return DualPayload{}, err
+	}
+	return DualPayload{SwissQR: buffer.String(), EPC: epc, Adaptations: adaptations}, nil
+}
+
+// EPCPayload returns the EPC QR payload of NewDualPayload along with the
+// adaptations.
+func EPCPayload(p Payload) (string, []string, error) {
+	if err := p.Validate(); err != nil {
+		return "", nil, err
+	}
+	if p.CurrencyAmount.Currency != EUR {
+		return "", nil, fmt.Errorf("EPC QR code requires currency EUR: %v", p.CurrencyAmount.Currency)
+	}
+	if IsQRIBAN(p.Account) {
+		return "", nil, errors.New("EPC QR code cannot be used with a QR-IBAN")
+	}
+	var adaptations []string
+	adapt := func(adaptation string) {
+		adaptations = append(adaptations, adaptation)
+	}
+	if p.Creditor.Address != nil || p.Creditor.CountryCode != "" {
+		adapt("Creditor address omitted")
+	}
+	if p.UltimateCreditor.Name != "" {
+		adapt("Ultimate creditor omitted")
+	}
+	if p.UltimateDebtor.Name != "" {
+		adapt("Ultimate debtor omitted")
+	}
+	amount := ""
+	switch {
+	case p.CurrencyAmount.Amount > 0:
+		amount = fmt.Sprintf("EUR%.2f", p.CurrencyAmount.Amount)
+	case p.CurrencyAmount.Mode == AmountZero:
+		adapt("Zero amount omitted")
+	}
+	reference, message, information := "", p.AdditionalInformation.UnstructuredMessage, ""
+	if p.Reference.Type() == "SCOR" {
+		// The EPC QR code contains either a reference or a message; the
+		// message is kept as information for the debtor.
+		reference = p.Reference.Number.DigitalFormat()
+		if message != "" {
+			information = message
+			message = ""
+			if r := []rune(information); len(r) > 70 {
+				information = string(r[:70])
+				adapt("Message shortened to 70 characters")
+			}
+			adapt("Message moved to information for the debtor")
+		}
+	}
+	if p.AdditionalInformation.BillInformation() != "" {
+		adapt("Bill information omitted")
+	}
+	if len(p.AlternativeProcedureParameters) > 0 {
+		adapt("Alternative procedures omitted")
+	}
+	fields := []string{
+		"BCD", "002", "1", "SCT",
+		"", // BIC, optional within the EEA.
+		p.Creditor.Name,
+		p.Account.IBAN.Code,
+		amount,
+		"", // Purpose.
+		reference,
+		message,
+		information,
+	}
+	// Trailing empty fields are omitted.
+	for fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+	s := strings.Join(fields, "\n")
+	if len(s) > maxEPCPayloadLength {
+		return "", nil, fmt.Errorf("Maximum EPC QR payload length is %d: %d", maxEPCPayloadLength, len(s))
+	}
+	return s, adaptations, nil
+}
+
+// CreateEPCQR creates an EPC QR code image of 1086×1086 pixels, the size
+// of the images created by CreateQR, from a payload returned by EPCPayload.
+// EPC QR codes carry no Swiss cross.
+func CreateEPCQR(payload string) (image.Image, error) {
+	qrCode, err := barcode_qr.Encode(payload, barcode_qr.M, barcode_qr.Unicode)
+	if err != nil {
+		return nil, err
+	}
+	qrCode, err = barcode.Scale(qrCode, 1086, 1086)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewGray16(image.Rect(0, 0, 1086, 1086))
+	draw.Draw(img, img.Bounds(), qrCode, image.ZP, draw.Src)
+	return img, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEPCPayload(t *testing.T) {
+	euro := examplePayload1
+	euro.CurrencyAmount.Currency = EUR
+	withReference := exampleCreditorReference
+	withReference.CurrencyAmount = PaymentAmount{Currency: EUR, Mode: AmountZero}
+	var testdata = []struct {
+		data        Payload
+		expected    string
+		adaptations []string
+		message     string
+	}{
+		{
+			data: euro,
+			expected: "BCD\n002\n1\nSCT\n\nRobert Schneider AG\nCH5800791123000889012\nEUR3949.75\n\n\n" +
+				"Rechnung Nr. 3139 für Gartenarbeiten und Entsorgung Schnittmaterial",
+			adaptations: []string{"Creditor address omitted", "Ultimate debtor omitted"},
+		},
+		{
+			data: withReference,
+			expected: "BCD\n002\n1\nSCT\n\nSalvation Army Foundation Switzerland\nCH3709000000304442225\n\n\n" +
+				"RF18539007547034\n\nDonation to the Winterfest Campaign",
+			adaptations: []string{
+				"Creditor address omitted",
+				"Zero amount omitted",
+				"Message moved to information for the debtor",
+			},
+		},
+		{data: examplePayload1, message: "EPC QR code requires currency EUR: CHF"},
+		{
+			data: func() Payload {
+				p := examplePayload2
+				p.CurrencyAmount.Currency = EUR
+				return p
+			}(),
+			message: "EPC QR code cannot be used with a QR-IBAN",
+		},
+	}
+	for i, data := range testdata {
+		actual, adaptations, err := EPCPayload(data.data)
+		if data.message != "" {
+			if err == nil || err.Error() != data.message {
+				t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+			continue
+		}
+		if actual != data.expected {
+			t.Errorf("Item %v: Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", i, data.expected, actual)
+		}
+		if !reflect.DeepEqual(adaptations, data.adaptations) {
+			t.Errorf("Item %v: expected adaptations %v, got %v", i, data.adaptations, adaptations)
+		}
+	}
+}
+
+func TestNewDualPayload(t *testing.T) {
+	p := examplePayload3
+	p.CurrencyAmount = PaymentAmount{Amount: 50, Currency: EUR}
+	p.Reference = PaymentReference{ParseCreditorReferenceOrDie("RF18539007547034")}
+	p.AdditionalInformation = PaymentInformation{}
+	dual, err := NewDualPayload(p)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(dual.SwissQR, "SPC\r\n") || !strings.HasPrefix(dual.EPC, "BCD\n") {
+		t.Errorf("Unexpected payloads: %#v", dual)
+	}
+	if !strings.HasSuffix(dual.EPC, "\nEUR50.00\n\nRF18539007547034") {
+		t.Errorf("Unexpected EPC payload: %#v", dual.EPC)
+	}
+	img, err := CreateEPCQR(dual.EPC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Dx(); size != 1086 {
+		t.Errorf("Unexpected image size: %v", size)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ESRMigration contains the payload fields for a QR bill that replaces an
+// orange inpayment slip (ESR).
+type ESRMigration struct {
+	// Account is the QR-IBAN, or empty if none was given.
+	Account AccountNumber
+
+	// Reference is the QR reference, which keeps the digits of the ESR
+	// reference.
+	Reference PaymentReference
+
+	// Warnings lists what had to be adapted or remains to be done by hand.
+	Warnings []string
+}
+
+// MigrateESR converts an ESR participant number, e.g. “01-39139-1”, and an
+// ESR reference to the fields of a QR bill. The QR reference uses the same
+// check digit algorithm as the ESR reference, so the reference is kept;
+// 16-digit references are padded with zeros to 27 digits. The QR-IBAN cannot
+// be derived from the participant number: the bank assigns it, and it must
+// be passed as qrIBAN. If it is empty, the migration contains a warning
+// instead of an account. An error means that the data cannot be migrated
+// automatically, e.g. because of an invalid check digit.
+func MigrateESR(participantNumber, reference, qrIBAN string) (ESRMigration, error) {
+	if err := validateESRParticipantNumber(participantNumber); err != nil {
+		return ESRMigration{}, err
+	}
+	var m ESRMigration
+	digits := strings.ReplaceAll(reference, " ", "")
+	if !isDigits(digits) || digits == "" {
+		return ESRMigration{}, fmt.Errorf("ESR reference may only contain digits 0-9: %v", reference)
+	}
+	switch len(digits) {
+	case 27:
+	case 16:
+		digits = strings.Repeat("0", 11) + digits
+		m.Warnings = append(m.Warnings, fmt.Sprintf("16-digit ESR reference padded to 27 digits: %v", digits))
+	default:
+		return ESRMigration{}, fmt.Errorf("ESR reference must have 16 or 27 digits: %v", reference)
+	}
+	if err := ValidateQRReference(digits); err != nil {
+		return ESRMigration{}, err
+	}
+	ref, err := ParseQRReference(digits)
+	if err != nil {
+		return ESRMigration{}, err
+	}
+	m.Reference = PaymentReference{Number: ref}
+	if qrIBAN == "" {
+		m.Warnings = append(m.Warnings, fmt.Sprintf("QR-IBAN for ESR participant number %v must be requested from the bank", participantNumber))
+		return m, nil
+	}
+	code, err := ParseIBAN(qrIBAN)
+	if err != nil {
+		return ESRMigration{}, err
+	}
+	m.Account = AccountNumber{IBAN: code}
+	if err := m.Account.Validate(); err != nil {
+		return ESRMigration{}, err
+	}
+	if !IsQRIBAN(m.Account) {
+		return ESRMigration{}, fmt.Errorf("QR reference requires a QR-IBAN: %v", qrIBAN)
+	}
+	return m, nil
+}
+
+// validateESRParticipantNumber checks the format “XX-XXXXXX-X” and the
+// check digit of an ESR participant number. Leading zeros of the middle
+// part may be omitted, e.g. “01-39139-1”.
+func validateESRParticipantNumber(s string) error {
+	parts := strings.Split(s, "-")
+	if len(parts) != 3 || len(parts[0]) != 2 || len(parts[1]) == 0 || len(parts[1]) > 6 || len(parts[2]) != 1 ||
+		!isDigits(parts[0]+parts[1]+parts[2]) {
+		return fmt.Errorf("ESR participant number must have the format XX-XXXXXX-X: %v", s)
+	}
+	digits := parts[0] + strings.Repeat("0", 6-len(parts[1])) + parts[1]
+	if qrReferenceCheckDigit(digits) != int(parts[2][0]-'0') {
+		return fmt.Errorf("Invalid check digit of ESR participant number: %v", s)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrateESR(t *testing.T) {
+	var testdata = []struct {
+		participant string
+		reference   string
+		qrIBAN      string
+		expected    string
+		warnings    int
+	}{
+		{"01-39139-1", "21 00000 00003 13947 14300 09017", "CH44 3199 9123 0008 8901 2", "210000000003139471430009017", 0},
+		{"01-039139-1", "3139471430009018", "CH4431999123000889012", "000000000003139471430009018", 1},
+		{"01-39139-1", "210000000003139471430009017", "", "210000000003139471430009017", 1},
+	}
+	for i, data := range testdata {
+		m, err := MigrateESR(data.participant, data.reference, data.qrIBAN)
+		if err != nil {
+			t.Errorf("Item %v: expected no error; got %v", i, err)
+			continue
+		}
+		if actual := m.Reference.Number.DigitalFormat(); actual != data.expected {
+			t.Errorf("Item %v: expected %v, got %v", i, data.expected, actual)
+		}
+		if len(m.Warnings) != data.warnings {
+			t.Errorf("Item %v: expected %d warnings, got %v", i, data.warnings, m.Warnings)
+		}
+		if (m.Account.IBAN != nil) != (data.qrIBAN != "") {
+			t.Errorf("Item %v: unexpected account %v", i, m.Account)
+		}
+	}
+}
+
+func TestMigrateESRErrors(t *testing.T) {
+	var testdata = []struct {
+		participant string
+		reference   string
+		qrIBAN      string
+		message     string
+	}{
+		{"01-39139", "210000000003139471430009017", "", "ESR participant number must have the format XX-XXXXXX-X"},
+		{"01-39139-2", "210000000003139471430009017", "", "Invalid check digit of ESR participant number"},
+		{"01-39139-1", "21000000000313947143000901", "", "ESR reference must have 16 or 27 digits"},
+		{"01-39139-1", "RF18539007547034", "", "ESR reference may only contain digits 0-9"},
+		{"01-39139-1", "210000000003139471430009013", "", "Invalid check digit 3 of QR reference"},
+		{"01-39139-1", "210000000003139471430009017", "CH5800791123000889012", "QR reference requires a QR-IBAN"},
+	}
+	for i, data := range testdata {
+		_, err := MigrateESR(data.participant, data.reference, data.qrIBAN)
+		if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
@@ -12,12 +12,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !(js && wasm)
+
 package swissqr_test
 
 import (
 	"fmt"
 	"github.com/krepost/gopdf/pdf"
-	"github.com/krepost/structref"
 	"github.com/krepost/swissqr"
 	"math"
 	"os"
@@ -81,7 +82,7 @@ func box(doc *pdf.Document) error {
 			CountryCode: "CH",
 		},
 		Reference: swissqr.PaymentReference{
-			Number: structref.NewCreditorReferenceOrDie("RF83 1234 5678 9123 4567 8912"),
+			Number: swissqr.ParseCreditorReferenceOrDie("RF83 1234 5678 9123 4567 8912"),
 		},
 		AdditionalInformation: swissqr.PaymentInformation{
 			UnstructuredMessage: "Beachten Sie unsere Sonderangebotswoche bis 23.02.2017!",
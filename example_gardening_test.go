@@ -12,11 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !(js && wasm)
+
 package swissqr_test
 
 import (
 	"fmt"
-	"github.com/krepost/gopdf/pdf"
 	"github.com/krepost/swissqr"
 	"os"
 )
@@ -57,8 +58,8 @@ func Example_gardeningInvoice() {
 		fmt.Println("Unexpected error:", err)
 	}
 
-	doc := pdf.New()
-	canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm) // QR invoice size.
+	doc := swissqr.NewDocument()
+	canvas := doc.NewPage(21.0*swissqr.Cm, 10.5*swissqr.Cm) // QR invoice size.
 	if err := swissqr.DrawInvoiceWithScissors(canvas, data, "en"); err != nil {
 		fmt.Println("Unexpected error:", err)
 	}
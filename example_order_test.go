@@ -12,12 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !(js && wasm)
+
 package swissqr_test
 
 import (
 	"fmt"
-	"github.com/krepost/gopdf/pdf"
-	"github.com/krepost/structref"
 	"github.com/krepost/swissqr"
 	"os"
 	"time"
@@ -52,7 +52,7 @@ func Example_schneiderOrder() {
 			CountryCode: "CH",
 		},
 		Reference: swissqr.PaymentReference{
-			Number: structref.NewReferenceNumberOrDie("21 00000 00003 13947 14300 09017"),
+			Number: swissqr.ParseQRReferenceOrDie("21 00000 00003 13947 14300 09017"),
 		},
 		AdditionalInformation: swissqr.PaymentInformation{
 			UnstructuredMessage: "Order dated 18.06.2020",
@@ -72,16 +72,16 @@ func Example_schneiderOrder() {
 			},
 		},
 		AlternativeProcedureParameters: swissqr.AlternativeProcedures{
-			swissqr.AlternativeProcedure{"Name AV1", "UV;UltraPay005;12345"},
-			swissqr.AlternativeProcedure{"Name AV2", "XY;XYService;54321"},
+			swissqr.AlternativeProcedure{Label: "Name AV1", Procedure: "UV;UltraPay005;12345"},
+			swissqr.AlternativeProcedure{Label: "Name AV2", Procedure: "XY;XYService;54321"},
 		},
 	}
 	if err := data.Validate(); err != nil {
 		fmt.Println("Unexpected error:", err)
 	}
 
-	doc := pdf.New()
-	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	doc := swissqr.NewDocument()
+	canvas := doc.NewPage(21.0*swissqr.Cm, 29.7*swissqr.Cm)
 	if err := swissqr.DrawInvoiceWithBorder(canvas, data, "en"); err != nil {
 		fmt.Println("Unexpected error:", err)
 	}
@@ -12,12 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !(js && wasm)
+
 package swissqr_test
 
 import (
 	"fmt"
-	"github.com/krepost/gopdf/pdf"
-	"github.com/krepost/structref"
 	"github.com/krepost/swissqr"
 	"os"
 )
@@ -51,15 +51,15 @@ func Example_riferimento() {
 			CountryCode: "CH",
 		},
 		Reference: swissqr.PaymentReference{
-			Number: structref.NewCreditorReferenceOrDie("RF18 5390 0754 7034"),
+			Number: swissqr.ParseCreditorReferenceOrDie("RF18 5390 0754 7034"),
 		},
 	}
 	if err := data.Validate(); err != nil {
 		fmt.Println("Unexpected error:", err)
 	}
 
-	doc := pdf.New()
-	canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm) // QR invoice size.
+	doc := swissqr.NewDocument()
+	canvas := doc.NewPage(21.0*swissqr.Cm, 10.5*swissqr.Cm) // QR invoice size.
 	if err := swissqr.DrawInvoiceWithScissors(canvas, data, "it"); err != nil {
 		fmt.Println("Unexpected error:", err)
 	}
@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// fakeDB is an in-memory database for the SQL implementations. It only
+// understands their default statements.
+type fakeDB struct {
+	mu         sync.Mutex
+	counters   map[string]int64
+	references map[string]bool
+}
+
+// open returns a *sql.DB backed by db.
+func (db *fakeDB) open() *sql.DB {
+	return sql.OpenDB(fakeConnector{db})
+}
+
+type fakeConnector struct{ db *fakeDB }
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeConn{c.db}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver { return nil }
+
+// fakeConn executes statements directly; transactions are not isolated.
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("Prepared statements not supported")
+}
+
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	switch query {
+	case defaultIncrementStatement:
+		name := args[0].Value.(string)
+		if _, ok := c.db.counters[name]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		c.db.counters[name]++
+		return driver.RowsAffected(1), nil
+	case defaultInsertReferenceStatement:
+		reference := args[0].Value.(string)
+		if c.db.references[reference] {
+			// The message of SQLite.
+			return nil, errors.New("UNIQUE constraint failed: swissqr_references.reference")
+		}
+		if c.db.references == nil {
+			c.db.references = make(map[string]bool)
+		}
+		c.db.references[reference] = true
+		return driver.RowsAffected(1), nil
+	case defaultDeleteReferenceStatement:
+		reference := args[0].Value.(string)
+		if !c.db.references[reference] {
+			return driver.RowsAffected(0), nil
+		}
+		delete(c.db.references, reference)
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("Unknown statement: %v", query)
+}
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	switch query {
+	case defaultSelectStatement:
+		var rows fakeRows
+		if value, ok := c.db.counters[args[0].Value.(string)]; ok {
+			rows = append(rows, value)
+		}
+		return &rows, nil
+	}
+	return nil, fmt.Errorf("Unknown statement: %v", query)
+}
+
+// fakeRows holds the values of a single column.
+type fakeRows []driver.Value
+
+func (r *fakeRows) Columns() []string { return []string{"value"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(*r) == 0 {
+		return io.EOF
+	}
+	dest[0] = (*r)[0]
+	*r = (*r)[1:]
+	return nil
+}
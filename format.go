@@ -0,0 +1,95 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"sync"
+)
+
+// DisplayFormat describes how amounts and IBANs are formatted outside of the
+// payment slip, e.g. in letters and tables. The payment slip and the QR code
+// always use the format prescribed by the style guide.
+type DisplayFormat struct {
+	GroupSeparator   string // Separates groups of three digits; default “ ”.
+	DecimalSeparator string // Default “.”.
+	IBANGroupSize    int    // Number of characters per IBAN group; default 4.
+	IBANSeparator    string // Separates IBAN groups; default “ ”.
+}
+
+var (
+	displayFormatsMu sync.RWMutex
+	displayFormats   = map[string]DisplayFormat{}
+)
+
+// RegisterDisplayFormat sets the format used by FormatAmount and FormatIBAN
+// for the given locale, which is matched against the language argument of
+// these functions. Empty fields of f keep their default value. Registering
+// a locale again replaces its format. The registration is global: it
+// applies to all calls afterwards in any goroutine, including the amounts and
+// accounts of rendered invoices, so call it during initialization.
+func RegisterDisplayFormat(locale string, f DisplayFormat) {
+	displayFormatsMu.Lock()
+	defer displayFormatsMu.Unlock()
+	displayFormats[locale] = f
+}
+
+// displayFormat returns the format for language with defaults filled in.
+func displayFormat(language Language) DisplayFormat {
+	displayFormatsMu.RLock()
+	f := displayFormats[string(language)]
+	displayFormatsMu.RUnlock()
+	if f.GroupSeparator == "" {
+		f.GroupSeparator = " "
+	}
+	if f.DecimalSeparator == "" {
+		f.DecimalSeparator = "."
+	}
+	if f.IBANGroupSize <= 0 {
+		f.IBANGroupSize = 4
+	}
+	if f.IBANSeparator == "" {
+		f.IBANSeparator = " "
+	}
+	return f
+}
+
+// amount formats an amount with two decimals using the separators of f.
+func (f DisplayFormat) amount(amount float64) string {
+	s := formatAmount(amount)
+	s = strings.Replace(s, ".", "\x00", 1)
+	s = strings.ReplaceAll(s, " ", f.GroupSeparator)
+	return strings.Replace(s, "\x00", f.DecimalSeparator, 1)
+}
+
+// iban splits an electronic IBAN into groups separated as given by f.
+func (f DisplayFormat) iban(code string) string {
+	groups := []string{}
+	for len(code) > f.IBANGroupSize {
+		groups = append(groups, code[:f.IBANGroupSize])
+		code = code[f.IBANGroupSize:]
+	}
+	groups = append(groups, code)
+	return strings.Join(groups, f.IBANSeparator)
+}
+
+// FormatIBAN formats an account number for display in the given language,
+// by default in groups of four characters as printed on the payment slip.
+func FormatIBAN(account AccountNumber, language Language) string {
+	if account.IBAN == nil {
+		return ""
+	}
+	return displayFormat(language).iban(account.IBAN.Code)
+}
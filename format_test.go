@@ -0,0 +1,44 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "testing"
+
+func TestDisplayFormat(t *testing.T) {
+	RegisterDisplayFormat("de-x-corporate", DisplayFormat{
+		GroupSeparator:   "’",
+		DecimalSeparator: ",",
+		IBANSeparator:    "-",
+	})
+	var testdata = []struct {
+		language Language
+		amount   string
+		iban     string
+	}{
+		{"de", "1 234 567.80", "CH58 0079 1123 0008 8901 2"},
+		{"de-x-corporate", "1’234’567,80", "CH58-0079-1123-0008-8901-2"},
+	}
+	for _, item := range testdata {
+		if actual := FormatAmount(1234567.8, CHF, item.language); actual != item.amount {
+			t.Errorf("Expected %q, got %q", item.amount, actual)
+		}
+		if actual := FormatIBAN(NewIBANOrDie("CH5800791123000889012"), item.language); actual != item.iban {
+			t.Errorf("Expected %q, got %q", item.iban, actual)
+		}
+	}
+	if actual := FormatIBAN(AccountNumber{}, "de"); actual != "" {
+		t.Errorf("Expected empty string, got %q", actual)
+	}
+}
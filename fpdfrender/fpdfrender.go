@@ -0,0 +1,213 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+// Package fpdfrender draws Swiss QR invoices onto documents of the fpdf
+// library, github.com/go-pdf/fpdf, with the same layout as swissqr.DrawInvoice
+// draws them onto gopdf canvases.
+//
+// A typical use places the invoice at the bottom of an A4 page:
+//
+//	doc := fpdf.New("P", "mm", "A4", "")
+//	doc.AddPage()
+//	err := fpdfrender.DrawInvoice(doc, 0, 297-105, data, swissqr.RenderOptions{Language: "de"})
+//
+// Text is set in the core fonts Helvetica and Helvetica-Bold, encoded as
+// cp1252, which covers the character set of the Swiss QR standard.
+package fpdfrender
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/krepost/swissqr"
+)
+
+// Height of the invoice in points.
+const invoiceHeight = 10.5 * swissqr.PointsPerCm
+
+// DrawInvoice draws the invoice with its upper left corner at x, y, given in
+// the unit of the document, onto the current page of doc. Errors of doc are
+// returned as well.
+func DrawInvoice(doc *fpdf.Fpdf, x, y float64, data swissqr.Payload, opts swissqr.RenderOptions) error {
+	if err := swissqr.RenderInvoice(New(doc, x, y), data, opts); err != nil {
+		return err
+	}
+	return doc.Error()
+}
+
+// New returns a renderer that draws onto the current page of doc, with the
+// upper left corner of the invoice at x, y in the unit of the document. Use
+// it with swissqr.RenderInvoice or the other functions taking a
+// swissqr.Renderer.
+func New(doc *fpdf.Fpdf, x, y float64) swissqr.Renderer {
+	return &renderer{
+		doc:       doc,
+		k:         doc.GetConversionRatio(),
+		x:         x,
+		y:         y,
+		translate: doc.UnicodeTranslatorFromDescriptor(""),
+		state:     state{lineWidth: 1},
+	}
+}
+
+// renderer implements swissqr.Renderer for fpdf. The coordinates of the
+// renderer, in points with the origin at the lower left corner of the
+// invoice, are converted to the unit and the downward y axis of fpdf by
+// user; translations and rotations are applied by the transformations of
+// fpdf.
+type renderer struct {
+	doc       *fpdf.Fpdf
+	k         float64 // Points per unit of the document.
+	x, y      float64 // Upper left corner of the invoice.
+	translate func(string) string
+	state     state
+	saved     []state // States saved by Push.
+}
+
+type state struct {
+	grey      float64
+	lineWidth float64
+	style     swissqr.FontStyle
+	size      float64
+}
+
+// user converts a point of the renderer to user coordinates of fpdf.
+func (r *renderer) user(x, y float64) (float64, float64) {
+	return r.x + x/r.k, r.y + (invoiceHeight-y)/r.k
+}
+
+func (r *renderer) Push() {
+	r.doc.TransformBegin()
+	r.saved = append(r.saved, r.state)
+}
+
+// Pop restores the graphics state of the document. fpdf keeps its own copy
+// of colours, line width and font, which TransformEnd does not restore, so
+// they are set again.
+func (r *renderer) Pop() {
+	r.doc.TransformEnd()
+	r.state = r.saved[len(r.saved)-1]
+	r.saved = r.saved[:len(r.saved)-1]
+	r.SetGrey(r.state.grey)
+	r.SetLineWidth(r.state.lineWidth)
+	if r.state.size > 0 {
+		r.setFont()
+		// SetFont does nothing if fpdf believes the font is current.
+		r.doc.SetFontSize(r.state.size)
+	}
+}
+
+func (r *renderer) Translate(x, y float64) {
+	r.doc.TransformTranslate(x/r.k, -y/r.k)
+}
+
+func (r *renderer) Rotate(angle float64) {
+	x, y := r.user(0, 0)
+	r.doc.TransformRotate(angle*180.0/math.Pi, x, y)
+}
+
+func (r *renderer) SetGrey(grey float64) {
+	r.state.grey = grey
+	level := int(grey*255.0 + 0.5)
+	r.doc.SetDrawColor(level, level, level)
+	r.doc.SetFillColor(level, level, level)
+	r.doc.SetTextColor(level, level, level)
+}
+
+func (r *renderer) SetLineWidth(width float64) {
+	r.state.lineWidth = width
+	r.doc.SetLineWidth(width / r.k)
+}
+
+func (r *renderer) SetFont(style swissqr.FontStyle, size float64) error {
+	r.state.style = style
+	r.state.size = size
+	r.setFont()
+	return r.doc.Error()
+}
+
+func (r *renderer) setFont() {
+	style := ""
+	if r.state.style == swissqr.BoldFont {
+		style = "B"
+	}
+	r.doc.SetFont("Helvetica", style, r.state.size)
+}
+
+func (r *renderer) FontName(style swissqr.FontStyle) string {
+	if style == swissqr.BoldFont {
+		return "Helvetica-Bold"
+	}
+	return "Helvetica"
+}
+
+func (r *renderer) TextWidth(s string) float64 {
+	return r.doc.GetStringWidth(r.translate(s)) * r.k
+}
+
+func (r *renderer) Text(x, y float64, s string) {
+	ux, uy := r.user(x, y)
+	r.doc.Text(ux, uy, r.translate(s))
+}
+
+func (r *renderer) Stroke(path *swissqr.Path) {
+	r.path(path)
+	r.doc.DrawPath("D")
+}
+
+func (r *renderer) Fill(path *swissqr.Path) {
+	r.path(path)
+	r.doc.DrawPath("F")
+}
+
+func (r *renderer) path(path *swissqr.Path) {
+	for _, segment := range path.Segments {
+		p := segment.Points
+		switch segment.Op {
+		case swissqr.MoveTo:
+			r.doc.MoveTo(r.user(p[0].X, p[0].Y))
+		case swissqr.LineTo:
+			r.doc.LineTo(r.user(p[0].X, p[0].Y))
+		case swissqr.CurveTo:
+			x1, y1 := r.user(p[0].X, p[0].Y)
+			x2, y2 := r.user(p[1].X, p[1].Y)
+			x3, y3 := r.user(p[2].X, p[2].Y)
+			r.doc.CurveBezierCubicTo(x1, y1, x2, y2, x3, y3)
+		case swissqr.ClosePath:
+			r.doc.ClosePath()
+		}
+	}
+}
+
+// Image embeds img as PNG. Images are named after the hash of their content,
+// so that an image drawn repeatedly is embedded only once.
+func (r *renderer) Image(img image.Image, minX, minY, maxX, maxY float64) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		r.doc.SetError(err)
+		return
+	}
+	name := fmt.Sprintf("swissqr-%x", sha256.Sum256(buffer.Bytes()))
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	r.doc.RegisterImageOptionsReader(name, options, &buffer)
+	x, y := r.user(minX, maxY)
+	r.doc.ImageOptions(name, x, y, (maxX-minX)/r.k, (maxY-minY)/r.k, false, options, 0, "")
+}
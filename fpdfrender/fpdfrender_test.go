@@ -0,0 +1,62 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package fpdfrender_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/krepost/swissqr"
+	"github.com/krepost/swissqr/fpdfrender"
+)
+
+func TestDrawInvoice(t *testing.T) {
+	account, err := swissqr.ParseIBAN("CH3709000000304442225")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := swissqr.Payload{
+		Account: swissqr.AccountNumber{IBAN: account},
+		Creditor: swissqr.Entity{
+			Name:        "Salvation Army Foundation Switzerland",
+			Address:     swissqr.CombinedAddress{AddressLine2: "3000 Bern"},
+			CountryCode: "CH",
+		},
+		CurrencyAmount: swissqr.PaymentAmount{Currency: swissqr.CHF},
+	}
+	for _, opts := range []swissqr.RenderOptions{
+		{Language: "de"},
+		{Language: "fr", Draft: true, Separator: swissqr.ScissorsSeparator},
+	} {
+		doc := fpdf.New("P", "mm", "A4", "")
+		doc.AddPage()
+		if err := fpdfrender.DrawInvoice(doc, 0, 297-105, data, opts); err != nil {
+			t.Errorf("Options %+v: unexpected error: %v", opts, err)
+		}
+		var buffer bytes.Buffer
+		if err := doc.Output(&buffer); err != nil {
+			t.Errorf("Options %+v: unexpected error: %v", opts, err)
+		}
+	}
+
+	doc := fpdf.New("P", "mm", "A4", "")
+	doc.AddPage()
+	if err := fpdfrender.DrawInvoice(doc, 0, 0, swissqr.Payload{}, swissqr.RenderOptions{}); err == nil {
+		t.Error("Expected error for empty payload")
+	}
+}
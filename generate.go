@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"io"
+	"slices"
+)
+
+// Option modifies the options of GeneratePDF.
+type Option func(*SeqOptions)
+
+// WithSeparator draws the given separator around the invoice.
+func WithSeparator(separator Separator) Option {
+	return func(o *SeqOptions) { o.Separator = separator }
+}
+
+// WithDraft renders an unpayable review copy; see RenderOptions.Draft.
+func WithDraft() Option {
+	return func(o *SeqOptions) { o.Draft = true }
+}
+
+// WithLayout overrides the geometry of the invoice.
+func WithLayout(layout Layout) Option {
+	return func(o *SeqOptions) { o.Layout = layout }
+}
+
+// WithVectorQR draws the QR code as paths; see RenderOptions.VectorQR.
+func WithVectorQR() Option {
+	return func(o *SeqOptions) { o.VectorQR = true }
+}
+
+// WithSlipOnly creates a page of the size of the invoice (210×105 mm)
+// instead of an A4 page with the invoice at the bottom.
+func WithSlipOnly() Option {
+	return func(o *SeqOptions) { o.SlipOnly = true }
+}
+
+// WithRenderOptions replaces all render options, including the language.
+func WithRenderOptions(opts RenderOptions) Option {
+	return func(o *SeqOptions) { o.RenderOptions = opts }
+}
+
+// GeneratePDF writes a PDF document with the invoice in the given language
+// to w. By default, the document has one A4 page with the invoice at the
+// bottom and no separator; the options change this, e.g.
+//
+//	err := swissqr.GeneratePDF(w, data, "de", swissqr.WithSeparator(swissqr.ScissorsSeparator))
+func GeneratePDF(w io.Writer, data Payload, language Language, opts ...Option) error {
+	o := SeqOptions{RenderOptions: RenderOptions{Language: language}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	return RenderSeq(slices.Values([]Payload{data}), w, o)
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePDF(t *testing.T) {
+	for i, opts := range [][]Option{
+		nil,
+		{WithSeparator(ScissorsSeparator), WithSlipOnly()},
+		{WithSeparator(BorderSeparator), WithDraft(), WithVectorQR()},
+		{WithLayout(Layout{OffsetX: 5}), WithRenderOptions(RenderOptions{Language: "fr"})},
+	} {
+		var buffer bytes.Buffer
+		if err := GeneratePDF(&buffer, examplePayload2, "de", opts...); err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+		}
+		if !bytes.HasPrefix(buffer.Bytes(), []byte("%PDF")) {
+			t.Errorf("Item %v: expected PDF, got: %q", i, buffer.String())
+		}
+	}
+
+	var testdata = []struct {
+		data     Payload
+		language Language
+		opts     []Option
+		err      string
+	}{
+		{Payload{}, "de", nil, "No account specified"},
+		{examplePayload1, "de", []Option{WithLayout(Layout{MinLeading: 2})}, "Minimum leading"},
+	}
+	for i, item := range testdata {
+		var buffer bytes.Buffer
+		err := GeneratePDF(&buffer, item.data, item.language, item.opts...)
+		if err == nil || !strings.Contains(err.Error(), item.err) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, item.err, err)
+		}
+		// Options and payloads are checked before anything is rendered.
+		if buffer.Len() != 0 {
+			t.Errorf("Item %v: expected no output, got %v bytes", i, buffer.Len())
+		}
+	}
+
+	var buffer bytes.Buffer
+	language := "it"
+	if err := GeneratePDFString(&buffer, examplePayload2, language); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Handler is an http.Handler that renders invoices for web services. It
+// accepts a Payload in the JSON representation of this package in the body
+// of a POST request and responds with a PDF document like RenderSeq or a
+// PNG image like RenderImageWithOptions, as selected by the Accept header of
+// the request; PDF is the default. The query parameter “lang” selects the
+// language of the invoice instead of the options.
+//
+// Errors are reported as JSON object {"Error": "…"} with status 400 for
+// malformed requests, 405 for other methods than POST, 406 if neither PDF
+// nor PNG is acceptable, 413 for too large bodies and 422 for payloads
+// that do not validate.
+type Handler struct {
+	// Options apply to each invoice.
+	Options SeqOptions
+
+	// DPI is the resolution of PNG images; the default is 150 dpi.
+	DPI int
+
+	// MaxBodySize limits the size of the request body; the default is
+	// 64 KiB, which is plenty for any valid payload.
+	MaxBodySize int64
+}
+
+// Media types produced by Handler.
+const (
+	pdfMediaType = "application/pdf"
+	pngMediaType = "image/png"
+)
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeHTTPError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method not allowed: %v", r.Method))
+		return
+	}
+	mediaType := negotiateMediaType(r.Header.Get("Accept"), pdfMediaType, pngMediaType)
+	if mediaType == "" {
+		writeHTTPError(w, http.StatusNotAcceptable, errors.New("Only application/pdf and image/png can be produced"))
+		return
+	}
+	maxBodySize := h.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = 64 << 10
+	}
+	var data Payload
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err := decoder.Decode(&data); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeHTTPError(w, http.StatusRequestEntityTooLarge, err)
+		} else {
+			writeHTTPError(w, http.StatusBadRequest, err)
+		}
+		return
+	}
+	opts := h.Options
+	if language := r.URL.Query().Get("lang"); language != "" {
+		opts.Language = Language(language)
+	}
+	if err := opts.RenderOptions.Validate(); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := data.Validate(); err != nil {
+		writeHTTPError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var buffer bytes.Buffer
+	switch mediaType {
+	case pdfMediaType:
+		if err := RenderSeq(slices.Values([]Payload{data}), &buffer, opts); err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+	case pngMediaType:
+		dpi := h.DPI
+		if dpi == 0 {
+			dpi = 150
+		}
+		img, err := RenderImageWithOptions(data, opts.RenderOptions, dpi)
+		if err == nil {
+			err = png.Encode(&buffer, img)
+		}
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(buffer.Len()))
+	w.Header().Set("Vary", "Accept")
+	w.Write(buffer.Bytes())
+}
+
+// writeHTTPError responds with the error as JSON object.
+func writeHTTPError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct{ Error string }{err.Error()})
+}
+
+// negotiateMediaType returns the offered media type with the highest
+// quality in the Accept header, or the empty string if none is acceptable.
+// The first offer is preferred for equal quality and for an empty header.
+func negotiateMediaType(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		quality, specificity := 0.0, -1
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			s := mediaRangeSpecificity(mediaType, offer)
+			if s <= specificity {
+				continue
+			}
+			specificity = s
+			quality = 1.0
+			if q, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					quality = 0
+				}
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
+// mediaRangeSpecificity returns 2 if the media range equals the media type,
+// 1 for a range “type/*” and 0 for “*/*” that covers the type, and -1 if
+// the range does not cover the type.
+func mediaRangeSpecificity(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") &&
+		strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	body, err := json.Marshal(examplePayload2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := Handler{Options: SeqOptions{RenderOptions: RenderOptions{Language: "de"}}, DPI: 50}
+	var testdata = []struct {
+		method      string
+		target      string
+		accept      string
+		body        string
+		status      int
+		contentType string
+	}{
+		{"POST", "/", "", string(body), 200, "application/pdf"},
+		{"POST", "/", "image/png", string(body), 200, "image/png"},
+		{"POST", "/?lang=fr", "image/*;q=0.9, application/pdf;q=0.5", string(body), 200, "image/png"},
+		{"POST", "/", "text/html, */*;q=0.1", string(body), 200, "application/pdf"},
+		{"POST", "/", "text/html", string(body), 406, "application/json; charset=utf-8"},
+		{"POST", "/", "application/pdf;q=0, image/png;q=0", string(body), 406, "application/json; charset=utf-8"},
+		{"GET", "/", "", "", 405, "application/json; charset=utf-8"},
+		{"POST", "/", "", "{", 400, "application/json; charset=utf-8"},
+		{"POST", "/?lang=xx", "", string(body), 200, "application/pdf"},
+		{"POST", "/", "", "{}", 422, "application/json; charset=utf-8"},
+		{"POST", "/", "", strings.Repeat(" ", 70<<10) + "{}", 413, "application/json; charset=utf-8"},
+	}
+	for i, item := range testdata {
+		request := httptest.NewRequest(item.method, item.target, strings.NewReader(item.body))
+		if item.accept != "" {
+			request.Header.Set("Accept", item.accept)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != item.status {
+			t.Errorf("Item %v: expected status %v, got: %v %v", i, item.status, recorder.Code, recorder.Body)
+		}
+		if got := recorder.Header().Get("Content-Type"); got != item.contentType {
+			t.Errorf("Item %v: expected content type %v, got: %v", i, item.contentType, got)
+		}
+		switch {
+		case item.status != 200:
+			var response struct{ Error string }
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Error == "" {
+				t.Errorf("Item %v: expected error response, got: %v", i, recorder.Body)
+			}
+		case item.contentType == "image/png":
+			if !bytes.HasPrefix(recorder.Body.Bytes(), []byte("\x89PNG")) {
+				t.Errorf("Item %v: expected PNG image", i)
+			}
+		default:
+			if !bytes.HasPrefix(recorder.Body.Bytes(), []byte("%PDF")) {
+				t.Errorf("Item %v: expected PDF, got: %v", i, recorder.Body)
+			}
+		}
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("PUT", "/", nil))
+	if got := recorder.Header().Get("Allow"); got != "POST" {
+		t.Errorf("Expected Allow: POST, got: %v", got)
+	}
+}
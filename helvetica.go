@@ -44,16 +44,21 @@ func reflowAtRune(lines []string, maxWidth float64) []string {
 }
 
 func reflowAtSpace(lines []string, maxWidth float64) []string {
+	return reflowAtSpaceWith(lines, maxWidth, stringWidth)
+}
+
+// reflowAtSpaceWith implements reflowAtSpace for the text measure width.
+func reflowAtSpaceWith(lines []string, maxWidth float64, width func(string) float64) []string {
 	reflowed := []string{}
-	spaceWidth, _ := regularWX[' ']
+	spaceWidth := width(" ")
 	for _, line := range lines {
-		if stringWidth(line) < maxWidth {
+		if width(line) < maxWidth {
 			reflowed = append(reflowed, line)
 		} else {
 			currentLine := ""
 			currentWidth := 0.0
 			for _, word := range strings.Fields(line) {
-				w := stringWidth(word)
+				w := width(word)
 				if w > maxWidth {
 					// Switch to “reflowAtRune” algorithm.
 					if currentLine != "" {
@@ -61,10 +66,7 @@ func reflowAtSpace(lines []string, maxWidth float64) []string {
 						currentLine = currentLine + " "
 					}
 					for _, r := range word {
-						w := 1.0 // Default.
-						if m, ok := regularWX[r]; ok {
-							w = m
-						}
+						w := width(string(r))
 						if currentWidth+w > maxWidth {
 							reflowed = append(reflowed, currentLine)
 							currentLine = ""
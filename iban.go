@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IBAN is an International Bank Account Number according to ISO 13616.
+type IBAN struct {
+	// Code is the IBAN in electronic format, e.g. “CH5800791123000889012”.
+	Code string
+
+	// PrintCode is the IBAN in groups of four characters, e.g.
+	// “CH58 0079 1123 0008 8901 2”.
+	PrintCode string
+
+	// CountryCode, CheckDigits and BBAN are the parts of Code: the
+	// two-letter country code, the two check digits and the basic bank
+	// account number.
+	CountryCode string
+	CheckDigits string
+	BBAN        string
+}
+
+// ParseIBAN parses an IBAN in electronic or print format; spaces are
+// ignored and letters may be lower case. It verifies the length for the
+// country and the MOD-97 check digits.
+func ParseIBAN(s string) (*IBAN, error) {
+	code := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(code) < 5 {
+		return nil, fmt.Errorf("IBAN too short: %v", s)
+	}
+	for _, r := range code {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return nil, fmt.Errorf("IBAN may only contain letters A-Z and digits 0-9: %v", s)
+		}
+	}
+	countryCode := code[:2]
+	length, ok := ibanLengths[countryCode]
+	if !ok {
+		return nil, fmt.Errorf("Unknown IBAN country code %v: %v", countryCode, s)
+	}
+	if len(code) != length {
+		return nil, fmt.Errorf("%v IBAN must have %d characters: %v", countryCode, length, s)
+	}
+	if !isDigits(code[2:4]) || mod97(code[4:]+code[:4]) != 1 {
+		return nil, fmt.Errorf("Invalid IBAN check digits: %v", s)
+	}
+	var groups []string
+	for i := 0; i < len(code); i += 4 {
+		groups = append(groups, code[i:min(i+4, len(code))])
+	}
+	return &IBAN{
+		Code:        code,
+		PrintCode:   strings.Join(groups, " "),
+		CountryCode: countryCode,
+		CheckDigits: code[2:4],
+		BBAN:        code[4:],
+	}, nil
+}
+
+// IID returns the bank clearing number (IID) of a CH or LI IBAN, the first
+// five digits of the BBAN, or an empty string for other countries.
+func (i *IBAN) IID() string {
+	if (i.CountryCode != "CH" && i.CountryCode != "LI") || len(i.BBAN) < 5 {
+		return ""
+	}
+	return i.BBAN[:5]
+}
+
+// ibanLengths contains the length of the IBAN of each country, as listed in
+// the IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28,
+	"CZ": 24, "DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24,
+	"FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18,
+	"GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23,
+	"IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32,
+	"LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24, "ME": 22,
+	"MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24,
+	"SC": 31, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIBAN(t *testing.T) {
+	var testdata = []struct {
+		input     string
+		code      string
+		printCode string
+		iid       string
+	}{
+		{"CH5800791123000889012", "CH5800791123000889012", "CH58 0079 1123 0008 8901 2", "00791"},
+		{"ch44 3199 9123 0008 8901 2", "CH4431999123000889012", "CH44 3199 9123 0008 8901 2", "31999"},
+		{"LI21 0881 0000 2324 013A A", "LI21088100002324013AA", "LI21 0881 0000 2324 013A A", "08810"},
+		{"DE91100000000123456789", "DE91100000000123456789", "DE91 1000 0000 0123 4567 89", ""},
+	}
+	for i, data := range testdata {
+		actual, err := ParseIBAN(data.input)
+		if err != nil {
+			t.Errorf("Item %v: expected no error; got %v", i, err)
+			continue
+		}
+		if actual.Code != data.code || actual.PrintCode != data.printCode || actual.IID() != data.iid {
+			t.Errorf("Item %v: expected %v, %v, %v; got %+v, %v", i, data.code, data.printCode, data.iid, actual, actual.IID())
+		}
+	}
+}
+
+func TestParseIBANErrors(t *testing.T) {
+	var testdata = []struct {
+		input   string
+		message string
+	}{
+		{"CH58", "IBAN too short"},
+		{"CH58-0079-1123-0008-8901-2", "IBAN may only contain letters A-Z and digits 0-9"},
+		{"XX5800791123000889012", "Unknown IBAN country code XX"},
+		{"CH580079112300088901", "CH IBAN must have 21 characters"},
+		{"CH5900791123000889012", "Invalid IBAN check digits"},
+	}
+	for i, data := range testdata {
+		_, err := ParseIBAN(data.input)
+		if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
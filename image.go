@@ -16,9 +16,11 @@ package swissqr
 
 import (
 	"bytes"
+	"errors"
 	"image"
 	"image/color"
 	"image/draw"
+	"strings"
 
 	"github.com/boombuler/barcode"
 	barcode_qr "github.com/boombuler/barcode/qr"
@@ -32,7 +34,83 @@ func CreateQR(data Payload) (image.Image, error) {
 	if err := data.Serialize(&buffer); err != nil {
 		return nil, err
 	}
+	return encodeQR(buffer.String())
+}
+
+// PayloadStats returns the number of characters of the additional
+// information of the payload, that is the unstructured message and the
+// bill information, which may not exceed 140 characters, as well as the
+// version of the QR code of the serialized payload, which may not exceed
+// 25. The number of characters is computed even if the payload is invalid,
+// so that it can be shown while a message is being composed; qrVersion is
+// zero in that case and err is the validation error.
+func PayloadStats(p Payload) (chars int, qrVersion int, err error) {
+	chars = p.AdditionalInformation.Length()
+	var buffer bytes.Buffer
+	if err := p.Serialize(&buffer); err != nil {
+		return chars, 0, err
+	}
 	qrCode, err := barcode_qr.Encode(buffer.String(), barcode_qr.M, barcode_qr.Unicode)
+	if err != nil {
+		return chars, 0, err
+	}
+	// A QR code of version v has 17+4v modules per side.
+	return chars, (qrCode.Bounds().Dx() - 17) / 4, nil
+}
+
+// CreateQRWithOptions creates a QR code image like CreateQR. If the options
+// select a draft, the QR code is printed in grey and cannot be paid.
+func CreateQRWithOptions(data Payload, opts RenderOptions) (image.Image, error) {
+	if opts.Draft {
+		return createDraftQR(data, previewGrey)
+	}
+	return CreateQR(data)
+}
+
+// previewGrey is the grey level of drafts.
+const previewGrey = 0.6
+
+// createDraftQR creates a QR code that looks like the QR code for the given
+// payload, but is printed in grey and cannot be paid: the “SPC” header is
+// replaced, so that banking software rejects the code.
+func createDraftQR(data Payload, grey float32) (image.Image, error) {
+	var buffer bytes.Buffer
+	if err := data.Serialize(&buffer); err != nil {
+		return nil, err
+	}
+	img, err := encodeQR(strings.Replace(buffer.String(), "SPC", "DRAFT", 1))
+	if err != nil {
+		return nil, err
+	}
+	// Lighten all pixels towards white.
+	level := uint32(grey * 0xffff)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			v := uint32(img.Gray16At(x, y).Y)
+			img.SetGray16(x, y, color.Gray16{uint16(v + (0xffff-v)*level/0xffff)})
+		}
+	}
+	return img, nil
+}
+
+// qrModules encodes the payload as QR code with one pixel per module,
+// without the Swiss cross. For drafts, the “SPC” header is replaced, so that
+// banking software rejects the code.
+func qrModules(data Payload, draft bool) (barcode.Barcode, error) {
+	var buffer bytes.Buffer
+	if err := data.Serialize(&buffer); err != nil {
+		return nil, err
+	}
+	text := buffer.String()
+	if draft {
+		text = strings.Replace(text, "SPC", "DRAFT", 1)
+	}
+	return barcode_qr.Encode(text, barcode_qr.M, barcode_qr.Unicode)
+}
+
+// encodeQR encodes text as a Swiss QR code image.
+func encodeQR(text string) (*image.Gray16, error) {
+	qrCode, err := barcode_qr.Encode(text, barcode_qr.M, barcode_qr.Unicode)
 	if err != nil {
 		return nil, err
 	}
@@ -42,19 +120,55 @@ func CreateQR(data Payload) (image.Image, error) {
 	}
 	swissQr := image.NewGray16(image.Rect(0, 0, 1086, 1086))
 	draw.Draw(swissQr, swissQr.Bounds(), qrCode, image.ZP, draw.Src)
-	// Draw 166×166 pixels Swiss cross at the center of the QR code. This
-	// produces the symbol which is published at www.paymentstandards.ch.
-	swissCross := []struct {
-		rect image.Rectangle
-		img  image.Image
-	}{
-		{image.Rect(460, 460, 626, 626), &image.Uniform{color.White}},
-		{image.Rect(472, 472, 614, 614), &image.Uniform{color.Black}},
-		{image.Rect(496, 526, 590, 554), &image.Uniform{color.White}},
-		{image.Rect(528, 494, 558, 586), &image.Uniform{color.White}},
-	}
 	for _, elem := range swissCross {
-		draw.Draw(swissQr, elem.rect, elem.img, image.ZP, draw.Src)
+		draw.Draw(swissQr, elem.rect, &image.Uniform{elem.color}, image.ZP, draw.Src)
 	}
 	return swissQr, nil
 }
+
+// checkQRImage checks that img, a square image created by CreateQR in any
+// size, is the QR code of the payload: the pixel at the center of each
+// module of the QR code must be dark exactly if the module is, except below
+// the Swiss cross.
+func checkQRImage(img image.Image, data Payload) error {
+	modules, err := qrModules(data, false)
+	if err != nil {
+		return err
+	}
+	n, bounds := modules.Bounds().Dx(), img.Bounds()
+	size := bounds.Dx()
+	if size != bounds.Dy() || size < n {
+		return errors.New("QR image does not match the payload")
+	}
+	// The modules are scaled to whole pixels and centered, as by CreateQR.
+	moduleSize := size / n
+	offset := (size - n*moduleSize) / 2
+	cross := swissCross[0].rect
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			px := offset + x*moduleSize + moduleSize/2
+			py := offset + y*moduleSize + moduleSize/2
+			if image.Pt(px*1086/size, py*1086/size).In(cross) {
+				continue
+			}
+			grey := color.Gray16Model.Convert(img.At(bounds.Min.X+px, bounds.Min.Y+py)).(color.Gray16)
+			if (grey.Y < 0x8000) != (modules.At(x, y) == color.Black) {
+				return errors.New("QR image does not match the payload")
+			}
+		}
+	}
+	return nil
+}
+
+// swissCross is the 166×166 pixels Swiss cross at the center of a QR code of
+// 1086×1086 pixels. This produces the symbol which is published at
+// www.paymentstandards.ch.
+var swissCross = []struct {
+	rect  image.Rectangle
+	color color.Color
+}{
+	{image.Rect(460, 460, 626, 626), color.White},
+	{image.Rect(472, 472, 614, 614), color.Black},
+	{image.Rect(496, 526, 590, 554), color.White},
+	{image.Rect(528, 494, 558, 586), color.White},
+}
@@ -52,3 +52,39 @@ func TestCreateImageFromInvalidData(t *testing.T) {
 		t.Errorf("Expected error due to no creditor name; got %v", err)
 	}
 }
+
+func TestCreateDraftQR(t *testing.T) {
+	img, err := createDraftQR(examplePayload1, 0.6)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The center of the Swiss cross is black in a regular QR code.
+	r, g, b, _ := img.At(543, 480).RGBA()
+	if r != g || g != b || r < 0x9000 || r > 0xa000 {
+		t.Errorf("Expected grey pixel, got %v %v %v", r, g, b)
+	}
+}
+
+func TestPayloadStats(t *testing.T) {
+	chars, version, err := PayloadStats(examplePayload3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info := examplePayload3.AdditionalInformation
+	if expected := len(info.UnstructuredMessage + info.StructuredMessage.ToString()); chars != expected {
+		t.Errorf("Expected %v characters, got %v", expected, chars)
+	}
+	if version < 1 || version > 25 {
+		t.Errorf("Unexpected QR version: %v", version)
+	}
+
+	tooLong := examplePayload3
+	tooLong.AdditionalInformation.UnstructuredMessage = strings.Repeat("x", 141)
+	chars, version, err = PayloadStats(tooLong)
+	if err == nil || !strings.HasPrefix(err.Error(), "Maximum combined length is 140") {
+		t.Errorf("Expected error due to message length, got: %v", err)
+	}
+	if chars <= 140 || version != 0 {
+		t.Errorf("Unexpected stats for invalid payload: %v, %v", chars, version)
+	}
+}
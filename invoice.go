@@ -16,8 +16,10 @@ package swissqr
 
 import (
 	"fmt"
-	"github.com/krepost/structref"
 	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
 )
 
 const (
@@ -37,6 +39,9 @@ type AmountSectionData struct {
 	CurrencyValue   string
 	AmountHeading   string
 	AmountValue     string
+
+	// EmptyBox is set if a box is to be printed instead of the amount.
+	EmptyBox bool
 }
 
 // TitleSectionData represents the title sections on the payment slip.
@@ -46,7 +51,7 @@ type TitleSectionData struct {
 }
 
 // AmountSection returns the payment amount.
-func AmountSection(p Payload, language string) (AmountSectionData, error) {
+func AmountSection(p Payload, language Language) (AmountSectionData, error) {
 	if err := p.Validate(); err != nil {
 		return AmountSectionData{}, err
 	}
@@ -54,32 +59,53 @@ func AmountSection(p Payload, language string) (AmountSectionData, error) {
 		return AmountSectionData{}, err
 	}
 	amt := AmountSectionData{
-		CurrencyHeading: headings[currency][language],
+		CurrencyHeading: localized(CurrencyHeading, language),
 		CurrencyValue:   p.CurrencyAmount.Currency,
-		AmountHeading:   headings[amount][language],
-	}
-	if p.CurrencyAmount.Amount > 0.0 {
-		s := fmt.Sprintf("%.2f", p.CurrencyAmount.Amount)
-		dot := strings.IndexByte(s, '.')
-		if dot > 0 {
-			firstSpace := dot % 3
-			spaced := s[:firstSpace]
-			for j := firstSpace; j < dot; j += 3 {
-				if spaced != "" {
-					spaced = spaced + " "
-				}
-				spaced = spaced + s[j:j+3]
+		AmountHeading:   localized(AmountHeading, language),
+	}
+	switch {
+	case p.CurrencyAmount.Amount > 0.0 || p.CurrencyAmount.Mode == AmountZero:
+		amt.AmountValue = formatAmount(p.CurrencyAmount.Amount)
+	case p.CurrencyAmount.Mode == AmountBox:
+		amt.EmptyBox = true
+	}
+	return amt, nil
+}
+
+// FormatAmount formats an amount for letters and tables, by default
+// exactly as it is printed on the payment slip, e.g. “3 949.75”: two
+// decimals after a decimal point, and the digits before it grouped in
+// threes separated by spaces. A different format can be registered for a
+// language with RegisterDisplayFormat; the payment slip is not affected.
+func FormatAmount(amount float64, currency string, language Language) string {
+	return displayFormat(language).amount(amount)
+}
+
+// formatAmount formats an amount with two decimals and groups the digits
+// before the decimal point in groups of three separated by spaces.
+func formatAmount(amount float64) string {
+	if amount < 0.0 {
+		return "-" + formatAmount(-amount)
+	}
+	s := fmt.Sprintf("%.2f", amount)
+	dot := strings.IndexByte(s, '.')
+	if dot > 0 {
+		firstSpace := dot % 3
+		spaced := s[:firstSpace]
+		for j := firstSpace; j < dot; j += 3 {
+			if spaced != "" {
+				spaced = spaced + " "
 			}
-			spaced = spaced + s[dot:]
-			s = spaced
+			spaced = spaced + s[j:j+3]
 		}
-		amt.AmountValue = s
+		spaced = spaced + s[dot:]
+		s = spaced
 	}
-	return amt, nil
+	return s
 }
 
 // TitleSection returns the titles of the receipt and payment parts.
-func TitleSection(p Payload, language string) (TitleSectionData, error) {
+func TitleSection(p Payload, language Language) (TitleSectionData, error) {
 	if err := p.Validate(); err != nil {
 		return TitleSectionData{}, err
 	}
@@ -87,17 +113,92 @@ func TitleSection(p Payload, language string) (TitleSectionData, error) {
 		return TitleSectionData{}, err
 	}
 	return TitleSectionData{
-		PaymentPart: headings[paymentPart][language],
-		Receipt:     headings[receipt][language],
+		PaymentPart: localized(PaymentPartHeading, language),
+		Receipt:     localized(ReceiptHeading, language),
 	}, nil
 }
 
+// PointsPerCm is the number of points, the unit of font sizes and of PDF
+// coordinates, per centimeter.
+const PointsPerCm = 28.35
+
+// WidthForPaymentPart returns the width of the information section of the
+// payment part, 8.5 cm, as a multiple of the given font size in points. Pass
+// it as width to InformationSection; the payment part uses 10 pt text.
+func WidthForPaymentPart(fontSize float64) float64 {
+	return 8.5 * PointsPerCm / fontSize
+}
+
+// WidthForReceipt returns the width of the information section of the
+// receipt, 5.2 cm, as a multiple of the given font size in points. Pass it
+// as width to InformationSection; the receipt uses 8 pt text.
+func WidthForReceipt(fontSize float64) float64 {
+	return 5.2 * PointsPerCm / fontSize
+}
+
 // InformationSection returns a slice of paragraphs to be rendered on the
 // payment slip. The value of info (paymentPartInformation or
 // receiptPartInformation) determines if information for the payment part
-// or receipt part should be returned.
-func InformationSection(p Payload, language string,
+// or receipt part should be returned. Lines are reflowed to width, given as
+// a multiple of the font size in Helvetica.
+func InformationSection(p Payload, language Language,
 	width float64, info int) ([]Paragraph, error) {
+	section, _, err := informationSection(p, language, width, info, Layout{})
+	return section, err
+}
+
+// InformationLines returns the same paragraphs as InformationSection, but
+// without reflow: each line is one logical line of the invoice. Renderers
+// that use other fonts than the PDF renderer can wrap the lines with Reflow.
+func InformationLines(p Payload, language Language, info int) ([]Paragraph, error) {
+	return informationLines(p, language, info, ForeignCountryLine, nil)
+}
+
+// Reflow returns a copy of section with the lines of each paragraph broken
+// at spaces so that no line is wider than maxWidth. Words that are wider
+// than maxWidth are broken between characters. The function width measures
+// text in the unit of maxWidth; if it is nil, the Helvetica metrics of the
+// payment slip are used, relative to the font size.
+func Reflow(section []Paragraph, maxWidth float64, width func(string) float64) []Paragraph {
+	if width == nil {
+		width = stringWidth
+	}
+	reflowed := make([]Paragraph, 0, len(section))
+	for _, paragraph := range section {
+		reflowed = append(reflowed, Paragraph{
+			Heading: paragraph.Heading,
+			Lines:   reflowAtSpaceWith(paragraph.Lines, maxWidth, width),
+		})
+	}
+	return reflowed
+}
+
+// informationSection implements InformationSection; the layout selects
+// when the country name is printed below the addresses and how address
+// lines wider than width are abbreviated before the reflow. It also returns
+// the abbreviations made.
+func informationSection(p Payload, language Language,
+	width float64, info int, layout Layout) ([]Paragraph, []string, error) {
+	var abbreviations []string
+	abbreviate := func(lines []string) []string {
+		lines, changes := AbbreviateAddress(lines, width, layout.AddressAbbreviation)
+		abbreviations = append(abbreviations, changes...)
+		return lines
+	}
+	section, err := informationLines(p, language, info, layout.CountryLine, abbreviate)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Reflow(section, width, stringWidth), abbreviations, nil
+}
+
+// informationLines implements InformationLines. If address is not nil, it
+// is applied to the lines of the addresses.
+func informationLines(p Payload, language Language,
+	info int, country CountryLine, address func([]string) []string) ([]Paragraph, error) {
+	if address == nil {
+		address = func(lines []string) []string { return lines }
+	}
 	if err := p.Validate(); err != nil {
 		return nil, err
 	}
@@ -105,21 +206,26 @@ func InformationSection(p Payload, language string,
 		return nil, err
 	}
 	lines := []string{p.Account.IBAN.PrintCode}
-	if payableTo, err := p.Creditor.ToLines(); err != nil {
+	if payableTo, err := p.Creditor.ToLocalizedLines(language, country); err != nil {
 		return nil, err
 	} else {
-		lines = append(lines, payableTo...)
+		lines = append(lines, address(payableTo)...)
 	}
 	sections := []Paragraph{Paragraph{
-		Heading: headings[accountPayableTo][language],
-		Lines:   reflowAtSpace(lines, width),
+		Heading: localized(AccountPayableToHeading, language),
+		Lines:   lines,
 	}}
-	switch v := p.Reference.Number.(type) {
-	case *structref.ReferenceNumber, *structref.CreditorReference:
-		lines := []string{v.PrintFormat()}
+	if paragraph, err := inFavourOf(p.UltimateCreditor, language, country, address); err != nil {
+		return nil, err
+	} else if paragraph != nil {
+		sections = append(sections, *paragraph)
+	}
+	switch p.Reference.Type() {
+	case "QRR", "SCOR":
+		lines := []string{p.Reference.Number.PrintFormat()}
 		sections = append(sections, Paragraph{
-			Heading: headings[reference][language],
-			Lines:   reflowAtSpace(lines, width),
+			Heading: localized(ReferenceHeading, language),
+			Lines:   lines,
 		})
 	}
 	if info == paymentPartInformation {
@@ -127,46 +233,105 @@ func InformationSection(p Payload, language string,
 		if s := p.AdditionalInformation.UnstructuredMessage; len(s) > 0 {
 			lines = append(lines, s)
 		}
-		if s := p.AdditionalInformation.StructuredMessage.ToString(); len(s) > 0 {
+		if s := p.AdditionalInformation.BillInformation(); len(s) > 0 {
 			lines = append(lines, s)
 		}
 		if len(lines) > 0 {
 			sections = append(sections, Paragraph{
-				Heading: headings[additionalInformation][language],
-				Lines:   reflowAtSpace(lines, width),
+				Heading: localized(AdditionalInformationHeading, language),
+				Lines:   lines,
 			})
 		}
 	}
 	// The debtor section is mandatory and should be drawn as
 	// a box by the client if the debtor information is empty.
-	if lines, err := p.UltimateDebtor.ToLines(); err != nil {
+	if lines, err := p.UltimateDebtor.ToLocalizedLines(language, country); err != nil {
 		return nil, err
 	} else {
 		if len(lines) == 0 {
 			sections = append(sections, Paragraph{
-				Heading: headings[payableByNameAddress][language],
+				Heading: localized(PayableByNameAddressHeading, language),
 				Lines:   []string{},
 			})
 		} else {
 			sections = append(sections, Paragraph{
-				Heading: headings[payableBy][language],
-				Lines:   reflowAtSpace(lines, width),
+				Heading: localized(PayableByHeading, language),
+				Lines:   address(lines),
 			})
 		}
 	}
 	return sections, nil
 }
 
+// inFavourOf returns the paragraph “In favour of” for the ultimate creditor,
+// which follows the creditor on both the payment part and the receipt, or
+// nil if there is no ultimate creditor. Validate rejects ultimate creditors
+// unless Payload.EnableUltimateCreditor is set, as long as the standard
+// reserves the field for future use.
+func inFavourOf(e Entity, language Language,
+	country CountryLine, address func([]string) []string) (*Paragraph, error) {
+	lines, err := e.ToLocalizedLines(language, country)
+	if err != nil || len(lines) == 0 {
+		return nil, err
+	}
+	return &Paragraph{
+		Heading: localized(InFavourOfHeading, language),
+		Lines:   address(lines),
+	}, nil
+}
+
+// Content contains the complete text of the payment part.
+type Content struct {
+	Title  TitleSectionData
+	Amount AmountSectionData
+
+	// Information contains the paragraphs of the payment part, without
+	// reflow: each line is one logical line of the invoice.
+	Information []Paragraph
+
+	// AlternativeProcedures contains one paragraph per alternative
+	// procedure, with the localized label as heading.
+	AlternativeProcedures []Paragraph
+}
+
+// PaymentPartContent returns the text of the payment part as structured
+// data, for channels that present the invoice without rendering it, such
+// as chatbots, e-banking links or accessibility tools.
+func PaymentPartContent(p Payload, language Language) (Content, error) {
+	var content Content
+	var err error
+	if content.Title, err = TitleSection(p, language); err != nil {
+		return Content{}, err
+	}
+	if content.Amount, err = AmountSection(p, language); err != nil {
+		return Content{}, err
+	}
+	content.Information, err = informationLines(p, language,
+		paymentPartInformation, ForeignCountryLine, nil)
+	if err != nil {
+		return Content{}, err
+	}
+	for _, ap := range p.AlternativeProcedureParameters {
+		content.AlternativeProcedures = append(content.AlternativeProcedures, Paragraph{
+			Heading: ap.LocalizedLabel(language),
+			Lines:   []string{ap.Procedure},
+		})
+	}
+	return content, nil
+}
+
 // BorderText returns the text that is to be printed above the QR invoice.
-func BorderText(language string) (string, error) {
+func BorderText(language Language) (string, error) {
 	if err := checkLanguage(language); err != nil {
 		return "", err
 	}
-	return headings[pleaseSeparate][language], nil
+	return localized(PleaseSeparateHeading, language), nil
 }
 
 // ToLines converts an Entity to a set of lines suitable for display
-// on a payment slip. It is assumed that the Entity is valid.
+// on a payment slip. It is assumed that the Entity is valid. The lines do
+// not include the country; use ToLocalizedLines to add the country name for
+// foreign addresses as the payment part does.
 func (e Entity) ToLines() ([]string, error) {
 	lines := []string{}
 	if len(e.Name) > 0 {
@@ -190,3 +355,52 @@ func (e Entity) ToLines() ([]string, error) {
 	}
 	return lines, nil
 }
+
+// CountryLine selects whether the name of the country is printed as last
+// line of an address.
+type CountryLine int
+
+const (
+	// ForeignCountryLine prints the country name for addresses outside
+	// Switzerland and Liechtenstein, as recommended by the style guide.
+	ForeignCountryLine CountryLine = iota
+
+	// AlwaysCountryLine prints the country name for all addresses.
+	AlwaysCountryLine
+
+	// NoCountryLine never prints the country name.
+	NoCountryLine
+)
+
+// ToLocalizedLines converts an Entity to a set of lines like ToLines, and
+// adds the name of the country in the given language as selected by
+// country. It is assumed that the Entity is valid.
+func (e Entity) ToLocalizedLines(language Language, country CountryLine) ([]string, error) {
+	lines, err := e.ToLines()
+	if err != nil || len(lines) == 0 {
+		return lines, err
+	}
+	code := NormalizeCountryCode(e.CountryCode)
+	switch country {
+	case ForeignCountryLine:
+		if code == "CH" || code == "LI" {
+			return lines, nil
+		}
+	case NoCountryLine:
+		return lines, nil
+	}
+	return append(lines, countryName(code, language)), nil
+}
+
+// countryName returns the name of the country with the given ISO 3166-1
+// alpha-2 code in the given language, or the code itself if it is unknown.
+func countryName(code string, lang Language) string {
+	region, err := language.ParseRegion(code)
+	if err != nil {
+		return code
+	}
+	if name := display.Regions(language.Make(string(lang))).Name(region); name != "" {
+		return name
+	}
+	return code
+}
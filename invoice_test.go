@@ -15,7 +15,9 @@
 package swissqr
 
 import (
+	"math"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -69,6 +71,7 @@ func TestAmountSectionExample3En(t *testing.T) {
 		CurrencyValue:   "CHF",
 		AmountHeading:   "Amount",
 		AmountValue:     "",
+		EmptyBox:        true,
 	}
 	actual, err := AmountSection(examplePayload3, "en")
 	if err != nil {
@@ -107,7 +110,7 @@ func TestInformationSectionExample1De(t *testing.T) {
 		},
 	}
 	actual, err := InformationSection(examplePayload1, "de",
-		8.5*28.35/10.0, // 8.5cm × 28.35 pt/cm ÷ 10pt font size.
+		WidthForPaymentPart(10),
 		paymentPartInformation)
 	if err != nil {
 		t.Errorf("Could not create invoice text: %v", err)
@@ -130,7 +133,7 @@ func TestInformationSectionExample3En(t *testing.T) {
 		Paragraph{Heading: "Payable by (name/address)", Lines: []string{}},
 	}
 	actual, err := InformationSection(examplePayload3, "en",
-		8.5*28.35/10.0, // 8.5cm × 28.35 pt/cm ÷ 10pt font size.
+		WidthForPaymentPart(10),
 		receiptPartInformation)
 	if err != nil {
 		t.Errorf("Could not create invoice text: %v", err)
@@ -232,3 +235,216 @@ func TestCombinedAddress(t *testing.T) {
 		t.Errorf("Expected %#v, got %#v", expected, actual)
 	}
 }
+
+func TestEntityToLocalizedLines(t *testing.T) {
+	swiss := Entity{
+		Name:        "Robert Schneider AG",
+		Address:     CombinedAddress{AddressLine2: "2501 Biel"},
+		CountryCode: "CH",
+	}
+	german := Entity{
+		Name:        "Pia Rutschmann",
+		Address:     CombinedAddress{AddressLine2: "78462 Konstanz"},
+		CountryCode: "DE",
+	}
+	var testdata = []struct {
+		entity   Entity
+		language Language
+		country  CountryLine
+		expected []string
+	}{
+		{swiss, "de", ForeignCountryLine, []string{"Robert Schneider AG", "2501 Biel"}},
+		{swiss, "fr", AlwaysCountryLine, []string{"Robert Schneider AG", "2501 Biel", "Suisse"}},
+		{german, "de", ForeignCountryLine, []string{"Pia Rutschmann", "78462 Konstanz", "Deutschland"}},
+		{german, "it", ForeignCountryLine, []string{"Pia Rutschmann", "78462 Konstanz", "Germania"}},
+		{german, "en", NoCountryLine, []string{"Pia Rutschmann", "78462 Konstanz"}},
+		{Entity{}, "en", AlwaysCountryLine, []string{}},
+	}
+	for _, item := range testdata {
+		actual, err := item.entity.ToLocalizedLines(item.language, item.country)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(item.expected, actual) {
+			t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", item.expected, actual)
+		}
+	}
+}
+
+func TestInFavourOf(t *testing.T) {
+	identity := func(lines []string) []string { return lines }
+	paragraph, err := inFavourOf(Entity{}, "de", ForeignCountryLine, identity)
+	if err != nil || paragraph != nil {
+		t.Errorf("Expected no paragraph, got: %v %v", paragraph, err)
+	}
+	creditor := Entity{
+		Name:        "Pia Rutschmann",
+		Address:     CombinedAddress{AddressLine2: "78462 Konstanz"},
+		CountryCode: "DE",
+	}
+	expected := &Paragraph{
+		Heading: "En faveur de",
+		Lines:   []string{"Pia Rutschmann", "78462 Konstanz", "Allemagne"},
+	}
+	paragraph, err = inFavourOf(creditor, "fr", ForeignCountryLine, identity)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expected, paragraph) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, paragraph)
+	}
+}
+
+func TestUltimateCreditor(t *testing.T) {
+	p := examplePayload3
+	p.UltimateCreditor = Entity{
+		Name:        "Pia Rutschmann",
+		Address:     CombinedAddress{AddressLine2: "78462 Konstanz"},
+		CountryCode: "DE",
+	}
+	if _, err := InformationSection(p, EN, 100, paymentPartInformation); err == nil {
+		t.Error("Expected error for ultimate creditor that is not enabled")
+	}
+	p.EnableUltimateCreditor = true
+	section, err := InformationSection(p, EN, 100, paymentPartInformation)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := Paragraph{
+		Heading: "In favour of",
+		Lines:   []string{"Pia Rutschmann", "78462 Konstanz", "Germany"},
+	}
+	if len(section) < 2 || !reflect.DeepEqual(expected, section[1]) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, section)
+	}
+
+	// The paragraph is printed on the receipt and the payment part.
+	r := new(recordingRenderer)
+	if err := RenderInvoice(r, p, RenderOptions{Language: EN}); err != nil {
+		t.Fatal(err)
+	}
+	if n := slices.Index(r.calls, "text In favour of"); n < 0 ||
+		slices.Index(r.calls[n+1:], "text In favour of") < 0 {
+		t.Errorf("Expected ultimate creditor twice, got: %v", r.calls)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	var testdata = []struct {
+		amount   float64
+		currency string
+		language Language
+		expected string
+	}{
+		{0, CHF, "de", "0.00"},
+		{1, CHF, "fr", "1.00"},
+		{999.995, EUR, "it", "1 000.00"},
+		{3949.75, CHF, "en", "3 949.75"},
+		{1234567.8, EUR, "de", "1 234 567.80"},
+		{-12345, CHF, "de", "-12 345.00"},
+	}
+	for _, item := range testdata {
+		if actual := FormatAmount(item.amount, item.currency, item.language); actual != item.expected {
+			t.Errorf("Expected %q, got %q", item.expected, actual)
+		}
+	}
+}
+
+func TestAmountSectionModes(t *testing.T) {
+	var testdata = []struct {
+		amount   PaymentAmount
+		value    string
+		emptyBox bool
+	}{
+		{PaymentAmount{Currency: CHF}, "", true},
+		{PaymentAmount{Currency: CHF, Mode: AmountZero}, "0.00", false},
+		{PaymentAmount{Currency: EUR, Mode: AmountOmit}, "", false},
+		{PaymentAmount{Amount: 12.5, Currency: EUR}, "12.50", false},
+	}
+	for _, item := range testdata {
+		p := examplePayload3
+		p.CurrencyAmount = item.amount
+		amt, err := AmountSection(p, "de")
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if amt.AmountValue != item.value || amt.EmptyBox != item.emptyBox {
+			t.Errorf("Item %v: unexpected amount section: %#v", item.amount, amt)
+		}
+	}
+}
+
+func TestPaymentPartContent(t *testing.T) {
+	content, err := PaymentPartContent(examplePayload2, "en")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if content.Title.PaymentPart != "Payment part" || content.Amount.AmountValue != "1 949.75" {
+		t.Errorf("Unexpected title or amount: %#v, %#v", content.Title, content.Amount)
+	}
+	info := content.Information[len(content.Information)-2]
+	expected := Paragraph{
+		Heading: "Additional information",
+		Lines: []string{
+			examplePayload2.AdditionalInformation.UnstructuredMessage,
+			examplePayload2.AdditionalInformation.StructuredMessage.ToString(),
+		},
+	}
+	if !reflect.DeepEqual(expected, info) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, info)
+	}
+	aps := []Paragraph{
+		{Heading: "Name AV1", Lines: []string{"UV;UltraPay005;12345"}},
+		{Heading: "Name AV2", Lines: []string{"XY;XYService;54321"}},
+	}
+	if !reflect.DeepEqual(aps, content.AlternativeProcedures) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", aps, content.AlternativeProcedures)
+	}
+	if _, err := PaymentPartContent(Payload{}, "en"); err == nil {
+		t.Error("Expected error due to invalid payload")
+	}
+}
+
+func TestInformationLinesAndReflow(t *testing.T) {
+	p := examplePayload2
+	p.AdditionalInformation.UnstructuredMessage = "Auftrag vom 15.06.2020 für Gartenarbeiten und Entsorgung"
+	raw, err := InformationLines(p, "de", paymentPartInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reflowed, err := InformationSection(p, "de", 20, paymentPartInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reflowed, Reflow(raw, 20, nil)) {
+		t.Errorf("Expected InformationSection to equal reflowed InformationLines")
+	}
+	for _, paragraph := range raw {
+		if paragraph.Heading == headings[AdditionalInformationHeading]["de"] &&
+			paragraph.Lines[0] != p.AdditionalInformation.UnstructuredMessage {
+			t.Errorf("Expected unwrapped message, got %#v", paragraph.Lines)
+		}
+	}
+	// One unit per character, as for a monospaced font.
+	chars := func(s string) float64 { return float64(len([]rune(s))) }
+	section := []Paragraph{{Heading: "H", Lines: []string{"aaa bbb ccc", "dddddddd"}}}
+	expected := []Paragraph{{Heading: "H", Lines: []string{"aaa bbb", "ccc", "ddddddd", "d"}}}
+	if actual := Reflow(section, 7, chars); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestSectionWidths(t *testing.T) {
+	tests := []struct {
+		actual, expected float64
+	}{
+		{WidthForPaymentPart(10), 8.5 * 28.35 / 10.0},
+		{WidthForReceipt(8), 5.2 * 28.35 / 8.0},
+		{WidthForReceipt(10), 5.2 * 28.35 / 10.0},
+	}
+	for i, test := range tests {
+		if math.Abs(test.actual-test.expected) > 1e-9 {
+			t.Errorf("Item %v: expected %v, got: %v", i, test.expected, test.actual)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krepost/swissqr"
+)
+
+// Payment is an incoming payment reported in a camt.054 credit notification.
+type Payment struct {
+	// Reference is the QR reference or creditor reference of the payment
+	// in digital format, or empty for payments without reference.
+	Reference string
+
+	Amount   float64
+	Currency string
+
+	BookingDate time.Time
+
+	// EndToEndID and DebtorName are set if the bank reports them.
+	EndToEndID string
+	DebtorName string
+}
+
+// ParseCamt054 reads a camt.054 debit/credit notification and returns the
+// credit transactions, i.e. the incoming payments, in the order of the
+// notification. Debit transactions are skipped. Versions 4 to 8 of camt.054
+// are accepted, as the element names used here are the same in all of them.
+func ParseCamt054(r io.Reader) ([]Payment, error) {
+	var doc camt054Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.XMLName.Local != "Document" || len(doc.Notification.Notifications) == 0 {
+		return nil, errors.New("No camt.054 notification found")
+	}
+	var payments []Payment
+	for _, n := range doc.Notification.Notifications {
+		for _, e := range n.Entries {
+			bookingDate, err := e.BookingDate.time()
+			if err != nil {
+				return nil, err
+			}
+			for _, details := range e.Details {
+				for _, tx := range details.Transactions {
+					indicator := tx.Indicator
+					if indicator == "" {
+						indicator = e.Indicator
+					}
+					if indicator != "CRDT" {
+						continue
+					}
+					amt := tx.Amount
+					if amt.Value == "" {
+						amt = e.Amount
+					}
+					value, err := strconv.ParseFloat(strings.TrimSpace(amt.Value), 64)
+					if err != nil {
+						return nil, fmt.Errorf("Invalid amount: %v", amt.Value)
+					}
+					debtor := tx.Debtor.Party.Name
+					if debtor == "" {
+						debtor = tx.Debtor.Name
+					}
+					payments = append(payments, Payment{
+						Reference:   tx.reference(),
+						Amount:      value,
+						Currency:    amt.Currency,
+						BookingDate: bookingDate,
+						EndToEndID:  tx.EndToEndID,
+						DebtorName:  debtor,
+					})
+				}
+			}
+		}
+	}
+	return payments, nil
+}
+
+// Match pairs an issued payload, given by its index, with a payment.
+type Match struct {
+	Payload int
+	Payment Payment
+}
+
+// Report is the result of Reconcile.
+type Report struct {
+	// Matched lists the payments whose reference, currency and amount
+	// agree with a payload.
+	Matched []Match
+
+	// AmountMismatches lists the payments whose reference agrees with a
+	// payload, but whose currency or amount differ, e.g. partial payments.
+	AmountMismatches []Match
+
+	// UnmatchedPayments lists the payments without or with an unknown
+	// reference, and repeated payments for the same payload.
+	UnmatchedPayments []Payment
+
+	// Unpaid lists the indices of the payloads without payment.
+	Unpaid []int
+}
+
+// Reconcile matches the payments against the issued payloads by QR
+// reference or creditor reference. A payment matches if it also has the
+// currency and amount of the payload; payloads with an empty amount box
+// match any amount. Each payload is matched with at most one payment, the
+// first one in the list, so that double payments are reported as unmatched.
+func Reconcile(payloads []swissqr.Payload, payments []Payment) Report {
+	byReference := make(map[string]int)
+	for i, p := range payloads {
+		if p.Reference.Number == nil {
+			continue
+		}
+		ref := normalizeReference(p.Reference.Number.DigitalFormat())
+		if _, ok := byReference[ref]; !ok {
+			byReference[ref] = i
+		}
+	}
+	var report Report
+	paid := make(map[int]bool)
+	for _, payment := range payments {
+		i, ok := byReference[normalizeReference(payment.Reference)]
+		if !ok || payment.Reference == "" || paid[i] {
+			report.UnmatchedPayments = append(report.UnmatchedPayments, payment)
+			continue
+		}
+		paid[i] = true
+		match := Match{Payload: i, Payment: payment}
+		if amountMatches(payloads[i].CurrencyAmount, payment) {
+			report.Matched = append(report.Matched, match)
+		} else {
+			report.AmountMismatches = append(report.AmountMismatches, match)
+		}
+	}
+	for i := range payloads {
+		if !paid[i] {
+			report.Unpaid = append(report.Unpaid, i)
+		}
+	}
+	return report
+}
+
+func normalizeReference(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+}
+
+func amountMatches(pa swissqr.PaymentAmount, payment Payment) bool {
+	if pa.Currency != payment.Currency {
+		return false
+	}
+	if pa.Amount == 0 && pa.Mode != swissqr.AmountZero {
+		return true
+	}
+	return math.Round(pa.Amount*100) == math.Round(payment.Amount*100)
+}
+
+// The following types describe the subset of camt.054 that is read by
+// ParseCamt054. Element names are matched without namespace.
+
+type camt054Document struct {
+	XMLName      xml.Name
+	Notification struct {
+		Notifications []struct {
+			Entries []camt054Entry `xml:"Ntry"`
+		} `xml:"Ntfctn"`
+	} `xml:"BkToCstmrDbtCdtNtfctn"`
+}
+
+type camt054Entry struct {
+	Amount      amount                `xml:"Amt"`
+	Indicator   string                `xml:"CdtDbtInd"`
+	BookingDate dateAndDateTime       `xml:"BookgDt"`
+	Details     []camt054EntryDetails `xml:"NtryDtls"`
+}
+
+type camt054EntryDetails struct {
+	Transactions []camt054Transaction `xml:"TxDtls"`
+}
+
+type camt054Transaction struct {
+	EndToEndID string `xml:"Refs>EndToEndId"`
+	Amount     amount `xml:"Amt"`
+	Indicator  string `xml:"CdtDbtInd"`
+	Debtor     struct {
+		// Versions 8 and later wrap the party in Pty.
+		Name  string `xml:"Nm"`
+		Party struct {
+			Name string `xml:"Nm"`
+		} `xml:"Pty"`
+	} `xml:"RltdPties>Dbtr"`
+	Remittance struct {
+		Structured []struct {
+			Reference string `xml:"CdtrRefInf>Ref"`
+		} `xml:"Strd"`
+	} `xml:"RmtInf"`
+}
+
+func (tx camt054Transaction) reference() string {
+	for _, s := range tx.Remittance.Structured {
+		if s.Reference != "" {
+			return strings.TrimSpace(s.Reference)
+		}
+	}
+	return ""
+}
+
+type dateAndDateTime struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+func (d dateAndDateTime) time() (time.Time, error) {
+	s := d.Date
+	if s == "" {
+		s = d.DateTime
+	}
+	if len(s) < 10 {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", s[:10])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid booking date: %v", s)
+	}
+	return t, nil
+}
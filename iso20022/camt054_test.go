@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iso20022
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krepost/swissqr"
+)
+
+const camt054Example = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.054.001.08">
+  <BkToCstmrDbtCdtNtfctn>
+    <GrpHdr><MsgId>NTF-1</MsgId><CreDtTm>2020-06-25T06:00:00</CreDtTm></GrpHdr>
+    <Ntfctn>
+      <Id>1</Id>
+      <Ntry>
+        <Amt Ccy="CHF">2049.75</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2020-06-24</Dt></BookgDt>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>E2E-1</EndToEndId></Refs>
+            <Amt Ccy="CHF">1949.75</Amt>
+            <CdtDbtInd>CRDT</CdtDbtInd>
+            <RltdPties><Dbtr><Pty><Nm>Pia Rutschmann</Nm></Pty></Dbtr></RltdPties>
+            <RmtInf><Strd><CdtrRefInf><Tp><CdOrPrtry><Prtry>QRR</Prtry></CdOrPrtry></Tp><Ref>210000000003139471430009017</Ref></CdtrRefInf></Strd></RmtInf>
+          </TxDtls>
+          <TxDtls>
+            <Amt Ccy="CHF">100.00</Amt>
+            <CdtDbtInd>CRDT</CdtDbtInd>
+            <RmtInf><Ustrd>Rechnung Nr. 3139</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">100.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><DtTm>2020-06-25T09:00:00</DtTm></BookgDt>
+        <NtryDtls>
+          <TxDtls>
+            <RmtInf><Strd><CdtrRefInf><Ref>RF18539007547034</Ref></CdtrRefInf></Strd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="CHF">10.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2020-06-24</Dt></BookgDt>
+        <NtryDtls><TxDtls><Amt Ccy="CHF">10.00</Amt></TxDtls></NtryDtls>
+      </Ntry>
+    </Ntfctn>
+  </BkToCstmrDbtCdtNtfctn>
+</Document>
+`
+
+func TestParseCamt054(t *testing.T) {
+	payments, err := ParseCamt054(strings.NewReader(camt054Example))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Payment{
+		{
+			Reference:   "210000000003139471430009017",
+			Amount:      1949.75,
+			Currency:    "CHF",
+			BookingDate: time.Date(2020, time.June, 24, 0, 0, 0, 0, time.UTC),
+			EndToEndID:  "E2E-1",
+			DebtorName:  "Pia Rutschmann",
+		},
+		{
+			Amount:      100,
+			Currency:    "CHF",
+			BookingDate: time.Date(2020, time.June, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Reference:   "RF18539007547034",
+			Amount:      100,
+			Currency:    "EUR",
+			BookingDate: time.Date(2020, time.June, 25, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	if !reflect.DeepEqual(expected, payments) {
+		t.Errorf("Expected %+v, got %+v", expected, payments)
+	}
+}
+
+func TestParseCamt054Errors(t *testing.T) {
+	for i, test := range []struct {
+		input    string
+		expected string
+	}{
+		{"<Document></Document>", "No camt.054 notification found"},
+		{"<Document><BkToCstmrDbtCdtNtfctn><Ntfctn><Ntry><Amt Ccy=\"CHF\">x</Amt><CdtDbtInd>CRDT</CdtDbtInd>" +
+			"<NtryDtls><TxDtls/></NtryDtls></Ntry></Ntfctn></BkToCstmrDbtCdtNtfctn></Document>", "Invalid amount: x"},
+		{"not xml", "EOF"},
+	} {
+		_, err := ParseCamt054(strings.NewReader(test.input))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	boxPayload := scorPayload
+	boxPayload.CurrencyAmount = swissqr.PaymentAmount{Currency: swissqr.EUR}
+	payloads := []swissqr.Payload{qrrPayload, boxPayload, messagePayload}
+	payments := []Payment{
+		{Reference: "21 00000 00003 13947 14300 09017", Amount: 1000, Currency: "CHF"},
+		{Reference: "RF18539007547034", Amount: 42, Currency: "EUR"},
+		{Reference: "rf18539007547034", Amount: 42, Currency: "EUR"},
+		{Amount: 100, Currency: "CHF"},
+	}
+	report := Reconcile(payloads, payments)
+	expected := Report{
+		Matched:           []Match{{Payload: 1, Payment: payments[1]}},
+		AmountMismatches:  []Match{{Payload: 0, Payment: payments[0]}},
+		UnmatchedPayments: []Payment{payments[2], payments[3]},
+		Unpaid:            []int{2},
+	}
+	if !reflect.DeepEqual(expected, report) {
+		t.Errorf("Expected %+v, got %+v", expected, report)
+	}
+}
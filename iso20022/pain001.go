@@ -0,0 +1,279 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package iso20022 connects QR bills with the ISO 20022 messages exchanged
+// with banks, as specified by the Swiss Payment Standards: it creates
+// pain.001 credit transfer instructions for paying QR bills, and it matches
+// the payments reported in camt.054 credit notifications with the QR bills
+// that were issued.
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/krepost/swissqr"
+)
+
+// Pain001Namespace is the XML namespace of the messages written by
+// WritePain001, version 9 of pain.001 as used by the Swiss Payment Standards
+// 2022.
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// Initiation contains the data of a pain.001 message that are not part of
+// the QR bills: the message and the account of the debtor who pays them.
+type Initiation struct {
+	// MessageID identifies the message towards the bank; at most 35
+	// characters. It is also the prefix of the end-to-end identifiers.
+	MessageID string
+
+	// CreationTime is the time of creation of the message; the current
+	// time if zero.
+	CreationTime time.Time
+
+	// ExecutionDate is the date on which the bank should execute the
+	// payments.
+	ExecutionDate time.Time
+
+	// DebtorName and DebtorIBAN are the name and the account of the
+	// debtor. DebtorBIC is optional.
+	DebtorName string
+	DebtorIBAN string
+	DebtorBIC  string
+}
+
+// WritePain001 writes a pain.001 message with one credit transfer for each
+// payload to w. The payloads are validated and must have an amount. QR
+// references and creditor references are transmitted as structured
+// remittance information, with the message and the bill information as
+// additional remittance information; a message without reference or bill
+// information is transmitted as unstructured remittance information.
+func WritePain001(w io.Writer, init Initiation, payloads ...swissqr.Payload) error {
+	if init.MessageID == "" || len(init.MessageID) > 35 {
+		return fmt.Errorf("Message ID must have 1 to 35 characters: %q", init.MessageID)
+	}
+	if init.DebtorName == "" || init.DebtorIBAN == "" {
+		return errors.New("Debtor name and IBAN required")
+	}
+	if len(payloads) == 0 {
+		return errors.New("No payloads")
+	}
+	created := init.CreationTime
+	if created.IsZero() {
+		created = time.Now()
+	}
+	agent := financialInstitution{BIC: init.DebtorBIC}
+	if init.DebtorBIC == "" {
+		agent.Other = &otherID{ID: "NOTPROVIDED"}
+	}
+	info := paymentInformation{
+		ID:            init.MessageID,
+		Method:        "TRF",
+		NumberOfTxs:   len(payloads),
+		ExecutionDate: init.ExecutionDate.Format("2006-01-02"),
+		Debtor:        party{Name: init.DebtorName},
+		DebtorAccount: account{IBAN: init.DebtorIBAN},
+		DebtorAgent:   agent,
+	}
+	var sum float64
+	for i, p := range payloads {
+		tx, err := creditTransfer(p, fmt.Sprintf("%v-%d", init.MessageID, i+1))
+		if err != nil {
+			return fmt.Errorf("Payload %d: %v", i, err)
+		}
+		info.Transactions = append(info.Transactions, tx)
+		sum += p.CurrencyAmount.Amount
+	}
+	info.ControlSum = formatAmount(sum)
+	doc := pain001Document{
+		Namespace: Pain001Namespace,
+		Initiation: customerCreditTransferInitiation{
+			Header: groupHeader{
+				MessageID:      init.MessageID,
+				CreationTime:   created.Format("2006-01-02T15:04:05"),
+				NumberOfTxs:    len(payloads),
+				ControlSum:     info.ControlSum,
+				InitiatingName: init.DebtorName,
+			},
+			Payments: []paymentInformation{info},
+		},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func creditTransfer(p swissqr.Payload, endToEndID string) (creditTransferTransaction, error) {
+	if err := p.Validate(); err != nil {
+		return creditTransferTransaction{}, err
+	}
+	if p.CurrencyAmount.Amount <= 0 {
+		return creditTransferTransaction{}, errors.New("Amount required for credit transfer")
+	}
+	tx := creditTransferTransaction{
+		EndToEndID:      endToEndID,
+		Amount:          amount{Currency: p.CurrencyAmount.Currency, Value: formatAmount(p.CurrencyAmount.Amount)},
+		Creditor:        fromEntity(p.Creditor),
+		CreditorAccount: account{IBAN: p.Account.IBAN.Code},
+	}
+	message := p.AdditionalInformation.UnstructuredMessage
+	billInformation := p.AdditionalInformation.BillInformation()
+	var ref *creditorReference
+	switch p.Reference.Type() {
+	case "QRR":
+		ref = &creditorReference{Proprietary: "QRR", Reference: p.Reference.Number.DigitalFormat()}
+	case "SCOR":
+		ref = &creditorReference{Code: "SCOR", Reference: p.Reference.Number.DigitalFormat()}
+	}
+	var additional []string
+	for _, info := range []string{message, billInformation} {
+		if info != "" {
+			additional = append(additional, info)
+		}
+	}
+	switch {
+	case ref != nil || billInformation != "":
+		tx.Remittance = &remittanceInformation{Structured: &structuredRemittance{
+			Reference:             ref,
+			AdditionalInformation: additional,
+		}}
+	case message != "":
+		tx.Remittance = &remittanceInformation{Unstructured: message}
+	}
+	return tx, nil
+}
+
+func fromEntity(e swissqr.Entity) party {
+	pa := party{Name: e.Name, Address: &postalAddress{Country: swissqr.NormalizeCountryCode(e.CountryCode)}}
+	switch a := e.Address.(type) {
+	case swissqr.StructuredAddress:
+		pa.Address.StreetName = a.StreetName
+		pa.Address.BuildingNumber = a.BuildingNumber
+		pa.Address.PostCode = a.PostCode
+		pa.Address.TownName = a.TownName
+	case swissqr.CombinedAddress:
+		for _, line := range []string{a.AddressLine1, a.AddressLine2} {
+			if line != "" {
+				pa.Address.AddressLines = append(pa.Address.AddressLines, line)
+			}
+		}
+	}
+	return pa
+}
+
+// formatAmount formats an amount with two decimals, as the QR code does.
+func formatAmount(f float64) string {
+	return fmt.Sprintf("%.2f", math.Round(f*100)/100)
+}
+
+// The following types describe the subset of pain.001.001.09 that is
+// written by WritePain001; the XML names are those of the schema.
+
+type pain001Document struct {
+	XMLName    xml.Name                         `xml:"Document"`
+	Namespace  string                           `xml:"xmlns,attr"`
+	Initiation customerCreditTransferInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type customerCreditTransferInitiation struct {
+	Header   groupHeader          `xml:"GrpHdr"`
+	Payments []paymentInformation `xml:"PmtInf"`
+}
+
+type groupHeader struct {
+	MessageID      string `xml:"MsgId"`
+	CreationTime   string `xml:"CreDtTm"`
+	NumberOfTxs    int    `xml:"NbOfTxs"`
+	ControlSum     string `xml:"CtrlSum"`
+	InitiatingName string `xml:"InitgPty>Nm"`
+}
+
+type paymentInformation struct {
+	ID            string                      `xml:"PmtInfId"`
+	Method        string                      `xml:"PmtMtd"`
+	NumberOfTxs   int                         `xml:"NbOfTxs"`
+	ControlSum    string                      `xml:"CtrlSum"`
+	ExecutionDate string                      `xml:"ReqdExctnDt>Dt"`
+	Debtor        party                       `xml:"Dbtr"`
+	DebtorAccount account                     `xml:"DbtrAcct"`
+	DebtorAgent   financialInstitution        `xml:"DbtrAgt>FinInstnId"`
+	Transactions  []creditTransferTransaction `xml:"CdtTrfTxInf"`
+}
+
+type party struct {
+	Name    string         `xml:"Nm"`
+	Address *postalAddress `xml:"PstlAdr,omitempty"`
+}
+
+type postalAddress struct {
+	StreetName     string   `xml:"StrtNm,omitempty"`
+	BuildingNumber string   `xml:"BldgNb,omitempty"`
+	PostCode       string   `xml:"PstCd,omitempty"`
+	TownName       string   `xml:"TwnNm,omitempty"`
+	Country        string   `xml:"Ctry,omitempty"`
+	AddressLines   []string `xml:"AdrLine,omitempty"`
+}
+
+type account struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type financialInstitution struct {
+	BIC   string   `xml:"BICFI,omitempty"`
+	Other *otherID `xml:"Othr,omitempty"`
+}
+
+type otherID struct {
+	ID string `xml:"Id"`
+}
+
+type creditTransferTransaction struct {
+	EndToEndID      string                 `xml:"PmtId>EndToEndId"`
+	Amount          amount                 `xml:"Amt>InstdAmt"`
+	Creditor        party                  `xml:"Cdtr"`
+	CreditorAccount account                `xml:"CdtrAcct"`
+	Remittance      *remittanceInformation `xml:"RmtInf,omitempty"`
+}
+
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type remittanceInformation struct {
+	Unstructured string                `xml:"Ustrd,omitempty"`
+	Structured   *structuredRemittance `xml:"Strd,omitempty"`
+}
+
+type structuredRemittance struct {
+	Reference             *creditorReference `xml:"CdtrRefInf,omitempty"`
+	AdditionalInformation []string           `xml:"AddtlRmtInf,omitempty"`
+}
+
+type creditorReference struct {
+	Code        string `xml:"Tp>CdOrPrtry>Cd,omitempty"`
+	Proprietary string `xml:"Tp>CdOrPrtry>Prtry,omitempty"`
+	Reference   string `xml:"Ref"`
+}
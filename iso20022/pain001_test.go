@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iso20022
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krepost/swissqr"
+)
+
+var (
+	creditor = swissqr.Entity{
+		Name: "Robert Schneider AG",
+		Address: swissqr.StructuredAddress{
+			StreetName:     "Rue du Lac",
+			BuildingNumber: "1268",
+			PostCode:       "2501",
+			TownName:       "Biel",
+		},
+		CountryCode: "CH",
+	}
+
+	qrrPayload = swissqr.Payload{
+		Account:        swissqr.NewIBANOrDie("CH4431999123000889012"),
+		Creditor:       creditor,
+		CurrencyAmount: swissqr.PaymentAmount{Amount: 1949.75, Currency: swissqr.CHF},
+		Reference: swissqr.PaymentReference{
+			Number: swissqr.ParseQRReferenceOrDie("210000000003139471430009017"),
+		},
+		AdditionalInformation: swissqr.PaymentInformation{
+			UnstructuredMessage: "Auftrag vom 18.06.2020",
+			StructuredMessage:   swissqr.BillInformation{InvoiceNumber: "10201409"},
+		},
+	}
+
+	scorPayload = swissqr.Payload{
+		Account:        swissqr.NewIBANOrDie("CH5800791123000889012"),
+		Creditor:       creditor,
+		CurrencyAmount: swissqr.PaymentAmount{Amount: 199.95, Currency: swissqr.EUR},
+		Reference: swissqr.PaymentReference{
+			Number: swissqr.ParseCreditorReferenceOrDie("RF18539007547034"),
+		},
+	}
+
+	messagePayload = swissqr.Payload{
+		Account:        swissqr.NewIBANOrDie("CH5800791123000889012"),
+		Creditor:       creditor,
+		CurrencyAmount: swissqr.PaymentAmount{Amount: 100, Currency: swissqr.CHF},
+		AdditionalInformation: swissqr.PaymentInformation{
+			UnstructuredMessage: "Rechnung Nr. 3139",
+		},
+	}
+
+	initiation = Initiation{
+		MessageID:     "MSG-2020-06-18",
+		CreationTime:  time.Date(2020, time.June, 18, 10, 30, 0, 0, time.UTC),
+		ExecutionDate: time.Date(2020, time.June, 22, 0, 0, 0, 0, time.UTC),
+		DebtorName:    "Pia Rutschmann",
+		DebtorIBAN:    "CH9300762011623852957",
+	}
+)
+
+func TestWritePain001(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WritePain001(&buffer, initiation, qrrPayload, scorPayload, messagePayload); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buffer.String()
+	for _, expected := range []string{
+		`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">`,
+		"<CreDtTm>2020-06-18T10:30:00</CreDtTm>",
+		"<NbOfTxs>3</NbOfTxs>",
+		"<CtrlSum>2249.70</CtrlSum>",
+		"<Dt>2020-06-22</Dt>",
+		"<Id>NOTPROVIDED</Id>",
+		"<EndToEndId>MSG-2020-06-18-1</EndToEndId>",
+		`<InstdAmt Ccy="CHF">1949.75</InstdAmt>`,
+		`<InstdAmt Ccy="EUR">199.95</InstdAmt>`,
+		"<Prtry>QRR</Prtry>",
+		"<Ref>210000000003139471430009017</Ref>",
+		"<Cd>SCOR</Cd>",
+		"<AddtlRmtInf>Auftrag vom 18.06.2020</AddtlRmtInf>",
+		"<AddtlRmtInf>//S1/10/10201409</AddtlRmtInf>",
+		"<Ustrd>Rechnung Nr. 3139</Ustrd>",
+		"<StrtNm>Rue du Lac</StrtNm>",
+		"<IBAN>CH4431999123000889012</IBAN>",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %#v in output:\n%v", expected, output)
+		}
+	}
+}
+
+func TestWritePain001Errors(t *testing.T) {
+	boxPayload := messagePayload
+	boxPayload.CurrencyAmount = swissqr.PaymentAmount{Currency: swissqr.CHF}
+	noDebtor := initiation
+	noDebtor.DebtorIBAN = ""
+	longID := initiation
+	longID.MessageID = strings.Repeat("X", 36)
+	for i, test := range []struct {
+		init     Initiation
+		payloads []swissqr.Payload
+		expected string
+	}{
+		{initiation, nil, "No payloads"},
+		{noDebtor, []swissqr.Payload{qrrPayload}, "Debtor name and IBAN required"},
+		{longID, []swissqr.Payload{qrrPayload}, "Message ID must have 1 to 35 characters"},
+		{initiation, []swissqr.Payload{qrrPayload, boxPayload}, "Payload 1: Amount required for credit transfer"},
+		{initiation, []swissqr.Payload{{}}, "Payload 0: No account specified"},
+	} {
+		var buffer bytes.Buffer
+		err := WritePain001(&buffer, test.init, test.payloads...)
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The JSON representation of a Payload uses the Go field names as keys.
+// Fields that cannot be represented directly, such as the IBAN, the address
+// and the payment reference, are encoded by the methods in this file.
+
+// jsonAddress is the JSON representation of both address types. The type
+// is given by the same letter that is used in the QR code: “S” for a
+// structured address and “K” for a combined address.
+type jsonAddress struct {
+	Type           string
+	AddressLine1   string `json:",omitempty"`
+	AddressLine2   string `json:",omitempty"`
+	StreetName     string `json:",omitempty"`
+	BuildingNumber string `json:",omitempty"`
+	PostCode       string `json:",omitempty"`
+	TownName       string `json:",omitempty"`
+}
+
+type jsonEntity struct {
+	Name        string
+	Address     *jsonAddress `json:",omitempty"`
+	CountryCode string
+}
+
+// MarshalJSON encodes an account number as an IBAN without spaces.
+func (a AccountNumber) MarshalJSON() ([]byte, error) {
+	if a.IBAN == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(a.IBAN.Code)
+}
+
+// UnmarshalJSON decodes an IBAN given with or without spaces.
+func (a *AccountNumber) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		a.IBAN = nil
+		return nil
+	}
+	code, err := ParseIBAN(s)
+	if err != nil {
+		return err
+	}
+	a.IBAN = code
+	return nil
+}
+
+// MarshalJSON encodes an entity together with the type of its address.
+func (e Entity) MarshalJSON() ([]byte, error) {
+	v := jsonEntity{Name: e.Name, CountryCode: e.CountryCode}
+	switch addr := e.Address.(type) {
+	case nil:
+	case CombinedAddress:
+		v.Address = &jsonAddress{
+			Type:         "K",
+			AddressLine1: addr.AddressLine1,
+			AddressLine2: addr.AddressLine2,
+		}
+	case StructuredAddress:
+		v.Address = &jsonAddress{
+			Type:           "S",
+			StreetName:     addr.StreetName,
+			BuildingNumber: addr.BuildingNumber,
+			PostCode:       addr.PostCode,
+			TownName:       addr.TownName,
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported address type: %T", addr)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes an entity and restores the type of its address.
+func (e *Entity) UnmarshalJSON(b []byte) error {
+	var v jsonEntity
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	e.Name = v.Name
+	e.CountryCode = v.CountryCode
+	e.Address = nil
+	if v.Address == nil {
+		return nil
+	}
+	switch v.Address.Type {
+	case "K":
+		e.Address = CombinedAddress{
+			AddressLine1: v.Address.AddressLine1,
+			AddressLine2: v.Address.AddressLine2,
+		}
+	case "S":
+		e.Address = StructuredAddress{
+			StreetName:     v.Address.StreetName,
+			BuildingNumber: v.Address.BuildingNumber,
+			PostCode:       v.Address.PostCode,
+			TownName:       v.Address.TownName,
+		}
+	default:
+		return fmt.Errorf("Unsupported address type: %q", v.Address.Type)
+	}
+	return nil
+}
+
+// MarshalJSON encodes a payment reference in digital format. An empty
+// reference is encoded as null.
+func (pr PaymentReference) MarshalJSON() ([]byte, error) {
+	if pr.Number == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(pr.Number.DigitalFormat())
+}
+
+// UnmarshalJSON decodes a payment reference. References starting with “RF”
+// are creditor references according to ISO 11649; all other references are
+// QR references.
+func (pr *PaymentReference) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	pr.Number = nil
+	s = strings.ReplaceAll(s, " ", "")
+	switch {
+	case s == "":
+	case strings.HasPrefix(strings.ToUpper(s), "RF"):
+		ref, err := ParseCreditorReference(s)
+		if err != nil {
+			return err
+		}
+		pr.Number = ref
+	default:
+		ref, err := ParseQRReference(s)
+		if err != nil {
+			return err
+		}
+		pr.Number = ref
+	}
+	return nil
+}
+
+// jsonDateFormat is the format of dates in the JSON representation.
+const jsonDateFormat = "2006-01-02"
+
+// MarshalJSON encodes a date as “2006-01-02” and a date interval as
+// “2006-01-02/2006-01-31”. An empty date is encoded as an empty string.
+func (d dates) MarshalJSON() ([]byte, error) {
+	s := ""
+	if !d.Date.IsZero() {
+		s = d.Date.Format(jsonDateFormat)
+		if !d.End.IsZero() {
+			s = s + "/" + d.End.Format(jsonDateFormat)
+		}
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes a date or a date interval.
+func (d *dates) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*d = dates{}
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) > 2 {
+		return fmt.Errorf("Invalid date: %v", s)
+	}
+	date, err := time.Parse(jsonDateFormat, parts[0])
+	if err != nil {
+		return err
+	}
+	d.Date = date
+	if len(parts) == 2 {
+		end, err := time.Parse(jsonDateFormat, parts[1])
+		if err != nil {
+			return err
+		}
+		d.End = end
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	for i, payload := range []Payload{examplePayload1, examplePayload2, examplePayload3} {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			t.Errorf("Item %v: could not encode payload: %v", i, err)
+			continue
+		}
+		var decoded Payload
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Errorf("Item %v: could not decode payload: %v", i, err)
+			continue
+		}
+		var expected, actual bytes.Buffer
+		if err := payload.Serialize(&expected); err != nil {
+			t.Errorf("Item %v: could not serialize payload: %v", i, err)
+		}
+		if err := decoded.Serialize(&actual); err != nil {
+			t.Errorf("Item %v: could not serialize decoded payload: %v", i, err)
+		}
+		if expected.String() != actual.String() {
+			t.Errorf("Item %v: expected:\n\n%#v\n\nGot:\n\n%#v\n\n",
+				i, expected.String(), actual.String())
+		}
+	}
+}
+
+func TestJSONDecode(t *testing.T) {
+	input := `{
+		"Account": "CH44 3199 9123 0008 8901 2",
+		"Creditor": {
+			"Name": "Robert Schneider AG",
+			"Address": {"Type": "K", "AddressLine1": "Rue du Lac 1268", "AddressLine2": "2501 Biel"},
+			"CountryCode": "CH"
+		},
+		"CurrencyAmount": {"Amount": 1949.75, "Currency": "CHF"},
+		"Reference": "21 00000 00003 13947 14300 09017",
+		"AdditionalInformation": {
+			"StructuredMessage": {"InvoiceDate": "2019-05-12", "VATDates": "2019-05-01/2019-05-31"}
+		}
+	}`
+	var p Payload
+	if err := json.Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Could not decode payload: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	expected := "//S1/11/190512/31/190501190531"
+	if actual := p.AdditionalInformation.StructuredMessage.ToString(); expected != actual {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+	if _, ok := p.Creditor.Address.(CombinedAddress); !ok {
+		t.Errorf("Expected combined address, got %T", p.Creditor.Address)
+	}
+}
+
+func TestJSONDecodeErrors(t *testing.T) {
+	var testdata = []struct {
+		input   string
+		message string
+	}{
+		{`{"Account": "CH00 0000 0000 0000 0000 0"}`, "check"},
+		{`{"Creditor": {"Name": "X", "Address": {"Type": "Q"}}}`, "Unsupported address type"},
+		{`{"Reference": "RF00 1234"}`, "check"},
+		{`{"AdditionalInformation": {"StructuredMessage": {"InvoiceDate": "12.05.2019"}}}`, "cannot parse"},
+	}
+	for i, data := range testdata {
+		var p Payload
+		err := json.Unmarshal([]byte(data.input), &p)
+		if err == nil {
+			t.Errorf("Item %v: expected error; got no error.", i)
+		} else if !strings.Contains(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
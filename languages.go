@@ -14,113 +14,270 @@
 
 package swissqr
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Language is the code of a language of the invoice, e.g. “de”. The
+// constants are the languages supported out of the box; RegisterLanguage
+// adds further ones. Use ParseLanguage to check a code given as text, e.g.
+// in a configuration file, before rendering.
+type Language string
 
 const (
-	paymentPart = iota
-	accountPayableTo
-	reference
-	additionalInformation
-	currency
-	amount
-	receipt
-	acceptancePoint
-	pleaseSeparate
-	payableBy
-	payableByNameAddress
-	inFavourOf
-	dateFormat
+	DE Language = "de" // German.
+	FR Language = "fr" // French.
+	IT Language = "it" // Italian.
+	EN Language = "en" // English.
+	RM Language = "rm" // Romansh.
 )
 
+// ParseLanguage returns the language with the given code, e.g. “fr”, or an
+// error if the language is not supported.
+func ParseLanguage(code string) (Language, error) {
+	language := Language(code)
+	if err := checkLanguage(language); err != nil {
+		return "", err
+	}
+	return language, nil
+}
+
+// String returns the code of the language.
+func (l Language) String() string {
+	return string(l)
+}
+
+// Heading identifies a localized string of the invoice. Custom renderers
+// can look up the text of a heading with the Text method.
+type Heading int
+
+const (
+	PaymentPartHeading Heading = iota
+	AccountPayableToHeading
+	ReferenceHeading
+	AdditionalInformationHeading
+	CurrencyHeading
+	AmountHeading
+	ReceiptHeading
+	AcceptancePointHeading
+	PleaseSeparateHeading
+	PayableByHeading
+	PayableByNameAddressHeading
+	InFavourOfHeading
+	DateFormatHeading
+	DraftHeading
+	PhoneHeading
+	EmailHeading
+)
+
+// headingsMu guards headings against concurrent RegisterLanguage calls.
+var headingsMu sync.RWMutex
+
 // headings contains all invoice-related strings that require localization.
-// All but the date format are taken from the Swiss QR Invoice standard.
-var headings = map[int]map[string]string{
-	paymentPart: {
-		"de": "Zahlteil",
-		"fr": "Section paiement",
-		"it": "Sezione pagamento",
-		"en": "Payment part",
-	},
-	accountPayableTo: {
-		"de": "Konto / Zahlbar an",
-		"fr": "Compte / Payable à",
-		"it": "Conto / Pagabile a",
-		"en": "Account / Payable to",
-	},
-	reference: {
-		"de": "Referenz",
-		"fr": "Référence",
-		"it": "Riferimento",
-		"en": "Reference",
-	},
-	additionalInformation: {
-		"de": "Zusätzliche Informationen",
-		"fr": "Informations supplémentaires",
-		"it": "Informazioni supplementari",
-		"en": "Additional information",
-	},
-	currency: {
-		"de": "Währung",
-		"fr": "Monnaie",
-		"it": "Valuta",
-		"en": "Currency",
-	},
-	amount: {
-		"de": "Betrag",
-		"fr": "Montant",
-		"it": "Importo",
-		"en": "Amount",
-	},
-	receipt: {
-		"de": "Empfangsschein",
-		"fr": "Récépissé",
-		"it": "Ricevuta",
-		"en": "Receipt",
-	},
-	acceptancePoint: {
-		"de": "Annahmestelle",
-		"fr": "Point de dépôt",
-		"it": "Punto di accettazione",
-		"en": "Acceptance point",
-	},
-	pleaseSeparate: {
-		"de": "Vor der Einzahlung abzutrennen",
-		"fr": "A détacher avant le versement",
-		"it": "De staccare prima del versamento",
-		"en": "Separate before paying in",
-	},
-	payableBy: {
-		"de": "Zahlbar durch",
-		"fr": "Payable par",
-		"it": "Pagabile da",
-		"en": "Payable by",
-	},
-	payableByNameAddress: {
-		"de": "Zahlbar durch (Name/Adresse)",
-		"fr": "Payable par (nom/adresse)",
-		"it": "Pagabile da (nome/indirizzo)",
-		"en": "Payable by (name/address)",
-	},
-	inFavourOf: {
-		"de": "Zugunsten",
-		"fr": "En faveur de",
-		"it": "A favore di",
-		"en": "In favour of",
-	},
-	dateFormat: {
-		"de": "02.01.2006",
-		"fr": "02.01.2006",
-		"it": "02.01.2006",
-		"en": "2006-01-02",
+// All but the date format, the draft banner and the contact labels are taken
+// from the Swiss QR Invoice standard, which has no Romansh texts; those are
+// in Rumantsch Grischun.
+var headings = map[Heading]map[Language]string{
+	PaymentPartHeading: {
+		DE: "Zahlteil",
+		FR: "Section paiement",
+		IT: "Sezione pagamento",
+		EN: "Payment part",
+		RM: "Part da pajament",
+	},
+	AccountPayableToHeading: {
+		DE: "Konto / Zahlbar an",
+		FR: "Compte / Payable à",
+		IT: "Conto / Pagabile a",
+		EN: "Account / Payable to",
+		RM: "Conto / Pajabel a",
+	},
+	ReferenceHeading: {
+		DE: "Referenz",
+		FR: "Référence",
+		IT: "Riferimento",
+		EN: "Reference",
+		RM: "Referenza",
+	},
+	AdditionalInformationHeading: {
+		DE: "Zusätzliche Informationen",
+		FR: "Informations supplémentaires",
+		IT: "Informazioni supplementari",
+		EN: "Additional information",
+		RM: "Infurmaziuns supplementaras",
+	},
+	CurrencyHeading: {
+		DE: "Währung",
+		FR: "Monnaie",
+		IT: "Valuta",
+		EN: "Currency",
+		RM: "Valuta",
+	},
+	AmountHeading: {
+		DE: "Betrag",
+		FR: "Montant",
+		IT: "Importo",
+		EN: "Amount",
+		RM: "Import",
+	},
+	ReceiptHeading: {
+		DE: "Empfangsschein",
+		FR: "Récépissé",
+		IT: "Ricevuta",
+		EN: "Receipt",
+		RM: "Quittanza",
+	},
+	AcceptancePointHeading: {
+		DE: "Annahmestelle",
+		FR: "Point de dépôt",
+		IT: "Punto di accettazione",
+		EN: "Acceptance point",
+		RM: "Post d'acceptanza",
+	},
+	PleaseSeparateHeading: {
+		DE: "Vor der Einzahlung abzutrennen",
+		FR: "A détacher avant le versement",
+		IT: "De staccare prima del versamento",
+		EN: "Separate before paying in",
+		RM: "Da separar avant il pajament",
+	},
+	PayableByHeading: {
+		DE: "Zahlbar durch",
+		FR: "Payable par",
+		IT: "Pagabile da",
+		EN: "Payable by",
+		RM: "Pajabel da",
+	},
+	PayableByNameAddressHeading: {
+		DE: "Zahlbar durch (Name/Adresse)",
+		FR: "Payable par (nom/adresse)",
+		IT: "Pagabile da (nome/indirizzo)",
+		EN: "Payable by (name/address)",
+		RM: "Pajabel da (num/adressa)",
+	},
+	InFavourOfHeading: {
+		DE: "Zugunsten",
+		FR: "En faveur de",
+		IT: "A favore di",
+		EN: "In favour of",
+		RM: "En favur da",
+	},
+	DateFormatHeading: {
+		DE: "02.01.2006",
+		FR: "02.01.2006",
+		IT: "02.01.2006",
+		EN: "2006-01-02",
+		RM: "02.01.2006",
+	},
+	DraftHeading: {
+		DE: "ENTWURF",
+		FR: "PROJET",
+		IT: "BOZZA",
+		EN: "DRAFT",
+		RM: "SBOZ",
+	},
+	PhoneHeading: {
+		DE: "Tel.",
+		FR: "Tél.",
+		IT: "Tel.",
+		EN: "Phone",
+		RM: "Tel.",
+	},
+	EmailHeading: {
+		DE: "E-Mail",
+		FR: "E-mail",
+		IT: "E-mail",
+		EN: "Email",
+		RM: "E-mail",
 	},
 }
 
+// Text returns the text of the heading in the given language.
+func (h Heading) Text(language Language) (string, error) {
+	if err := checkLanguage(language); err != nil {
+		return "", err
+	}
+	headingsMu.RLock()
+	text, ok := headings[h][language]
+	headingsMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("Unknown heading: %d", int(h))
+	}
+	return text, nil
+}
+
+// SupportedLanguages returns the codes of all supported languages in
+// alphabetical order.
+func SupportedLanguages() []Language {
+	headingsMu.RLock()
+	defer headingsMu.RUnlock()
+	var languages []Language
+	for language := range headings[PaymentPartHeading] {
+		if supported(language) {
+			languages = append(languages, language)
+		}
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i] < languages[j] })
+	return languages
+}
+
+// RegisterLanguage sets the texts of the headings for the language with the
+// given code, so that invoices can be produced in further languages or with
+// the wording of a company. A new language needs the texts of all headings;
+// for a supported language, the given texts replace the existing ones. Line
+// item and statement tables of new languages use the English labels. The
+// registration is global: it applies to all invoices rendered afterwards in
+// any goroutine, so call it during initialization.
+func RegisterLanguage(code Language, texts map[Heading]string) error {
+	if code == "" {
+		return fmt.Errorf("Invalid language code: %q", code)
+	}
+	headingsMu.Lock()
+	defer headingsMu.Unlock()
+	for h := range texts {
+		if _, ok := headings[h]; !ok {
+			return fmt.Errorf("Unknown heading: %d", int(h))
+		}
+	}
+	if !supported(code) {
+		for h := range headings {
+			if _, ok := texts[h]; !ok {
+				return fmt.Errorf("Missing heading %d for new language: %v", int(h), code)
+			}
+		}
+	}
+	for h, text := range texts {
+		headings[h][code] = text
+	}
+	return nil
+}
+
 // checkLanguage returns nil if language is supported.
-func checkLanguage(language string) error {
+func checkLanguage(language Language) error {
+	headingsMu.RLock()
+	defer headingsMu.RUnlock()
+	if !supported(language) {
+		return fmt.Errorf("Unsupported langauge: %v", language)
+	}
+	return nil
+}
+
+// supported reports whether all headings have a text in language. The
+// caller must hold headingsMu.
+func supported(language Language) bool {
 	for _, heading := range headings {
 		if _, ok := heading[language]; !ok {
-			return fmt.Errorf("Unsupported langauge: %v", language)
+			return false
 		}
 	}
-	return nil
+	return true
+}
+
+// localized returns the text of the heading in a supported language.
+func localized(h Heading, language Language) string {
+	headingsMu.RLock()
+	defer headingsMu.RUnlock()
+	return headings[h][language]
 }
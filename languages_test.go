@@ -14,7 +14,10 @@
 
 package swissqr
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestLanguageSupported(t *testing.T) {
 	err := checkLanguage("en")
@@ -30,16 +33,24 @@ func TestLanguageNotSupported(t *testing.T) {
 	}
 }
 
+func TestSupportedLanguages(t *testing.T) {
+	expected := []Language{DE, EN, FR, IT, RM}
+	if actual := SupportedLanguages(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
+
 func TestLanguageLookup(t *testing.T) {
 	var languageTests = []struct {
-		id       int
-		language string
+		id       Heading
+		language Language
 		expected string
 	}{
-		{currency, "de", "Währung"},
-		{accountPayableTo, "fr", "Compte / Payable à"},
-		{amount, "it", "Importo"},
-		{payableByNameAddress, "en", "Payable by (name/address)"},
+		{CurrencyHeading, "de", "Währung"},
+		{AccountPayableToHeading, "fr", "Compte / Payable à"},
+		{AmountHeading, "it", "Importo"},
+		{PayableByNameAddressHeading, "en", "Payable by (name/address)"},
+		{ReceiptHeading, "rm", "Quittanza"},
 	}
 	for _, testCase := range languageTests {
 		actual, found := headings[testCase.id][testCase.language]
@@ -51,3 +62,78 @@ func TestLanguageLookup(t *testing.T) {
 		}
 	}
 }
+
+func TestHeadingText(t *testing.T) {
+	if text, err := AcceptancePointHeading.Text("it"); err != nil || text != "Punto di accettazione" {
+		t.Errorf("Unexpected heading: %v, %v", text, err)
+	}
+	if _, err := PaymentPartHeading.Text("sv"); err == nil {
+		t.Error("Expected error due to unsupported language")
+	}
+	if _, err := Heading(-1).Text("de"); err == nil {
+		t.Error("Expected error due to unknown heading")
+	}
+}
+
+func TestRegisterLanguage(t *testing.T) {
+	// Restore the headings changed by this test.
+	saved := map[Heading]map[Language]string{}
+	for h, texts := range headings {
+		saved[h] = map[Language]string{}
+		for language, text := range texts {
+			saved[h][language] = text
+		}
+	}
+	defer func() {
+		headingsMu.Lock()
+		headings = saved
+		headingsMu.Unlock()
+	}()
+
+	if err := RegisterLanguage("de", map[Heading]string{PayableByHeading: "Zahlungspflichtig"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if text, _ := PayableByHeading.Text("de"); text != "Zahlungspflichtig" {
+		t.Errorf("Expected overridden heading, got: %v", text)
+	}
+	if text, _ := AccountPayableToHeading.Text("de"); text != "Konto / Zahlbar an" {
+		t.Errorf("Expected unchanged heading, got: %v", text)
+	}
+
+	if err := RegisterLanguage("sv", map[Heading]string{PaymentPartHeading: "Betalningsdel"}); err == nil {
+		t.Error("Expected error due to missing headings")
+	}
+	if checkLanguage("sv") == nil {
+		t.Error("Expected language sv to not be supported after failed registration.")
+	}
+	texts := map[Heading]string{}
+	for h := range headings {
+		texts[h] = headings[h]["en"]
+	}
+	texts[PaymentPartHeading] = "Betalningsdel"
+	if err := RegisterLanguage("sv", texts); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if text, err := PaymentPartHeading.Text("sv"); err != nil || text != "Betalningsdel" {
+		t.Errorf("Unexpected heading: %v, %v", text, err)
+	}
+	if _, err := InformationSection(examplePayload1, "sv", WidthForPaymentPart(10), paymentPartInformation); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := RegisterLanguage("de", map[Heading]string{Heading(-1): "?"}); err == nil {
+		t.Error("Expected error due to unknown heading")
+	}
+	if err := RegisterLanguage("", texts); err == nil {
+		t.Error("Expected error due to empty language code")
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	if language, err := ParseLanguage("fr"); err != nil || language != FR {
+		t.Errorf("Unexpected language: %v, %v", language, err)
+	}
+	if _, err := ParseLanguage("ge"); err == nil {
+		t.Error("Expected error due to unsupported language")
+	}
+}
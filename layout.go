@@ -0,0 +1,163 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "fmt"
+
+// Millimeter is a length in millimeters. The standard and the style guide
+// give all dimensions in millimeters; use Unit to convert to PDF units.
+type Millimeter float64
+
+// BoxSize is the size of a box in millimeters.
+type BoxSize struct {
+	Width  Millimeter
+	Height Millimeter
+}
+
+// Layout contains geometry settings for drawing an invoice. All lengths are
+// given in millimeters. Zero box sizes select the size from the style guide.
+type Layout struct {
+	// OffsetX and OffsetY move the lower left corner of the invoice
+	// relative to the current position of the canvas.
+	OffsetX Millimeter
+	OffsetY Millimeter
+
+	// ReceiptAmountBox and PaymentAmountBox are drawn when the payload
+	// contains no amount. The style guide sizes are 30×10 mm and 40×15 mm.
+	ReceiptAmountBox BoxSize
+	PaymentAmountBox BoxSize
+
+	// ReceiptDebtorBox and PaymentDebtorBox are drawn below the heading
+	// “Payable by (name/address)” when the payload contains no ultimate
+	// debtor. The style guide sizes are 52×20 mm and 65×25 mm.
+	ReceiptDebtorBox BoxSize
+	PaymentDebtorBox BoxSize
+
+	// MinLeading and MinParagraphSpacing allow the information sections to
+	// be set tighter when their text does not fit with the normal spacing.
+	// MinLeading is the smallest line distance as a multiple of the font
+	// size; the normal line distance is 1.1 times the font size and the
+	// style guide minimum is 1.0. MinParagraphSpacing is the smallest space
+	// between paragraphs in points; the normal space is 3 pt and the
+	// minimum is 1 pt. Zero values disable the respective reduction.
+	MinLeading          float64
+	MinParagraphSpacing float64
+
+	// CountryLine selects when the country name is printed below the
+	// addresses. By default, it is printed for foreign addresses only.
+	CountryLine CountryLine
+
+	// AddressAbbreviation selects how address lines that are wider than
+	// their column are shortened before they are wrapped. By default,
+	// such lines are wrapped unchanged. AddressAbbreviations reports the
+	// abbreviations made.
+	AddressAbbreviation AddressAbbreviation
+}
+
+// Spacing limits of the information sections, see Layout.
+const (
+	minLeading          = 1.0
+	normalLeading       = 1.1
+	minParagraphSpacing = 1.0
+	paragraphSpacing    = 3.0
+)
+
+// DefaultLayout returns the layout of the style guide.
+func DefaultLayout() Layout {
+	return Layout{
+		ReceiptAmountBox: BoxSize{Width: 30, Height: 10},
+		PaymentAmountBox: BoxSize{Width: 40, Height: 15},
+		ReceiptDebtorBox: BoxSize{Width: 52, Height: 20},
+		PaymentDebtorBox: BoxSize{Width: 65, Height: 25},
+	}
+}
+
+// withDefaults returns the layout with zero box sizes replaced by the
+// sizes of the default layout.
+func (l Layout) withDefaults() Layout {
+	d := DefaultLayout()
+	if l.ReceiptAmountBox == (BoxSize{}) {
+		l.ReceiptAmountBox = d.ReceiptAmountBox
+	}
+	if l.PaymentAmountBox == (BoxSize{}) {
+		l.PaymentAmountBox = d.PaymentAmountBox
+	}
+	if l.ReceiptDebtorBox == (BoxSize{}) {
+		l.ReceiptDebtorBox = d.ReceiptDebtorBox
+	}
+	if l.PaymentDebtorBox == (BoxSize{}) {
+		l.PaymentDebtorBox = d.PaymentDebtorBox
+	}
+	return l
+}
+
+// Validate checks that the boxes fit into their sections: the amount
+// section of the receipt is 52×14 mm and the one of the payment part is
+// 51×22 mm, including the headings. The information section of the
+// receipt is 52×56 mm and the one of the payment part is 85×85 mm; each
+// debtor box must fit below its heading. It also checks that the spacing stays
+// within the limits of the style guide.
+func (l Layout) Validate() error {
+	l = l.withDefaults()
+	if err := l.ReceiptAmountBox.validate("receipt amount box", 52, 14-3); err != nil {
+		return err
+	}
+	if err := l.PaymentAmountBox.validate("payment amount box", 51, 22-3); err != nil {
+		return err
+	}
+	if err := l.ReceiptDebtorBox.validate("receipt debtor box", 52, 56-3); err != nil {
+		return err
+	}
+	if err := l.PaymentDebtorBox.validate("payment debtor box", 85, 85-3); err != nil {
+		return err
+	}
+	if l.MinLeading != 0 && (l.MinLeading < minLeading || l.MinLeading > normalLeading) {
+		return fmt.Errorf("Minimum leading must be between %v and %v: %v",
+			minLeading, normalLeading, l.MinLeading)
+	}
+	if l.MinParagraphSpacing != 0 &&
+		(l.MinParagraphSpacing < minParagraphSpacing || l.MinParagraphSpacing > paragraphSpacing) {
+		return fmt.Errorf("Minimum paragraph spacing must be between %v and %v pt: %v",
+			minParagraphSpacing, paragraphSpacing, l.MinParagraphSpacing)
+	}
+	return nil
+}
+
+func (b BoxSize) validate(name string, maxWidth, maxHeight Millimeter) error {
+	if b.Width <= 0 || b.Height <= 0 {
+		return fmt.Errorf("Size of %v must be positive: %v×%v mm", name, b.Width, b.Height)
+	}
+	if b.Width > maxWidth || b.Height > maxHeight {
+		return fmt.Errorf("Maximum size of %v is %v×%v mm: %v×%v mm",
+			name, maxWidth, maxHeight, b.Width, b.Height)
+	}
+	return nil
+}
+
+// Separator selects how the invoice is separated from the rest of the page.
+type Separator int
+
+const (
+	// NoSeparator draws no separation lines, as DrawInvoice does.
+	NoSeparator Separator = iota
+
+	// BorderSeparator draws lines with the text “Separate before paying
+	// in”, as DrawInvoiceWithBorder does.
+	BorderSeparator
+
+	// ScissorsSeparator draws a line with a scissors symbol between the
+	// receipt and the payment part, as DrawInvoiceWithScissors does.
+	ScissorsSeparator
+)
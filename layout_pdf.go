@@ -0,0 +1,39 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import "github.com/krepost/gopdf/pdf"
+
+// Mm is one millimeter in PDF units.
+const Mm = pdf.Cm / 10.0
+
+// Unit converts a length in millimeters to PDF units.
+func (m Millimeter) Unit() pdf.Unit {
+	return pdf.Unit(m) * Mm
+}
+
+// MmPoint returns the point with the given coordinates in millimeters.
+func MmPoint(x, y Millimeter) pdf.Point {
+	return pdf.Point{X: x.Unit(), Y: y.Unit()}
+}
+
+// DrawInvoiceWithLayout draws a standard Swiss QR Invoice like DrawInvoice,
+// using the given layout. The lower left corner of the invoice is the
+// current position moved by the offset of the layout.
+func DrawInvoiceWithLayout(canvas *pdf.Canvas, data Payload, language Language, layout Layout) error {
+	return drawInvoice(canvas, data, RenderOptions{Language: language, Layout: layout})
+}
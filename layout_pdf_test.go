@@ -0,0 +1,50 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+func TestMillimeterUnit(t *testing.T) {
+	if expected, actual := 4.6*pdf.Cm, Millimeter(46).Unit(); expected != actual {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+	expected := pdf.Point{6.2 * pdf.Cm, 10.5 * pdf.Cm}
+	if actual := MmPoint(62, 105); expected != actual {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
+
+func TestDrawInvoiceWithLayout(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	layout := Layout{
+		OffsetY:          20,
+		ReceiptAmountBox: BoxSize{Width: 32, Height: 11},
+	}
+	if err := DrawInvoiceWithLayout(canvas, examplePayload3, "en", layout); err != nil {
+		t.Error(err)
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
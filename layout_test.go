@@ -0,0 +1,50 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateLayout(t *testing.T) {
+	var testdata = []struct {
+		layout  Layout
+		message string
+	}{
+		{Layout{}, ""},
+		{DefaultLayout(), ""},
+		{Layout{OffsetX: 10, OffsetY: 5}, ""},
+		{Layout{ReceiptAmountBox: BoxSize{Width: 52, Height: 11}}, ""},
+		{Layout{ReceiptAmountBox: BoxSize{Width: 53, Height: 11}}, "Maximum size of receipt amount box"},
+		{Layout{PaymentAmountBox: BoxSize{Width: 40, Height: -1}}, "must be positive"},
+		{Layout{ReceiptDebtorBox: BoxSize{Width: 52, Height: 18}}, ""},
+		{Layout{PaymentDebtorBox: BoxSize{Width: 65, Height: 83}}, "Maximum size of payment debtor box"},
+		{Layout{ReceiptDebtorBox: BoxSize{Width: 0, Height: 20}}, "Size of receipt debtor box must be positive"},
+		{Layout{MinLeading: 1.0, MinParagraphSpacing: 1}, ""},
+		{Layout{MinLeading: 0.9}, "Minimum leading must be between 1 and 1.1: 0.9"},
+		{Layout{MinParagraphSpacing: 4}, "Minimum paragraph spacing must be between 1 and 3 pt"},
+	}
+	for i, data := range testdata {
+		err := data.layout.Validate()
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Letter is the document model of a complete A4 invoice letter: a sender
+// block, the recipient in the address window, place and date, a subject,
+// a free-text body, a table (typically line items and totals) and the QR
+// bill at the bottom of the page.
+type Letter struct {
+	// Sender is printed in small type at the top of the page.
+	Sender Entity
+
+	// Contact is printed below the sender. It is part of the letter only
+	// and is never encoded in the QR bill.
+	Contact Contact
+
+	// Recipient is printed in the address window on the right-hand side,
+	// suitable for C5 and DL envelopes with a right window.
+	Recipient Entity
+
+	// Place and Date are printed above the subject. The date is
+	// formatted according to the language of the letter.
+	Place string
+	Date  time.Time
+
+	// Subject is printed in bold.
+	Subject string
+
+	// Body is a text/template executed with the Letter as data. Paragraphs
+	// are separated by empty lines; long lines are wrapped.
+	Body string
+
+	// Data can be used to pass additional values to the body template.
+	Data interface{}
+
+	// Table is printed below the body.
+	Table LetterTable
+
+	// Bill is the payload of the QR bill at the bottom of the page.
+	Bill Payload
+}
+
+// LetterTable is a simple table. The first column is left aligned and
+// takes up the remaining width; all other columns are right aligned and
+// have a fixed width of 3 cm.
+type LetterTable struct {
+	Header []string
+	Rows   [][]string
+
+	// Totals are printed in bold below a line.
+	Totals [][]string
+}
+
+// Contact holds contact details of the sender. None of the fields is
+// required; empty fields are not printed.
+type Contact struct {
+	Phone   string
+	Email   string
+	Website string
+}
+
+// line returns the contact details on a single line, with localized labels.
+func (c Contact) line(language Language) string {
+	var parts []string
+	if c.Phone != "" {
+		parts = append(parts, localized(PhoneHeading, language)+" "+c.Phone)
+	}
+	if c.Email != "" {
+		parts = append(parts, localized(EmailHeading, language)+" "+c.Email)
+	}
+	if c.Website != "" {
+		parts = append(parts, c.Website)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// executeBody runs the body template.
+func (letter Letter) executeBody() (string, error) {
+	tmpl, err := template.New("body").Parse(letter.Body)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, letter); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buffer.String()), nil
+}
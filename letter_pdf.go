@@ -0,0 +1,201 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+// Layout of the letter on an A4 page, measured from the lower left corner.
+const (
+	letterLeft          = 2.0 * pdf.Cm
+	letterRight         = 19.0 * pdf.Cm
+	letterSenderTop     = 27.7 * pdf.Cm
+	letterWindowLeft    = 12.0 * pdf.Cm
+	letterWindowTop     = 24.5 * pdf.Cm
+	letterDateTop       = 19.5 * pdf.Cm
+	letterBottom        = 11.5 * pdf.Cm // 1 cm above the QR bill.
+	letterColumnWidth   = 3.0 * pdf.Cm
+	letterTextSize      = 10
+	letterTextLeading   = 13
+	letterSenderSize    = 8
+	letterSubjectSize   = 11
+	letterParagraphSkip = 6
+)
+
+// DrawLetter draws a complete invoice letter on an A4 canvas, starting at
+// the current position as the lower left corner of the page. The letter
+// and the QR bill are localized to the given language. An error is
+// returned if the text does not fit above the QR bill. The QR bill is drawn
+// with a border at the bottom of the page.
+func DrawLetter(canvas *pdf.Canvas, letter Letter, language Language) error {
+	if err := letter.Bill.Validate(); err != nil {
+		return err
+	}
+	if err := checkLanguage(language); err != nil {
+		return err
+	}
+	body, err := letter.executeBody()
+	if err != nil {
+		return err
+	}
+	canvas.Push()
+	defer canvas.Pop()
+	w, err := newLetterWriter(canvas)
+	if err != nil {
+		return err
+	}
+
+	w.y = letterSenderTop
+	if sender, err := letter.Sender.ToLines(); err != nil {
+		return err
+	} else if len(sender) > 0 {
+		w.line(w.regular, letterSenderSize, letterLeft, strings.Join(sender, " · "))
+	}
+	if contact := letter.Contact.line(language); contact != "" {
+		w.line(w.regular, letterSenderSize, letterLeft, contact)
+	}
+
+	if recipient, err := letter.Recipient.ToLocalizedLines(language, ForeignCountryLine); err != nil {
+		return err
+	} else {
+		w.y = letterWindowTop
+		for _, line := range recipient {
+			w.line(w.regular, letterTextSize, letterWindowLeft, line)
+		}
+	}
+
+	w.y = letterDateTop
+	dateLine := letter.Place
+	if !letter.Date.IsZero() {
+		if dateLine != "" {
+			dateLine = dateLine + ", "
+		}
+		dateLine = dateLine + letter.Date.Format(localized(DateFormatHeading, language))
+	}
+	if dateLine != "" {
+		w.line(w.regular, letterTextSize, letterLeft, dateLine)
+		w.y -= letterTextLeading
+	}
+	if letter.Subject != "" {
+		w.line(w.bold, letterSubjectSize, letterLeft, letter.Subject)
+		w.y -= letterParagraphSkip
+	}
+
+	width := float64((letterRight - letterLeft) / letterTextSize)
+	for _, paragraph := range strings.Split(body, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		for _, line := range reflowAtSpace(lines, width) {
+			w.line(w.regular, letterTextSize, letterLeft, line)
+		}
+		w.y -= letterParagraphSkip
+	}
+
+	w.table(letter.Table)
+	if w.y < letterBottom {
+		return errors.New("Letter text height too large.")
+	}
+	canvas.SetColor(0, 0, 0)
+	return DrawInvoiceWithBorder(canvas, letter.Bill, language)
+}
+
+// letterWriter draws lines of text from top to bottom.
+type letterWriter struct {
+	canvas  *pdf.Canvas
+	regular *pdf.Font
+	bold    *pdf.Font
+	y       pdf.Unit // Top of the next line.
+}
+
+func newLetterWriter(canvas *pdf.Canvas) (*letterWriter, error) {
+	doc := canvas.Document()
+	regular, err := doc.AddFont(pdf.Helvetica, pdf.WinAnsiEncoding)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := doc.AddFont(pdf.HelveticaBold, pdf.WinAnsiEncoding)
+	if err != nil {
+		return nil, err
+	}
+	canvas.SetColor(0, 0, 0)
+	return &letterWriter{canvas: canvas, regular: regular, bold: bold}, nil
+}
+
+// line draws s with its left edge at x and advances to the next line.
+func (w *letterWriter) line(font *pdf.Font, size pdf.Unit, x pdf.Unit, s string) {
+	w.text(font, size, x, s, false)
+	w.y -= size * letterTextLeading / letterTextSize
+}
+
+// text draws s at the current line, left aligned at x or right aligned
+// at x if alignRight is set.
+func (w *letterWriter) text(font *pdf.Font, size pdf.Unit, x pdf.Unit, s string, alignRight bool) {
+	text := new(pdf.Text)
+	text.UseFont(font, size, size)
+	text.Text(s)
+	if alignRight {
+		x = x - text.X()
+	}
+	w.canvas.Push()
+	w.canvas.Translate(x, w.y-size)
+	w.canvas.DrawText(text)
+	w.canvas.Pop()
+}
+
+// row draws one table row and advances to the next line.
+func (w *letterWriter) row(font *pdf.Font, cells []string) {
+	firstWidth := letterRight - letterLeft - pdf.Unit(len(cells)-1)*letterColumnWidth
+	for j, cell := range cells {
+		if j == 0 {
+			w.text(font, letterTextSize, letterLeft,
+				shortenToWidth(cell, float64(firstWidth/letterTextSize)), false)
+		} else {
+			w.text(font, letterTextSize, letterRight-pdf.Unit(len(cells)-1-j)*letterColumnWidth, cell, true)
+		}
+	}
+	w.y -= letterTextLeading
+}
+
+// rule draws a horizontal line across the text width.
+func (w *letterWriter) rule() {
+	path := new(pdf.Path)
+	path.Move(pdf.Point{letterLeft, w.y - 2})
+	path.Line(pdf.Point{letterRight, w.y - 2})
+	w.canvas.SetLineWidth(0.5)
+	w.canvas.Stroke(path)
+	w.y -= 5
+}
+
+// table draws a LetterTable.
+func (w *letterWriter) table(t LetterTable) {
+	if len(t.Header) > 0 {
+		w.row(w.bold, t.Header)
+		w.rule()
+	}
+	for _, cells := range t.Rows {
+		w.row(w.regular, cells)
+	}
+	if len(t.Totals) > 0 {
+		w.rule()
+		for _, cells := range t.Totals {
+			w.row(w.bold, cells)
+		}
+	}
+}
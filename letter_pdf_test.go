@@ -0,0 +1,48 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+func TestDrawLetter(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	if err := DrawLetter(canvas, exampleLetter, "de"); err != nil {
+		t.Error(err)
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDrawLetterTooLong(t *testing.T) {
+	letter := exampleLetter
+	letter.Body = strings.Repeat("Lorem ipsum dolor sit amet.\n\n", 30)
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	err := DrawLetter(canvas, letter, "de")
+	if err == nil || err.Error() != "Letter text height too large." {
+		t.Errorf("Expected error due to text height, got %v", err)
+	}
+}
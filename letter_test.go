@@ -0,0 +1,73 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var exampleLetter = Letter{
+	Sender:    examplePayload1.Creditor,
+	Contact:   Contact{Phone: "032 123 45 67", Email: "info@example.ch"},
+	Recipient: examplePayload1.UltimateDebtor,
+	Place:     "Biel",
+	Date:      time.Date(2019, time.May, 12, 0, 0, 0, 0, time.UTC),
+	Subject:   "Rechnung Nr. 3139",
+	Body: "Sehr geehrte Frau Rutschmann\n\n" +
+		"Für unsere Gartenarbeiten erlauben wir uns, Ihnen " +
+		"{{.Bill.CurrencyAmount.Currency}} {{printf \"%.2f\" .Bill.CurrencyAmount.Amount}} " +
+		"in Rechnung zu stellen.\n\nFreundliche Grüsse",
+	Table: LetterTable{
+		Header: []string{"Leistung", "Betrag"},
+		Rows: [][]string{
+			{"Gartenarbeiten", "3 500.00"},
+			{"Entsorgung Schnittmaterial", "449.75"},
+		},
+		Totals: [][]string{{"Total CHF", "3 949.75"}},
+	},
+	Bill: examplePayload1,
+}
+
+func TestLetterBody(t *testing.T) {
+	body, err := exampleLetter.executeBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "CHF 3949.75 in Rechnung") {
+		t.Errorf("Unexpected body: %v", body)
+	}
+}
+
+func TestContactLine(t *testing.T) {
+	var testdata = []struct {
+		contact  Contact
+		language Language
+		expected string
+	}{
+		{Contact{}, "de", ""},
+		{Contact{Phone: "032 123 45 67"}, "fr", "Tél. 032 123 45 67"},
+		{Contact{Email: "info@example.ch", Website: "www.example.ch"}, "en",
+			"Email info@example.ch · www.example.ch"},
+		{Contact{"032 123 45 67", "info@example.ch", "www.example.ch"}, "de",
+			"Tel. 032 123 45 67 · E-Mail info@example.ch · www.example.ch"},
+	}
+	for _, item := range testdata {
+		if got := item.contact.line(item.language); got != item.expected {
+			t.Errorf("Expected %q, got %q", item.expected, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"net/url"
+)
+
+// QRDataURI returns the QR code of the payload as PNG image in a data URI,
+// which can be used as source of an image in e-mails and web pages, e.g.
+// for customers who scan the code with their banking app on another device.
+func QRDataURI(p Payload) (string, error) {
+	img, err := CreateQR(p)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// DeepLink returns a link that passes the serialized payload to a payment
+// app. There is no single standard for such links, so the base of the link
+// is given by the caller, e.g. the URL or URL scheme documented by a bank.
+// The payload is appended, base64url encoded, as query parameter “qr”; any
+// existing query parameters of base are kept.
+func DeepLink(p Payload, base string) (string, error) {
+	if base == "" {
+		return "", errors.New("No link base specified.")
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := p.Serialize(&buffer); err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("qr", base64.RawURLEncoding.EncodeToString(buffer.Bytes()))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
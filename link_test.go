@@ -0,0 +1,74 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestQRDataURI(t *testing.T) {
+	uri, err := QRDataURI(examplePayload1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	const prefix = "data:image/png;base64,"
+	if !strings.HasPrefix(uri, prefix) {
+		t.Fatalf("Unexpected data URI: %.40s", uri)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Dx(); size != 1086 {
+		t.Errorf("Unexpected image size: %v", size)
+	}
+}
+
+func TestDeepLink(t *testing.T) {
+	link, err := DeepLink(examplePayload2, "https://pay.example.ch/qr?lang=de")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "pay.example.ch" || u.Query().Get("lang") != "de" {
+		t.Errorf("Unexpected link: %v", link)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(u.Query().Get("qr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected bytes.Buffer
+	if err := examplePayload2.Serialize(&expected); err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != expected.String() {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected.String(), string(payload))
+	}
+	if _, err := DeepLink(examplePayload2, ""); err == nil {
+		t.Error("Expected error due to missing link base")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm) && !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package swissqr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLockAge is the age from which lockFile considers a lock file left
+// behind by a crashed process. Locks are only held while a counter or a
+// registry file is rewritten.
+const staleLockAge = time.Minute
+
+// lockFile creates path exclusively, retrying until ctx is done. A lock
+// file older than staleLockAge is removed. The returned function removes
+// the lock file again.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Could not acquire lock %v: %v", path, ctx.Err())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
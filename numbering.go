@@ -0,0 +1,56 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Numbering issues sequential numbers, such as invoice numbers or the
+// running part of payment references. Implementations must be safe for
+// concurrent use and must never issue the same number twice.
+type Numbering interface {
+	Next(ctx context.Context) (string, error)
+}
+
+func formatNumber(format string, n int64) string {
+	if format == "" {
+		format = "%d"
+	}
+	return fmt.Sprintf(format, n)
+}
+
+// ReferenceNumbering issues QR references whose running part is taken
+// from a Numbering. Prefix is put in front of the number, e.g. a customer
+// identification assigned by the bank; the result is padded to 26 digits
+// and completed with the check digit.
+type ReferenceNumbering struct {
+	Numbering Numbering
+	Prefix    string
+}
+
+// NextReference returns a new QR reference.
+func (rn ReferenceNumbering) NextReference(ctx context.Context) (PaymentReference, error) {
+	n, err := rn.Numbering.Next(ctx)
+	if err != nil {
+		return PaymentReference{}, err
+	}
+	if padding := 26 - len(rn.Prefix) - len(n); padding > 0 {
+		n = strings.Repeat("0", padding) + n
+	}
+	return NewQRReference(rn.Prefix + n)
+}
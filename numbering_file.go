@@ -0,0 +1,73 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FileNumbering keeps the last issued number in a text file. Concurrent
+// processes are serialized by a lock on a file next to the counter file, so
+// several batch jobs on the same host can share a counter. The counter
+// starts at Start if the file does not exist yet.
+type FileNumbering struct {
+	// Path of the counter file. The lock file is Path + “.lock”.
+	Path string
+
+	// Start is the first number issued.
+	Start int64
+
+	// Format is applied to the number with fmt.Sprintf. Default is “%d”.
+	Format string
+
+	mu sync.Mutex
+}
+
+// Next increments the counter in the file and returns the new number.
+func (fn *FileNumbering) Next(ctx context.Context) (string, error) {
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+	unlock, err := lockFile(ctx, fn.Path+".lock")
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	next := fn.Start
+	if b, err := os.ReadFile(fn.Path); err == nil {
+		last, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("Corrupt counter file %v: %v", fn.Path, err)
+		}
+		next = last + 1
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	// Write to a temporary file first so that the counter is never lost.
+	tmp := fn.Path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(next, 10)+"\n"), 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, fn.Path); err != nil {
+		return "", err
+	}
+	return formatNumber(fn.Format, next), nil
+}
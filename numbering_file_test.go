@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileNumberingConcurrent(t *testing.T) {
+	numbering := &FileNumbering{
+		Path:   filepath.Join(t.TempDir(), "counter"),
+		Start:  1,
+		Format: "%04d",
+	}
+	const n = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	issued := map[string]bool{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A separate instance simulates a concurrent batch job.
+			other := &FileNumbering{Path: numbering.Path, Start: 1, Format: "%04d"}
+			s, err := other.Next(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if issued[s] {
+				t.Errorf("Number issued twice: %v", s)
+			}
+			issued[s] = true
+		}()
+	}
+	wg.Wait()
+	if s, err := numbering.Next(context.Background()); err != nil {
+		t.Error(err)
+	} else if s != "0051" {
+		t.Errorf("Expected 0051, got %v", s)
+	}
+}
+
+func TestFileNumberingStaleLock(t *testing.T) {
+	numbering := &FileNumbering{Path: filepath.Join(t.TempDir(), "counter"), Start: 1}
+	// A lock file left behind by a crashed process.
+	lock := numbering.Path + ".lock"
+	if err := os.WriteFile(lock, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if s, err := numbering.Next(ctx); err != nil || s != "1" {
+		t.Errorf("Expected 1, got: %v, %v", s, err)
+	}
+}
+
+func TestReferenceNumbering(t *testing.T) {
+	numbering := ReferenceNumbering{
+		Numbering: &FileNumbering{Path: filepath.Join(t.TempDir(), "counter"), Start: 3139},
+		Prefix:    "21",
+	}
+	ref, err := numbering.NextReference(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "210000000000000000000031398"
+	if actual := ref.Number.DigitalFormat(); actual != expected {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
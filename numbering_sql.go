@@ -0,0 +1,86 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SQLNumbering keeps counters in a database table. The counter is
+// incremented and read in one transaction; the row lock taken by the
+// update serializes concurrent jobs. The default statements expect a
+// table created like this:
+//
+//	CREATE TABLE swissqr_numbering (name VARCHAR(64) PRIMARY KEY, value BIGINT NOT NULL);
+//	INSERT INTO swissqr_numbering VALUES ('invoice', 0);
+type SQLNumbering struct {
+	DB *sql.DB
+
+	// Name of the counter, passed as only argument to both statements.
+	Name string
+
+	// Increment increments the counter. Default uses “?” placeholders.
+	Increment string
+
+	// Select reads the counter. Default uses “?” placeholders.
+	Select string
+
+	// Format is applied to the number with fmt.Sprintf. Default is “%d”.
+	Format string
+}
+
+const (
+	defaultIncrementStatement = "UPDATE swissqr_numbering SET value = value + 1 WHERE name = ?"
+	defaultSelectStatement    = "SELECT value FROM swissqr_numbering WHERE name = ?"
+)
+
+// Next increments the counter in the database and returns the new number.
+func (sn SQLNumbering) Next(ctx context.Context) (string, error) {
+	if sn.DB == nil {
+		return "", errors.New("No database specified.")
+	}
+	increment, query := sn.Increment, sn.Select
+	if increment == "" {
+		increment = defaultIncrementStatement
+	}
+	if query == "" {
+		query = defaultSelectStatement
+	}
+	tx, err := sn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	result, err := tx.ExecContext(ctx, increment, sn.Name)
+	if err != nil {
+		return "", err
+	}
+	if n, err := result.RowsAffected(); err == nil && n != 1 {
+		return "", fmt.Errorf("Unknown counter: %v", sn.Name)
+	}
+	var value int64
+	if err := tx.QueryRowContext(ctx, query, sn.Name).Scan(&value); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return formatNumber(sn.Format, value), nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSQLNumbering(t *testing.T) {
+	db := &fakeDB{counters: map[string]int64{"invoice": 41}}
+	numbering := SQLNumbering{DB: db.open(), Name: "invoice", Format: "INV-%05d"}
+	ctx := context.Background()
+	for _, expected := range []string{"INV-00042", "INV-00043"} {
+		if s, err := numbering.Next(ctx); err != nil || s != expected {
+			t.Errorf("Expected %v, got: %v, %v", expected, s, err)
+		}
+	}
+	numbering.Name = "order"
+	if _, err := numbering.Next(ctx); err == nil || err.Error() != "Unknown counter: order" {
+		t.Errorf("Expected error due to unknown counter, got: %v", err)
+	}
+	if _, err := (SQLNumbering{}).Next(ctx); err == nil || err.Error() != "No database specified." {
+		t.Errorf("Expected error due to missing database, got: %v", err)
+	}
+}
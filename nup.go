@@ -0,0 +1,87 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+// MaxInvoicesPerPage is the number of invoices that fit on an A4 page. The
+// style guide does not permit scaling the invoice, so two invoices of
+// 105 mm height fit onto the 297 mm of the page; three would need 315 mm.
+const MaxInvoicesPerPage = 2
+
+// RenderNUp draws the invoices for the payloads onto A4 pages, perPage
+// invoices per page, and writes the resulting PDF document to w. The
+// invoices of a page are placed one above the other at the bottom of the
+// page, the first one on top. A line across the page is drawn above each
+// invoice, so that the sheets can be cut. The options apply to each invoice.
+func RenderNUp(w io.Writer, payloads []Payload, perPage int, opts RenderOptions) error {
+	if perPage < 1 || perPage > MaxInvoicesPerPage {
+		return fmt.Errorf("Invoices per page must be between 1 and %d: %d", MaxInvoicesPerPage, perPage)
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.QRImage != nil && len(payloads) > 1 {
+		return errQRImageForSeveral
+	}
+	for i, data := range payloads {
+		if err := data.Validate(); err != nil {
+			return fmt.Errorf("Payload %d: %w", i, err)
+		}
+	}
+	doc := pdf.New()
+	for start := 0; start < len(payloads); start += perPage {
+		end := start + perPage
+		if end > len(payloads) {
+			end = len(payloads)
+		}
+		canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+		for j, data := range payloads[start:end] {
+			y := Millimeter(105 * (perPage - 1 - j))
+			canvas.Push()
+			canvas.Translate(0, y.Unit())
+			err := drawInvoice(canvas, data, opts)
+			canvas.Pop()
+			if err != nil {
+				canvas.Close()
+				return fmt.Errorf("Payload %d: %w", start+j, err)
+			}
+			drawCutLine(NewPDFRenderer(canvas), (y + 105).points())
+		}
+		if err := canvas.Close(); err != nil {
+			return err
+		}
+	}
+	return doc.Encode(w)
+}
+
+// drawCutLine draws a line across the page at height y.
+func drawCutLine(r Renderer, y float64) {
+	r.Push()
+	defer r.Pop()
+	r.SetGrey(0)
+	r.SetLineWidth(0.75)
+	path := new(Path)
+	path.Move(0, y)
+	path.Line(21.0*PointsPerCm, y)
+	r.Stroke(path)
+}
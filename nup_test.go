@@ -0,0 +1,63 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRenderNUp(t *testing.T) {
+	payloads := []Payload{examplePayload1, examplePayload2, examplePayload3}
+	for _, perPage := range []int{1, 2} {
+		var buffer bytes.Buffer
+		opts := RenderOptions{Language: "de", Separator: ScissorsSeparator}
+		if err := RenderNUp(&buffer, payloads, perPage, opts); err != nil {
+			t.Errorf("Per page %d: unexpected error: %v", perPage, err)
+		}
+		if !bytes.HasPrefix(buffer.Bytes(), []byte("%PDF")) {
+			t.Errorf("Per page %d: expected PDF, got: %q", perPage, buffer.String())
+		}
+	}
+
+	var testdata = []struct {
+		payloads []Payload
+		perPage  int
+		opts     RenderOptions
+		err      string
+	}{
+		{payloads, 3, RenderOptions{Language: "de"}, "Invoices per page must be between 1 and 2: 3"},
+		{payloads, 0, RenderOptions{Language: "de"}, "Invoices per page must be between 1 and 2: 0"},
+		{payloads, 2, RenderOptions{Language: "xx", FallbackLanguage: "sv"}, "Unsupported langauge: sv"},
+		{[]Payload{examplePayload1, {}}, 2, RenderOptions{Language: "de"}, "Payload 1: No account specified"},
+	}
+	for i, item := range testdata {
+		var buffer bytes.Buffer
+		err := RenderNUp(&buffer, item.payloads, item.perPage, item.opts)
+		if err == nil || !strings.Contains(err.Error(), item.err) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, item.err, err)
+		}
+	}
+	err := RenderNUp(io.Discard, []Payload{examplePayload1, {}}, 2, RenderOptions{Language: "de"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Account.IBAN" {
+		t.Errorf("Expected validation error, got %#v", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"image"
+)
+
+// RenderOptions controls how an invoice is rendered. The same options are
+// accepted by all output formats: DrawInvoiceWithOptions for PDF output,
+// CreateQRWithOptions for images and WriteQRSVGWithOptions for SVG. Options
+// that do not apply to an output format are ignored; e.g., the QR code
+// alone has neither a language nor a separator.
+//
+// The typeface is not an option: the style guide permits only Helvetica,
+// Arial, Frutiger and Liberation Sans, and the PDF output of this package
+// and of package fpdfrender uses the Helvetica of every PDF viewer, so no
+// font is embedded. RenderImage uses the Go fonts, since the image is a
+// preview. Other backends choose the font in the SetFont method of their
+// Renderer.
+type RenderOptions struct {
+	// Language of the invoice, e.g. DE.
+	Language Language
+
+	// FallbackLanguage is used instead of an unsupported Language, so
+	// that a misspelled locale still yields an invoice; the default is
+	// EN. Use ParseLanguage to reject unsupported languages instead.
+	FallbackLanguage Language
+
+	// Separator drawn around the invoice.
+	Separator Separator
+
+	// Draft renders an unpayable review copy: everything is printed in
+	// light grey, the QR code is deliberately invalidated, and invoices
+	// carry a “DRAFT” watermark across the payment part.
+	Draft bool
+
+	// Layout overrides the geometry of the invoice.
+	Layout Layout
+
+	// QRImage, if set, is drawn instead of encoding the payload again,
+	// e.g. an image from a cache created by CreateQR. It must be the QR
+	// code of the payload being rendered, which is checked before it is
+	// drawn, so it can only be used for a single invoice: functions that
+	// render several payloads reject it. QRImage is ignored for drafts.
+	QRImage image.Image
+
+	// VectorQR draws the modules of the QR code and the Swiss cross as
+	// filled rectangles instead of embedding a raster image, so that the
+	// code is sharp at any print resolution and the PDF is much smaller.
+	// QRImage is ignored if VectorQR is set. Only PDF output is affected.
+	VectorQR bool
+
+	// Preprinted prints only the variable data of the invoice—the QR code,
+	// the amount, the addresses, the reference and the other information—
+	// for paper with the static elements already printed: titles,
+	// headings, separators and the corners of empty boxes are left out.
+	// The variable data is at the same positions as on plain paper, so the
+	// Separator is ignored.
+	Preprinted bool
+}
+
+// errQRImageForSeveral is returned by the functions that render several
+// payloads with the same options if QRImage is set.
+var errQRImageForSeveral = errors.New("QRImage cannot be used for several payloads")
+
+// Validate checks that the language, or else the fallback language, is
+// supported and that the layout is valid.
+func (o RenderOptions) Validate() error {
+	if err := checkLanguage(o.language()); err != nil {
+		return err
+	}
+	return o.Layout.Validate()
+}
+
+// language returns the language used for rendering.
+func (o RenderOptions) language() Language {
+	if checkLanguage(o.Language) == nil {
+		return o.Language
+	}
+	if o.FallbackLanguage == "" {
+		return EN
+	}
+	return o.FallbackLanguage
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidateRenderOptions(t *testing.T) {
+	var testdata = []struct {
+		opts    RenderOptions
+		message string
+	}{
+		{RenderOptions{Language: "de"}, ""},
+		{RenderOptions{Language: "fr", Separator: BorderSeparator, Draft: true}, ""},
+		{RenderOptions{}, ""},
+		{RenderOptions{Language: "it", Layout: Layout{MinLeading: 2}}, "Minimum leading"},
+		{RenderOptions{Language: "de_CH", FallbackLanguage: "en"}, ""},
+		{RenderOptions{Language: "de_CH", FallbackLanguage: "sv"}, "Unsupported langauge: sv"},
+	}
+	for i, data := range testdata {
+		err := data.opts.Validate()
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
+
+func TestDraftOptionsForQR(t *testing.T) {
+	opts := RenderOptions{Draft: true}
+	img, err := CreateQRWithOptions(examplePayload1, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r, _, _, _ := img.At(543, 480).RGBA(); r == 0 {
+		t.Error("Expected grey pixel in draft QR code")
+	}
+	var buffer bytes.Buffer
+	if err := WriteQRSVGWithOptions(&buffer, examplePayload1, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buffer.String(), `fill="#999999"`) || strings.Contains(buffer.String(), `fill="#000"`) {
+		t.Error("Expected grey SVG for draft")
+	}
+}
+
+func TestFallbackLanguage(t *testing.T) {
+	var testdata = []struct {
+		opts     RenderOptions
+		expected Language
+	}{
+		{RenderOptions{Language: "fr", FallbackLanguage: "en"}, "fr"},
+		{RenderOptions{Language: "fr-CH", FallbackLanguage: "en"}, "en"},
+		{RenderOptions{Language: "fr-CH"}, "en"},
+		{RenderOptions{}, "en"},
+	}
+	for i, data := range testdata {
+		if actual := data.opts.language(); actual != data.expected {
+			t.Errorf("Item %v: expected %v, got %v", i, data.expected, actual)
+		}
+	}
+}
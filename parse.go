@@ -0,0 +1,368 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field positions in the payload text; see the Swiss QR standard.
+const (
+	fieldQRType           = 0
+	fieldVersion          = 1
+	fieldCoding           = 2
+	fieldAccount          = 3
+	fieldCreditor         = 4
+	fieldUltimateCreditor = 11
+	fieldAmount           = 18
+	fieldCurrency         = 19
+	fieldUltimateDebtor   = 20
+	fieldReferenceType    = 27
+	fieldReference        = 28
+	fieldMessage          = 29
+	fieldTrailer          = 30
+	fieldBillInformation  = 31
+	fieldProcedures       = 32
+	entityFields          = 7
+)
+
+// Parse turns the text of a Swiss QR code, as written by Serialize, back
+// into a Payload. Lines may be separated by CR LF or LF; use NormalizeScan
+// first for raw scanner input. The labels of alternative procedures are
+// not part of the QR code, so the procedures are returned without label
+// and must be labelled before the payload is rendered again. Apart from
+// this, the payload is validated. Version is set to SpecVersion23 if the
+// text contains characters of the extended character set.
+func Parse(s string) (Payload, error) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	fields := strings.Split(s, "\n")
+	if len(fields) < fieldBillInformation || len(fields) > fieldProcedures+2 {
+		return Payload{}, fmt.Errorf("Invalid number of lines: %d", len(fields))
+	}
+	if fields[fieldQRType] != "SPC" {
+		return Payload{}, fmt.Errorf("Invalid QR type: %v", fields[fieldQRType])
+	}
+	if !strings.HasPrefix(fields[fieldVersion], "02") || len(fields[fieldVersion]) != 4 {
+		return Payload{}, fmt.Errorf("Unsupported version: %v", fields[fieldVersion])
+	}
+	if fields[fieldCoding] != "1" {
+		return Payload{}, fmt.Errorf("Unsupported coding type: %v", fields[fieldCoding])
+	}
+	if fields[fieldTrailer] != "EPD" {
+		return Payload{}, fmt.Errorf("Invalid trailer: %v", fields[fieldTrailer])
+	}
+	// Missing optional fields at the end are empty.
+	for len(fields) < fieldProcedures+2 {
+		fields = append(fields, "")
+	}
+
+	var p Payload
+	var err error
+	if p.Account, err = parseAccount(fields[fieldAccount]); err != nil {
+		return Payload{}, err
+	}
+	if p.Creditor, err = parseEntity(fields[fieldCreditor:]); err != nil {
+		return Payload{}, err
+	}
+	if p.UltimateCreditor, err = parseEntity(fields[fieldUltimateCreditor:]); err != nil {
+		return Payload{}, err
+	}
+	if p.CurrencyAmount, err = parseAmount(fields[fieldAmount], fields[fieldCurrency]); err != nil {
+		return Payload{}, err
+	}
+	if p.UltimateDebtor, err = parseEntity(fields[fieldUltimateDebtor:]); err != nil {
+		return Payload{}, err
+	}
+	if p.Reference, err = parseReference(fields[fieldReferenceType], fields[fieldReference]); err != nil {
+		return Payload{}, err
+	}
+	p.AdditionalInformation.UnstructuredMessage = fields[fieldMessage]
+	if err := parsePaymentBillInformation(&p.AdditionalInformation, fields[fieldBillInformation]); err != nil {
+		return Payload{}, err
+	}
+	for _, procedure := range fields[fieldProcedures:] {
+		if procedure != "" {
+			p.AlternativeProcedureParameters = append(p.AlternativeProcedureParameters,
+				AlternativeProcedure{Procedure: procedure})
+		}
+	}
+
+	// The version is not encoded either; characters beyond the character
+	// set of version 2.0 require version 2.3.
+	if ValidateCharacterSet(strings.ReplaceAll(s, "\n", "")) != nil {
+		p.Version = SpecVersion23
+	}
+
+	// Validate with placeholder labels, since labels are not encoded.
+	check := p
+	check.AlternativeProcedureParameters = nil
+	for _, ap := range p.AlternativeProcedureParameters {
+		ap.Label = "-"
+		check.AlternativeProcedureParameters = append(check.AlternativeProcedureParameters, ap)
+	}
+	if err := check.Validate(); err != nil {
+		return Payload{}, err
+	}
+	return p, nil
+}
+
+// Decode reads the text of a Swiss QR code from r and parses it with Parse.
+func Decode(r io.Reader) (Payload, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Payload{}, err
+	}
+	return Parse(string(b))
+}
+
+func parseAccount(s string) (AccountNumber, error) {
+	if s == "" {
+		return AccountNumber{}, nil
+	}
+	code, err := ParseIBAN(s)
+	if err != nil {
+		return AccountNumber{}, err
+	}
+	return AccountNumber{IBAN: code}, nil
+}
+
+// parseEntity parses the seven fields of an entity starting at fields[0].
+func parseEntity(fields []string) (Entity, error) {
+	f := fields[:entityFields]
+	if strings.Join(f, "") == "" {
+		return Entity{}, nil
+	}
+	e := Entity{Name: f[1], CountryCode: f[6]}
+	switch f[0] {
+	case "S":
+		e.Address = StructuredAddress{
+			StreetName:     f[2],
+			BuildingNumber: f[3],
+			PostCode:       f[4],
+			TownName:       f[5],
+		}
+	case "K":
+		if f[4] != "" || f[5] != "" {
+			return Entity{}, fmt.Errorf("Post code and town must be empty for combined address: %v", e.Name)
+		}
+		e.Address = CombinedAddress{AddressLine1: f[2], AddressLine2: f[3]}
+	default:
+		return Entity{}, fmt.Errorf("Unknown address type: %v", f[0])
+	}
+	return e, nil
+}
+
+// amountPattern matches an amount as encoded in the QR code.
+var amountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})?$`)
+
+func parseAmount(amount, currency string) (PaymentAmount, error) {
+	pa := PaymentAmount{Currency: currency}
+	if amount == "" {
+		return pa, nil
+	}
+	// Only the format of the standard: digits with at most two decimals
+	// after a point, so that strconv does not accept “NaN” or “1e3”.
+	if !amountPattern.MatchString(amount) {
+		return PaymentAmount{}, fmt.Errorf("Invalid amount: %v", amount)
+	}
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return PaymentAmount{}, fmt.Errorf("Invalid amount: %v", amount)
+	}
+	pa.Amount = value
+	if value == 0 {
+		pa.Mode = AmountZero
+	}
+	return pa, nil
+}
+
+func parseReference(referenceType, reference string) (PaymentReference, error) {
+	switch referenceType {
+	case "QRR":
+		ref, err := ParseQRReference(reference)
+		if err != nil {
+			return PaymentReference{}, err
+		}
+		return PaymentReference{Number: ref}, nil
+	case "SCOR":
+		ref, err := ParseCreditorReference(reference)
+		if err != nil {
+			return PaymentReference{}, err
+		}
+		return PaymentReference{Number: ref}, nil
+	case "NON":
+		if reference != "" {
+			return PaymentReference{}, fmt.Errorf("Reference must be empty for type NON: %v", reference)
+		}
+		return PaymentReference{}, nil
+	}
+	return PaymentReference{}, fmt.Errorf("Unknown reference type: %v", referenceType)
+}
+
+// ParseBillInformation parses bill information in the syntax “//S1/10/…”
+// written by BillInformation.ToString, e.g. the structured message of a QR
+// code received from a supplier. An empty string is empty bill information.
+// Parse only calls it for S1 and keeps bill information in other syntaxes
+// in PaymentInformation.RawBillInformation.
+// The result is not validated; call Validate to check it.
+func ParseBillInformation(s string) (BillInformation, error) {
+	var bi BillInformation
+	if s == "" {
+		return bi, nil
+	}
+	if !strings.HasPrefix(s, "//S1/") {
+		return bi, fmt.Errorf("Unsupported bill information: %v", s)
+	}
+	parts, err := splitBillInformation(strings.TrimPrefix(s, "//S1/"))
+	if err != nil {
+		return bi, err
+	}
+	if len(parts)%2 != 0 {
+		return bi, fmt.Errorf("Invalid bill information: %v", s)
+	}
+	for i := 0; i < len(parts); i += 2 {
+		tag, value := parts[i], parts[i+1]
+		var err error
+		switch tag {
+		case "10":
+			bi.InvoiceNumber = value
+		case "11":
+			bi.InvoiceDate, err = parseDates(value)
+		case "20":
+			bi.CustomerReference = value
+		case "30":
+			bi.VATNumber = value
+		case "31":
+			bi.VATDates, err = parseDates(value)
+		case "32":
+			bi.VATRates, err = parseTaxRates(value)
+		case "33":
+			bi.VATImportTaxRates, err = parseTaxRates(value)
+		case "40":
+			bi.Conditions, err = parsePaymentConditions(value)
+		default:
+			err = fmt.Errorf("Unknown bill information tag: %v", tag)
+		}
+		if err != nil {
+			return BillInformation{}, err
+		}
+	}
+	return bi, nil
+}
+
+// parsePaymentBillInformation sets the bill information of pi to s: bill
+// information in S1 syntax is decoded, and other syntaxes are kept as
+// they are.
+func parsePaymentBillInformation(pi *PaymentInformation, s string) error {
+	if s != "" && !strings.HasPrefix(s, "//S1/") {
+		pi.RawBillInformation = s
+		return nil
+	}
+	var err error
+	pi.StructuredMessage, err = ParseBillInformation(s)
+	return err
+}
+
+// splitBillInformation splits bill information at each “/” that is not
+// escaped and removes the escaping “\” before “/” and “\”.
+func splitBillInformation(s string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '/':
+			parts = append(parts, part.String())
+			part.Reset()
+		case '\\':
+			if i+1 == len(s) || (s[i+1] != '/' && s[i+1] != '\\') {
+				return nil, fmt.Errorf("Invalid escape sequence in bill information: %v", s)
+			}
+			i++
+			part.WriteByte(s[i])
+		default:
+			part.WriteByte(s[i])
+		}
+	}
+	return append(parts, part.String()), nil
+}
+
+// parseDates parses a date “YYMMDD” or a date interval “YYMMDDYYMMDD”.
+func parseDates(s string) (dates, error) {
+	if len(s) != 6 && len(s) != 12 {
+		return dates{}, fmt.Errorf("Invalid date: %v", s)
+	}
+	date, err := time.Parse("060102", s[:6])
+	if err != nil {
+		return dates{}, fmt.Errorf("Invalid date: %v", s)
+	}
+	d := dates{Date: date}
+	if len(s) == 12 {
+		if d.End, err = time.Parse("060102", s[6:]); err != nil {
+			return dates{}, fmt.Errorf("Invalid date: %v", s)
+		}
+	}
+	return d, nil
+}
+
+// parseTaxRates parses “rate” or “rate:amount;rate:amount…”.
+func parseTaxRates(s string) (TaxRates, error) {
+	rates := TaxRates{}
+	for _, field := range strings.Split(s, ";") {
+		values := strings.Split(field, ":")
+		if len(values) > 2 {
+			return nil, fmt.Errorf("Invalid tax rate: %v", field)
+		}
+		var rate TaxRate
+		var err error
+		if rate.RatePercent, err = strconv.ParseFloat(values[0], 64); err != nil {
+			return nil, fmt.Errorf("Invalid tax rate: %v", field)
+		}
+		if len(values) == 2 {
+			if rate.Amount, err = strconv.ParseFloat(values[1], 64); err != nil {
+				return nil, fmt.Errorf("Invalid tax rate: %v", field)
+			}
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// parsePaymentConditions parses “discount:days;discount:days…”.
+func parsePaymentConditions(s string) (PaymentConditions, error) {
+	conditions := PaymentConditions{}
+	for _, field := range strings.Split(s, ";") {
+		values := strings.Split(field, ":")
+		if len(values) != 2 {
+			return nil, fmt.Errorf("Invalid payment condition: %v", field)
+		}
+		var condition PaymentCondition
+		var err error
+		if condition.DiscountPercent, err = strconv.ParseFloat(values[0], 64); err != nil {
+			return nil, fmt.Errorf("Invalid payment condition: %v", field)
+		}
+		if condition.NumberOfDays, err = strconv.Atoi(values[1]); err != nil {
+			return nil, fmt.Errorf("Invalid payment condition: %v", field)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
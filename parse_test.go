@@ -0,0 +1,145 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	zero := examplePayload3
+	zero.CurrencyAmount.Mode = AmountZero
+	extended := examplePayload3
+	extended.Version = SpecVersion23
+	extended.Creditor.Name = "Søren Østergaard"
+	raw := examplePayload3
+	raw.AdditionalInformation.RawBillInformation = "//XY/Rechnung 42"
+	for i, p := range []Payload{examplePayload1, examplePayload2, examplePayload3, exampleCreditorReference, zero, extended, raw} {
+		var buffer bytes.Buffer
+		if err := p.Serialize(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := Parse(buffer.String())
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+			continue
+		}
+		// Labels are not encoded in the QR code.
+		expected := p
+		expected.AlternativeProcedureParameters = nil
+		for _, ap := range p.AlternativeProcedureParameters {
+			expected.AlternativeProcedureParameters = append(expected.AlternativeProcedureParameters,
+				AlternativeProcedure{Procedure: ap.Procedure})
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Item %v: Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", i, expected, actual)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := examplePayload2.Serialize(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	valid := buffer.String()
+	var testdata = []struct {
+		text    string
+		message string
+	}{
+		{strings.ReplaceAll(valid, "\r\n", "\n"), ""},
+		{valid + "\r\n", ""},
+		{"SPC\r\n0200\r\n1", "Invalid number of lines: 3"},
+		{strings.Replace(valid, "SPC", "XYZ", 1), "Invalid QR type: XYZ"},
+		{strings.Replace(valid, "0200", "0100", 1), "Unsupported version: 0100"},
+		{strings.Replace(valid, "EPD", "END", 1), "Invalid trailer: END"},
+		{strings.Replace(valid, "\r\nS\r\n", "\r\nX\r\n", 1), "Unknown address type: X"},
+		{strings.Replace(valid, "1949.75", "1949,75", 1), "Invalid amount: 1949,75"},
+		{strings.Replace(valid, "1949.75", "NaN", 1), "Invalid amount: NaN"},
+		{strings.Replace(valid, "1949.75", "+Inf", 1), "Invalid amount: +Inf"},
+		{strings.Replace(valid, "1949.75", "1e3", 1), "Invalid amount: 1e3"},
+		{strings.Replace(valid, "1949.75", "12.345", 1), "Invalid amount: 12.345"},
+		{strings.Replace(valid, "1949.75", "-0", 1), "Invalid amount: -0"},
+		{strings.Replace(valid, "QRR", "ABC", 1), "Unknown reference type: ABC"},
+		{strings.Replace(valid, "/11/190512", "/11/1905", 1), "Invalid date: 1905"},
+		{strings.Replace(valid, "/40/2:10;0:30", "/40/2", 1), "Invalid payment condition: 2"},
+		{strings.Replace(valid, "/10/", "/99/", 1), "Unknown bill information tag: 99"},
+		{strings.Replace(valid, "CHF", "USD", 1), "Currency must be CHF or EUR: USD"},
+	}
+	for i, data := range testdata {
+		_, err := Parse(data.text)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := examplePayload1.Serialize(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Decode(&buffer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Creditor.Name != examplePayload1.Creditor.Name {
+		t.Errorf("Unexpected creditor: %v", p.Creditor.Name)
+	}
+}
+
+func TestParseBillInformation(t *testing.T) {
+	for i, bi := range []BillInformation{
+		{},
+		examplePayload2.AdditionalInformation.StructuredMessage,
+		{
+			VATNumber:         "106017086",
+			VATDates:          StartAndEndDate(2019, time.May, 1, 2019, time.May, 31),
+			VATRates:          TaxRates{{RatePercent: 7.7, Amount: 185.65}, {RatePercent: 2.5, Amount: 5.5}},
+			VATImportTaxRates: TaxRates{{RatePercent: 7.7, Amount: 48.12}},
+		},
+		{InvoiceNumber: `2020/05\12`, CustomerReference: "/"},
+	} {
+		actual, err := ParseBillInformation(bi.ToString())
+		if err != nil {
+			t.Errorf("Item %v: unexpected error: %v", i, err)
+			continue
+		}
+		if actual.ToString() != bi.ToString() {
+			t.Errorf("Item %v: expected %#v, got %#v", i, bi.ToString(), actual.ToString())
+		}
+	}
+	for i, test := range []struct {
+		input    string
+		expected string
+	}{
+		{"//S2/10/1", "Unsupported bill information"},
+		{`//S1/10/1\`, "Invalid escape sequence in bill information"},
+		{`//S1/10/1\x`, "Invalid escape sequence in bill information"},
+		{"//S1/10/2020/05", "Invalid bill information"},
+	} {
+		if _, err := ParseBillInformation(test.input); err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
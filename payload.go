@@ -14,14 +14,13 @@
 
 // package swissqr creates a QR code for electronic bills as defined in version
 // 2.0 of the document “Schweizer Implementation Guidelines QR-Rechnung”, which
-// can be downloaded from https://www.paymentstandards.ch/, and version 1.2 of
+// can be downloaded from https://www.paymentstandards.ch/, or in version 2.3
+// if selected with Payload.Version, and version 1.2 of
 // the document “Syntaxdefinition der Rechnungsinformationen (S1) bei der QR-
 // Rechnung”, which can be downloaded from https://www.swiss-qr-invoice.org/.
 package swissqr
 
 import (
-	"github.com/almerlucke/go-iban/iban"
-	"github.com/krepost/structref"
 	"io"
 )
 
@@ -39,7 +38,8 @@ type Payload struct {
 	// The creditor. Mandatory data group.
 	Creditor Entity
 
-	// Information about the ultimate creditor. For future use.
+	// Information about the ultimate creditor. For future use; it must be
+	// empty unless EnableUltimateCreditor is set.
 	UltimateCreditor Entity
 
 	// The payment amount in a given currency. Mandatory data group.
@@ -60,6 +60,19 @@ type Payload struct {
 	// alternative scheme according to the syntax definition in
 	// the section on “Alternative procedure” in the Swiss QR standard.
 	AlternativeProcedureParameters AlternativeProcedures
+
+	// Version selects the version of the implementation guidelines whose
+	// rules apply to validation and serialization: the character set, the
+	// permitted address types and the version in the header of the QR
+	// code. The default is version 2.0.
+	Version SpecVersion `json:",omitempty"`
+
+	// EnableUltimateCreditor permits an UltimateCreditor, which the
+	// standard reserves for future use, e.g. for receivers that already
+	// process it. The ultimate creditor is then validated like the
+	// creditor, encoded in the QR code and printed as “In favour of”
+	// below the creditor on the receipt and the payment part.
+	EnableUltimateCreditor bool `json:",omitempty"`
 }
 
 type qrAddress interface {
@@ -116,17 +129,38 @@ type PaymentAmount struct {
 
 	// Mandatory payment currency. Only "CHF" and "EUR" are permitted.
 	Currency string
+
+	// Mode selects how a zero amount is encoded and printed.
+	Mode AmountMode
 }
 
+// AmountMode selects how a payment amount of zero is handled. Amounts other
+// than zero are always encoded and printed.
+type AmountMode int
+
+const (
+	// AmountBox leaves the amount empty, so that the debtor fills it in;
+	// an empty box is printed instead of the amount.
+	AmountBox AmountMode = iota
+
+	// AmountZero encodes and prints the amount “0.00”, as used for
+	// notification bills that are not to be paid.
+	AmountZero
+
+	// AmountOmit leaves the amount empty like AmountBox, but prints no
+	// box, for billers who do not want the debtor to fill in an amount.
+	AmountOmit
+)
+
 // Account contains an IBAN or QR-IBAN.
 type AccountNumber struct {
-	IBAN *iban.IBAN
+	IBAN *IBAN
 }
 
 // NewIBANOrDie is a helper function to set the IBAN field in Payload.
 // Useful when initializing a Payload struct programmatically.
 func NewIBANOrDie(s string) AccountNumber {
-	iban, err := iban.NewIBAN(s)
+	iban, err := ParseIBAN(s)
 	if err != nil {
 		panic(err)
 	}
@@ -136,7 +170,7 @@ func NewIBANOrDie(s string) AccountNumber {
 // PaymentReference contains either a Swiss ESR reference number,
 // or a structured creditor reference according to ISO 11649, or nil.
 type PaymentReference struct {
-	Number structref.Printer
+	Number ReferencePrinter
 }
 
 // PaymentInformation includes additional unstructured or coded
@@ -151,6 +185,20 @@ type PaymentInformation struct {
 	// StructuredInformation contains coded information for automated
 	// booking of the payment. Optional field.
 	StructuredMessage BillInformation
+
+	// RawBillInformation contains bill information in a syntax other than
+	// S1, e.g. “//XY/…”, as read by Parse. It is encoded as is and must
+	// be empty if StructuredMessage is set. Optional field.
+	RawBillInformation string `json:",omitempty"`
+}
+
+// BillInformation returns the bill information as encoded in the QR code:
+// the StructuredMessage in S1 syntax, or else the RawBillInformation.
+func (pi PaymentInformation) BillInformation() string {
+	if s := pi.StructuredMessage.ToString(); s != "" {
+		return s
+	}
+	return pi.RawBillInformation
 }
 
 // AlternativeProcedure defines an alternateive payment procedure.
@@ -160,6 +208,20 @@ type AlternativeProcedure struct {
 
 	// Procedure describes the alternative payment procedure.
 	Procedure string
+
+	// Labels optionally contains localized versions of Label, keyed by
+	// language. Like Label, they are only printed and never encoded in
+	// the QR code.
+	Labels map[string]string
+}
+
+// LocalizedLabel returns the label for the given language, or Label if
+// there is no localized label for the language.
+func (ap AlternativeProcedure) LocalizedLabel(language Language) string {
+	if label, ok := ap.Labels[string(language)]; ok {
+		return label
+	}
+	return ap.Label
 }
 
 // AlternativeProcedures is a list of alternative payment procedures.
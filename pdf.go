@@ -12,11 +12,12 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !(js && wasm)
+
 package swissqr
 
 import (
-	"errors"
-	"math"
+	"image"
 
 	"github.com/krepost/gopdf/pdf"
 )
@@ -26,21 +27,8 @@ import (
 // is localized to the given language. The size of the invoice is “DIN A6/5
 // Querformat”, i.e., 210 mm wide and 105 mm high. It is the responsibility
 // of the caller to make sure that the invoice area in the PDF is clear.
-func DrawInvoice(canvas *pdf.Canvas, data Payload, language string) error {
-	canvas.Push()
-	defer canvas.Pop()
-	// drawSupportLines(canvas) // Only for debugging.
-	invoice, err := setupForNewInvoice(canvas, data, language)
-	if err != nil {
-		return err
-	}
-	if err = invoice.drawReceiptPart(); err != nil {
-		return err
-	}
-	if err = invoice.drawPaymentPart(); err != nil {
-		return err
-	}
-	return nil
+func DrawInvoice(canvas *pdf.Canvas, data Payload, language Language) error {
+	return drawInvoice(canvas, data, RenderOptions{Language: language})
 }
 
 // DrawInvoiceWithBorder draws a standard Swiss QR Invoice on the given canvas,
@@ -51,24 +39,11 @@ func DrawInvoice(canvas *pdf.Canvas, data Payload, language string) error {
 // removed from the rest of the document is printed above the border. It is
 // the responsibility of the caller to make sure that the invoice area in the
 // PDF is clear.
-func DrawInvoiceWithBorder(canvas *pdf.Canvas, data Payload, language string) error {
-	canvas.Push()
-	defer canvas.Pop()
-	// drawSupportLines(canvas) // Only for debugging.
-	invoice, err := setupForNewInvoice(canvas, data, language)
-	if err != nil {
-		return err
-	}
-	if err = invoice.drawReceiptPart(); err != nil {
-		return err
-	}
-	if err = invoice.drawPaymentPart(); err != nil {
-		return err
-	}
-	if err = invoice.drawBorderWithText(); err != nil {
-		return err
-	}
-	return nil
+func DrawInvoiceWithBorder(canvas *pdf.Canvas, data Payload, language Language) error {
+	return drawInvoice(canvas, data, RenderOptions{
+		Language:  language,
+		Separator: BorderSeparator,
+	})
 }
 
 // DrawInvoiceWithScissors draws a standard Swiss QR Invoice on the given
@@ -79,79 +54,95 @@ func DrawInvoiceWithBorder(canvas *pdf.Canvas, data Payload, language string) er
 // symbol indicating that the receipt part is to be removed is added next to
 // the line. It is the responsibility of the caller to make sure that the
 // invoice area in the PDF is clear.
-func DrawInvoiceWithScissors(canvas *pdf.Canvas, data Payload, language string) error {
+func DrawInvoiceWithScissors(canvas *pdf.Canvas, data Payload, language Language) error {
+	return drawInvoice(canvas, data, RenderOptions{
+		Language:  language,
+		Separator: ScissorsSeparator,
+	})
+}
+
+// DrawInvoicePreview draws a Swiss QR Invoice for internal review. The
+// invoice is drawn like by DrawInvoiceWithBorder, but in light grey and with
+// a “DRAFT” banner across the payment part. The QR code is deliberately
+// invalidated, so that a review copy can never be paid by accident. It is
+// the responsibility of the caller to make sure that the invoice area in the
+// PDF is clear.
+func DrawInvoicePreview(canvas *pdf.Canvas, data Payload, language Language) error {
+	return drawInvoice(canvas, data, RenderOptions{
+		Language:  language,
+		Separator: BorderSeparator,
+		Draft:     true,
+	})
+}
+
+// DrawInvoiceWithOptions draws a Swiss QR Invoice on the given canvas as
+// selected by the options. The lower left corner of the invoice is the
+// current position moved by the offset of the layout. It is the
+// responsibility of the caller to make sure that the invoice area in the
+// PDF is clear.
+func DrawInvoiceWithOptions(canvas *pdf.Canvas, data Payload, opts RenderOptions) error {
+	return drawInvoice(canvas, data, opts)
+}
+
+// DrawInvoiceAt draws a standard Swiss QR Invoice like DrawInvoice with its
+// lower left corner at the given point instead of the current position,
+// e.g. for stationery with the invoice in a non-standard position. The
+// canvas is left unchanged.
+func DrawInvoiceAt(canvas *pdf.Canvas, at pdf.Point, data Payload, language Language) error {
 	canvas.Push()
 	defer canvas.Pop()
+	canvas.Translate(at.X, at.Y)
+	return drawInvoice(canvas, data, RenderOptions{Language: language})
+}
+
+// drawInvoice implements all variants of DrawInvoice.
+func drawInvoice(canvas *pdf.Canvas, data Payload, opts RenderOptions) error {
 	// drawSupportLines(canvas) // Only for debugging.
-	invoice, err := setupForNewInvoice(canvas, data, language)
-	if err != nil {
-		return err
-	}
-	if err = invoice.drawReceiptPart(); err != nil {
-		return err
-	}
-	if err = invoice.drawPaymentPart(); err != nil {
-		return err
-	}
-	if err = invoice.drawSeparatorWithScissors(); err != nil {
-		return err
-	}
-	return nil
+	return RenderInvoice(NewPDFRenderer(canvas), data, opts)
 }
 
-// drawBorderWithText draws a solid black border on top of the QR invoice as
-// well as between the receipt part and the payment part. A text indicating
-// that the payment part should be detached from the rest of the paper is also
-// drawn. It is assumed that the current point is at the lower left corner of
-// the invoice area.
-func (i *pdfInvoice) drawBorderWithText() error {
-	i.canvas.Push()
-	defer i.canvas.Pop()
-	path := new(pdf.Path)
-	path.Move(pdf.Point{0, 10.5 * pdf.Cm})
-	path.Line(pdf.Point{21.0 * pdf.Cm, 10.5 * pdf.Cm})
-	path.Move(pdf.Point{6.2 * pdf.Cm, 0})
-	path.Line(pdf.Point{6.2 * pdf.Cm, 10.5 * pdf.Cm})
-	i.canvas.SetStrokeColor(0, 0, 0)
-	i.canvas.SetLineWidth(1.0)
-	i.canvas.Stroke(path)
-	if sep, err := BorderText(i.language); err != nil {
+// DrawBorderWithText draws the separation indications of DrawInvoiceWithBorder:
+// a solid black border on top of the QR invoice as well as between the
+// receipt part and the payment part, and a text above the border indicating
+// that the payment part should be detached from the rest of the paper. It is
+// assumed that the current point is at the lower left corner of the invoice
+// area. Use it together with DrawInvoice on pages that are laid out by the
+// caller.
+func DrawBorderWithText(canvas *pdf.Canvas, language Language) error {
+	if err := checkLanguage(language); err != nil {
 		return err
-	} else {
-		text := new(pdf.Text)
-		text.UseFont(i.textFont, 6, 7)
-		text.Text(sep)
-		i.canvas.Translate(10.5*pdf.Cm-text.X()/2.0, 10.5*pdf.Cm+3)
-		i.canvas.DrawText(text)
 	}
-	return nil
+	return drawBorderWithText(NewPDFRenderer(canvas), language, 0)
 }
 
-// drawSeparatorWithScissors draws a solid black border between the receipt
-// part and the payment part of the QR invoice. A scissors symbol is drawn next
-// to the separator line. It is assumed that the current point is at the lower
-// left corner of the invoice area.
-func (i *pdfInvoice) drawSeparatorWithScissors() error {
-	i.canvas.Push()
-	defer i.canvas.Pop()
-	path := new(pdf.Path)
-	path.Move(pdf.Point{6.2 * pdf.Cm, 0})
-	path.Line(pdf.Point{6.2 * pdf.Cm, 10.5 * pdf.Cm})
-	i.canvas.SetStrokeColor(0, 0, 0)
-	i.canvas.SetLineWidth(1.0)
-	i.canvas.Stroke(path)
-	doc := i.canvas.Document()
-	dingbats, err := doc.AddFont(pdf.ZapfDingbats, pdf.StandardEncoding)
-	if err != nil {
-		return err
+// DrawSeparatorWithScissors draws the separation indications of
+// DrawInvoiceWithScissors: a solid black line between the receipt part and
+// the payment part of the QR invoice, with a scissors symbol next to it. It
+// is assumed that the current point is at the lower left corner of the
+// invoice area. Use it together with DrawInvoice on pages that are laid out
+// by the caller.
+func DrawSeparatorWithScissors(canvas *pdf.Canvas) error {
+	return drawSeparatorWithScissors(NewPDFRenderer(canvas), 0)
+}
+
+// DrawAmountBox draws the corner marks of the empty amount box with at as
+// its lower left corner: 30×10 mm for the receipt and 40×15 mm for the
+// payment part, as given by the style guide. Use it for stationery on which
+// the rest of the invoice is pre-printed.
+func DrawAmountBox(canvas *pdf.Canvas, at pdf.Point, receipt bool) {
+	size := DefaultLayout().PaymentAmountBox
+	if receipt {
+		size = DefaultLayout().ReceiptAmountBox
 	}
-	rotated := new(pdf.Text)
-	rotated.UseFont(dingbats, 20, 25)
-	rotated.Text("✂")
-	i.canvas.Rotate(math.Pi / 2.0)
-	i.canvas.Translate(5.0*pdf.Cm, -6.2*pdf.Cm)
-	i.canvas.DrawText(rotated)
-	return nil
+	r := NewPDFRenderer(canvas)
+	r.Push()
+	defer r.Pop()
+	r.SetGrey(0)
+	r.SetLineWidth(0.75)
+	x, y := float64(at.X), float64(at.Y)
+	path := new(Path)
+	corners(path, x, y, x+size.Width.points(), y+size.Height.points())
+	r.Stroke(path)
 }
 
 // drawSupportLines draws a grid on canvas to help positioning elements.
@@ -202,269 +193,129 @@ func drawSupportLines(canvas *pdf.Canvas) {
 	canvas.Stroke(path)
 }
 
-type pdfInvoice struct {
-	canvas    *pdf.Canvas
-	titleFont *pdf.Font
-	textFont  *pdf.Font
-	data      Payload
-	language  string
+// pdfRenderer is the Renderer for a gopdf canvas.
+type pdfRenderer struct {
+	canvas *pdf.Canvas
+	fonts  map[FontStyle]*pdf.Font
+	font   pdfFont
+	saved  []pdfFont // Fonts saved by Push.
 }
 
-type layoutOptions struct {
-	headerSize pdf.Unit
-	textSize   pdf.Unit
-	leading    pdf.Unit
-	topLeft    pdf.Point
-	maxHeight  pdf.Unit
-	maxWidth   pdf.Unit
-	boxSize    pdf.Point
+type pdfFont struct {
+	font *pdf.Font
+	size pdf.Unit
 }
 
-func setupForNewInvoice(canvas *pdf.Canvas, data Payload, language string) (*pdfInvoice, error) {
-	canvas.SetColor(0, 0, 0)
-	canvas.SetStrokeColor(0, 0, 0)
-	canvas.SetLineWidth(0.75)
-	invoice := &pdfInvoice{
-		canvas:   canvas,
-		data:     data,
-		language: language,
-	}
-	doc := canvas.Document()
-	if f, err := doc.AddFont(pdf.Helvetica, pdf.WinAnsiEncoding); err != nil {
-		return nil, err
-	} else {
-		invoice.textFont = f
-	}
-	if f, err := doc.AddFont(pdf.HelveticaBold, pdf.WinAnsiEncoding); err != nil {
-		return nil, err
-	} else {
-		invoice.titleFont = f
-	}
-	return invoice, nil
+// NewPDFRenderer returns a Renderer that draws on the given canvas, with the
+// current position of the canvas as origin. Text is set in Helvetica.
+func NewPDFRenderer(canvas *pdf.Canvas) Renderer {
+	return &pdfRenderer{canvas: canvas, fonts: make(map[FontStyle]*pdf.Font)}
 }
 
-func (i *pdfInvoice) drawReceiptPart() error {
-	if title, err := TitleSection(i.data, i.language); err != nil {
-		return err
-	} else {
-		i.canvas.Push()
-		i.canvas.Translate(0.5*pdf.Cm, 10.0*pdf.Cm-11) // Font size 11.
-		text := new(pdf.Text)
-		text.UseFont(i.titleFont, 11, 13)
-		text.Text(title.Receipt)
-		i.canvas.DrawText(text)
-		i.canvas.Pop()
-	}
-
-	if amt, err := AmountSection(i.data, i.language); err != nil {
-		return err
-	} else {
-		i.drawAmount(amt, layoutOptions{
-			headerSize: 6,
-			textSize:   8,
-			leading:    9,
-			topLeft:    pdf.Point{0.5 * pdf.Cm, 3.7 * pdf.Cm},
-			maxHeight:  1.4 * pdf.Cm,
-			maxWidth:   5.2 * pdf.Cm,
-			boxSize:    pdf.Point{3.0 * pdf.Cm, 1.0 * pdf.Cm},
-		})
-	}
-
-	if info, err := InformationSection(i.data, i.language,
-		5.2*28.35/8.0, // 5.2cm × 28.35 pt/cm ÷ 8pt font size.
-		receiptPartInformation); err != nil {
-		return err
-	} else {
-		err = i.drawParagraphs(info, layoutOptions{
-			headerSize: 6,
-			textSize:   8,
-			leading:    9,
-			topLeft:    pdf.Point{0.5 * pdf.Cm, 9.3 * pdf.Cm},
-			maxHeight:  5.6 * pdf.Cm,
-			boxSize:    pdf.Point{5.2 * pdf.Cm, 2.0 * pdf.Cm},
-		})
-		if err != nil {
-			return err
-		}
-	}
+func (r *pdfRenderer) Push() {
+	r.canvas.Push()
+	r.saved = append(r.saved, r.font)
+}
 
-	i.canvas.Push()
-	text := new(pdf.Text)
-	text.UseFont(i.titleFont, 6, 9)
-	text.Text(headings[acceptancePoint][i.language])
-	i.canvas.Translate(5.7*pdf.Cm-text.X(), 2.3*pdf.Cm-6)
-	i.canvas.DrawText(text)
-	i.canvas.Pop()
+func (r *pdfRenderer) Pop() {
+	r.canvas.Pop()
+	r.font = r.saved[len(r.saved)-1]
+	r.saved = r.saved[:len(r.saved)-1]
+}
 
-	return nil
+func (r *pdfRenderer) Translate(x, y float64) {
+	r.canvas.Translate(pdf.Unit(x), pdf.Unit(y))
 }
 
-func (i *pdfInvoice) drawPaymentPart() error {
-	if title, err := TitleSection(i.data, i.language); err != nil {
-		return err
-	} else {
-		i.canvas.Push()
-		i.canvas.Translate(6.7*pdf.Cm, 10.0*pdf.Cm-11) // Font size 11.
-		text := new(pdf.Text)
-		text.UseFont(i.titleFont, 11, 13)
-		text.Text(title.PaymentPart)
-		i.canvas.DrawText(text)
-		i.canvas.Pop()
-	}
+func (r *pdfRenderer) Rotate(angle float64) {
+	r.canvas.Rotate(float32(angle))
+}
 
-	if amt, err := AmountSection(i.data, i.language); err != nil {
-		return err
-	} else {
-		i.drawAmount(amt, layoutOptions{
-			headerSize: 8,
-			textSize:   10,
-			leading:    11,
-			topLeft:    pdf.Point{6.7 * pdf.Cm, 3.7 * pdf.Cm},
-			maxHeight:  2.2 * pdf.Cm,
-			maxWidth:   5.1 * pdf.Cm,
-			boxSize:    pdf.Point{4.0 * pdf.Cm, 1.5 * pdf.Cm},
-		})
-	}
+func (r *pdfRenderer) SetGrey(grey float64) {
+	g := float32(grey)
+	r.canvas.SetColor(g, g, g)
+	r.canvas.SetStrokeColor(g, g, g)
+}
 
-	if qrImage, err := CreateQR(i.data); err != nil {
-		return err
-	} else {
-		// 46×46 mm image; at least 5 mm margin.
-		// Payment part starts at 61.5 mm indent.
-		i.canvas.DrawImage(qrImage, pdf.Rectangle{
-			pdf.Point{6.7 * pdf.Cm, 4.3 * pdf.Cm},
-			pdf.Point{11.3 * pdf.Cm, 8.9 * pdf.Cm}})
-	}
+func (r *pdfRenderer) SetLineWidth(width float64) {
+	r.canvas.SetLineWidth(pdf.Unit(width))
+}
 
-	if section, err := InformationSection(i.data, i.language,
-		8.5*28.35/10.0, // 8.5cm × 28.35 pt/cm ÷ 10pt font size.
-		paymentPart); err != nil {
-		return err
-	} else {
-		err = i.drawParagraphs(section, layoutOptions{
-			headerSize: 8,
-			textSize:   10,
-			leading:    11,
-			topLeft:    pdf.Point{11.9 * pdf.Cm, 10.0 * pdf.Cm},
-			maxHeight:  8.5 * pdf.Cm,
-			boxSize:    pdf.Point{6.5 * pdf.Cm, 2.5 * pdf.Cm},
-		})
-		if err != nil {
+func (r *pdfRenderer) SetFont(style FontStyle, size float64) error {
+	font, ok := r.fonts[style]
+	if !ok {
+		name := pdf.Helvetica
+		if style == BoldFont {
+			name = pdf.HelveticaBold
+		}
+		var err error
+		if font, err = r.canvas.Document().AddFont(name, pdf.WinAnsiEncoding); err != nil {
 			return err
 		}
+		r.fonts[style] = font
 	}
-
-	i.canvas.Push()
-	text := new(pdf.Text)
-	for _, ap := range i.data.AlternativeProcedureParameters {
-		text.UseFont(i.titleFont, 7, 8)
-		text.Text(ap.Label + ": ")
-		text.UseFont(i.textFont, 7, 8)
-		// 13cm × 28.35 pt/cm ÷ 7pt font size is total width.
-		remainingWidth := (13.8*28.35 - text.X()) / 7.0
-		text.Text(shortenToWidth(ap.Procedure, float64(remainingWidth)))
-		text.NextLine()
-	}
-	i.canvas.Translate(6.7*pdf.Cm, 1.5*pdf.Cm-8)
-	i.canvas.DrawText(text)
-	i.canvas.Pop()
-
+	r.font = pdfFont{font, pdf.Unit(size)}
 	return nil
 }
 
-// drawAmount draws a payment amount, or an empty box if there is no amount.
-func (i *pdfInvoice) drawAmount(amt AmountSectionData, layout layoutOptions) error {
-	i.canvas.Push()
-	defer i.canvas.Pop()
-	i.canvas.Translate(layout.topLeft.X, layout.topLeft.Y-layout.headerSize)
-	text := new(pdf.Text)
-	text.UseFont(i.titleFont, layout.headerSize, layout.leading)
-	text.Text(amt.CurrencyHeading)
-	columnSeparation := text.X() + layout.headerSize
-	text.NextLineOffset(columnSeparation, 0)
-	text.Text(amt.AmountHeading)
-	totalHeaderWidth := text.X()
-	text.NextLineOffset(-columnSeparation, -layout.leading)
-	text.UseFont(i.textFont, layout.textSize, layout.leading)
-	text.Text(amt.CurrencyValue)
-	if amt.AmountValue != "" {
-		text.NextLineOffset(columnSeparation, 0)
-		text.Text(amt.AmountValue)
-	} else {
-		topLeft := pdf.Point{layout.maxWidth - layout.boxSize.X, layout.headerSize}
-		if totalHeaderWidth+layout.boxSize.X > layout.maxWidth {
-			topLeft.Y = text.Y() + layout.leading - 5
-		}
-		path := new(pdf.Path)
-		drawCorners(path, pdf.Rectangle{
-			Min: pdf.Point{topLeft.X, topLeft.Y - layout.boxSize.Y},
-			Max: pdf.Point{topLeft.X + layout.boxSize.X, topLeft.Y},
-		})
-		i.canvas.Stroke(path)
+func (r *pdfRenderer) FontName(style FontStyle) string {
+	if style == BoldFont {
+		return "Helvetica-Bold"
 	}
-	i.canvas.DrawText(text)
-	return nil
+	return "Helvetica"
 }
 
-// drawParagraphs draws a slice of paragraphs following the layout options.
-// If a paragraph is empty, a box is drawn instead.
-func (i *pdfInvoice) drawParagraphs(section []Paragraph, layout layoutOptions) error {
-	i.canvas.Push()
-	defer i.canvas.Pop()
-	i.canvas.Translate(layout.topLeft.X, layout.topLeft.Y-layout.headerSize)
+// newText returns a text object containing s in the current font.
+func (r *pdfRenderer) newText(s string) *pdf.Text {
 	text := new(pdf.Text)
-	firstLine := true
-	for _, s := range section {
-		if firstLine {
-			firstLine = false
-		} else {
-			text.NextLine()
-			text.NextLineOffset(0, -3)
-		}
-		text.UseFont(i.titleFont, layout.headerSize, layout.leading)
-		text.Text(s.Heading)
-		if len(s.Lines) > 0 {
-			text.UseFont(i.textFont, layout.textSize, layout.leading)
-			for _, line := range s.Lines {
-				text.NextLine()
-				text.Text(line)
-			}
-		} else {
-			path := new(pdf.Path)
-			drawCorners(path, pdf.Rectangle{
-				Min: pdf.Point{0, text.Y() - 5 - layout.boxSize.Y},
-				Max: pdf.Point{layout.boxSize.X, text.Y() - 5},
-			})
-			i.canvas.Stroke(path)
-			text.NextLineOffset(0, -layout.boxSize.Y-3)
-		}
-	}
+	text.UseFont(r.font.font, r.font.size, r.font.size)
+	text.Text(s)
+	return text
+}
 
-	if -text.Y() > layout.maxHeight { // -text.Y() is the text height.
-		return errors.New("Invoice text height too large.")
-	}
-	i.canvas.DrawText(text)
-	return nil
+func (r *pdfRenderer) TextWidth(s string) float64 {
+	return float64(r.newText(s).X())
+}
+
+func (r *pdfRenderer) Text(x, y float64, s string) {
+	r.canvas.Push()
+	defer r.canvas.Pop()
+	r.canvas.Translate(pdf.Unit(x), pdf.Unit(y))
+	r.canvas.DrawText(r.newText(s))
+}
+
+func (r *pdfRenderer) Stroke(path *Path) {
+	r.canvas.Stroke(pdfPath(path))
+}
+
+func (r *pdfRenderer) Fill(path *Path) {
+	r.canvas.Fill(pdfPath(path))
+}
+
+func (r *pdfRenderer) Image(img image.Image, minX, minY, maxX, maxY float64) {
+	r.canvas.DrawImage(img, pdf.Rectangle{
+		Min: pdf.Point{X: pdf.Unit(minX), Y: pdf.Unit(minY)},
+		Max: pdf.Point{X: pdf.Unit(maxX), Y: pdf.Unit(maxY)},
+	})
 }
 
-// drawCorners draws corner marks around the given box.
-func drawCorners(path *pdf.Path, box pdf.Rectangle) {
-	// According to the standard, the corner marks should be 3 mm long.
-	size := 0.3 * pdf.Cm
-	// Lower left corner
-	path.Move(pdf.Point{box.Min.X + size, box.Min.Y})
-	path.Line(pdf.Point{box.Min.X, box.Min.Y})
-	path.Line(pdf.Point{box.Min.X, box.Min.Y + size})
-	// Upper left corner
-	path.Move(pdf.Point{box.Min.X, box.Max.Y - size})
-	path.Line(pdf.Point{box.Min.X, box.Max.Y})
-	path.Line(pdf.Point{box.Min.X + size, box.Max.Y})
-	// Upper right corner
-	path.Move(pdf.Point{box.Max.X - size, box.Max.Y})
-	path.Line(pdf.Point{box.Max.X, box.Max.Y})
-	path.Line(pdf.Point{box.Max.X, box.Max.Y - size})
-	// Lower right corner
-	path.Move(pdf.Point{box.Max.X, box.Min.Y + size})
-	path.Line(pdf.Point{box.Max.X, box.Min.Y})
-	path.Line(pdf.Point{box.Max.X - size, box.Min.Y})
+// pdfPath converts a path to a gopdf path.
+func pdfPath(path *Path) *pdf.Path {
+	p := new(pdf.Path)
+	pt := func(point PathPoint) pdf.Point {
+		return pdf.Point{X: pdf.Unit(point.X), Y: pdf.Unit(point.Y)}
+	}
+	for _, segment := range path.Segments {
+		switch segment.Op {
+		case MoveTo:
+			p.Move(pt(segment.Points[0]))
+		case LineTo:
+			p.Line(pt(segment.Points[0]))
+		case CurveTo:
+			p.Curve(pt(segment.Points[0]), pt(segment.Points[1]), pt(segment.Points[2]))
+		case ClosePath:
+			p.Close()
+		}
+	}
+	return p
 }
@@ -12,10 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build !(js && wasm)
+
 package swissqr
 
 import (
 	"io/ioutil"
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/krepost/gopdf/pdf"
@@ -53,3 +57,171 @@ func TestExample2FromStandard(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestPreview(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm)
+	if err := DrawInvoicePreview(canvas, examplePayload2, "it"); err != nil {
+		t.Error(err)
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSeparators(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	if err := DrawInvoice(canvas, examplePayload1, "it"); err != nil {
+		t.Error(err)
+	}
+	if err := DrawBorderWithText(canvas, "it"); err != nil {
+		t.Error(err)
+	}
+	if err := DrawSeparatorWithScissors(canvas); err != nil {
+		t.Error(err)
+	}
+	if err := DrawBorderWithText(canvas, "xx"); err == nil {
+		t.Error("Expected error due to unsupported language")
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDrawInvoiceWithOptions(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	opts := RenderOptions{
+		Language:  "fr",
+		Separator: ScissorsSeparator,
+		Draft:     true,
+		Layout:    Layout{OffsetY: 10},
+	}
+	if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err != nil {
+		t.Error(err)
+	}
+	opts.Layout.ReceiptAmountBox = BoxSize{Width: 60, Height: 10}
+	if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err == nil {
+		t.Error("Expected error due to invalid layout")
+	}
+	opts = RenderOptions{Language: "fr_CH", FallbackLanguage: "sv"}
+	if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err == nil {
+		t.Error("Expected error due to unsupported fallback language")
+	}
+	opts.FallbackLanguage = ""
+	if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err != nil {
+		t.Error(err)
+	}
+	opts.FallbackLanguage = "fr"
+	if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err != nil {
+		t.Error(err)
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDrawInvoiceAt(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(29.7*pdf.Cm, 21.0*pdf.Cm)
+	if err := DrawInvoiceAt(canvas, pdf.Point{X: 8.7 * pdf.Cm, Y: 2 * pdf.Cm}, examplePayload1, "it"); err != nil {
+		t.Error(err)
+	}
+	if err := DrawInvoiceAt(canvas, pdf.Point{}, examplePayload1, "xx"); err != nil {
+		t.Error(err)
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPrerenderedQR(t *testing.T) {
+	img, err := CreateQR(examplePayload1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := pdf.New()
+	opts := RenderOptions{Language: "de", QRImage: img}
+	for i := 0; i < 3; i++ {
+		canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm)
+		if err := DrawInvoiceWithOptions(canvas, examplePayload1, opts); err != nil {
+			t.Error(err)
+		}
+		canvas.Close()
+	}
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+
+	// The image of another payload is rejected.
+	canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm)
+	if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err == nil {
+		t.Error("Expected error due to QR image of another payload")
+	}
+	canvas.Close()
+	small, err := encodeQR("SPC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkQRImage(small, examplePayload1); err == nil {
+		t.Error("Expected error due to QR image of another text")
+	}
+
+	// Functions rendering several payloads reject the image.
+	payloads := []Payload{examplePayload1, examplePayload1}
+	if err := RenderNUp(ioutil.Discard, payloads, 2, opts); err != errQRImageForSeveral {
+		t.Errorf("Expected error due to several payloads, got: %v", err)
+	}
+	err = RenderSeq(slices.Values(payloads), ioutil.Discard, SeqOptions{RenderOptions: opts})
+	if err != errQRImageForSeveral {
+		t.Errorf("Expected error due to several payloads, got: %v", err)
+	}
+	if err := GeneratePDF(ioutil.Discard, examplePayload1, DE, WithRenderOptions(opts)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestVectorQR(t *testing.T) {
+	doc := pdf.New()
+	for _, opts := range []RenderOptions{
+		{Language: "de", VectorQR: true},
+		{Language: "de", VectorQR: true, Draft: true},
+	} {
+		canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm)
+		if err := DrawInvoiceWithOptions(canvas, examplePayload2, opts); err != nil {
+			t.Error(err)
+		}
+		canvas.Close()
+	}
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDrawScissors(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	for _, angle := range []float64{0, math.Pi / 2, -math.Pi / 2} {
+		DrawScissors(canvas, pdf.Point{10 * pdf.Cm, 10 * pdf.Cm}, 1*pdf.Cm, angle)
+	}
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDrawAmountBox(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm)
+	DrawAmountBox(canvas, pdf.Point{2.7 * pdf.Cm, 1.5 * pdf.Cm}, true)
+	DrawAmountBox(canvas, pdf.Point{16.5 * pdf.Cm, 1.5 * pdf.Cm}, false)
+	canvas.Close()
+	if err := doc.Encode(ioutil.Discard); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import "github.com/krepost/gopdf/pdf"
+
+// The PDF functions of this package take and return the types of the gopdf
+// library at the import path “github.com/krepost/gopdf/pdf”. Types of the
+// same library imported under another path, such as an older
+// “bitbucket.org/krepost/gopdf/pdf”, are distinct types to the compiler and
+// cannot be passed to this package. The aliases below always denote the
+// types used by this package, so that code that only creates documents and
+// draws invoices does not have to import the PDF library at all.
+type (
+	// Document is a PDF document; create one with NewDocument.
+	Document = pdf.Document
+
+	// Canvas is a page of a Document on which invoices are drawn.
+	Canvas = pdf.Canvas
+
+	// Unit is a length in PDF units, i.e., in points.
+	Unit = pdf.Unit
+
+	// Point is a position on a Canvas.
+	Point = pdf.Point
+
+	// Rectangle is a rectangle on a Canvas.
+	Rectangle = pdf.Rectangle
+)
+
+// Lengths in PDF units.
+const (
+	Pt   = pdf.Pt
+	Cm   = pdf.Cm
+	Inch = pdf.Inch
+)
+
+// NewDocument creates a new, empty PDF document. Add pages with NewPage,
+// e.g. doc.NewPage(21.0*swissqr.Cm, 29.7*swissqr.Cm) for an A4 page.
+func NewDocument() *Document {
+	return pdf.New()
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+// Package pdfstamp adds the Swiss QR invoice to an existing PDF, e.g. an
+// invoice created by an accounting system, using pdfcpu,
+// github.com/pdfcpu/pdfcpu. The payment part is drawn onto the bottom 105 mm
+// of the last page, which must be left blank for it.
+package pdfstamp
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/krepost/swissqr"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// stampDescription places the stamp unscaled at the lower left corner of
+// the page.
+const stampDescription = "pos:bl, off:0 0, sc:1 abs, rot:0"
+
+// Stamp reads the PDF from in and writes it to out with the invoice drawn
+// onto the bottom of its last page, as DrawInvoiceWithOptions draws it. The
+// page should be 210 mm wide, i.e. A4 in portrait orientation.
+func Stamp(in io.ReadSeeker, out io.Writer, data swissqr.Payload, opts swissqr.RenderOptions) error {
+	stamp, err := InvoicePDF(data, opts)
+	if err != nil {
+		return err
+	}
+	wm, err := api.PDFWatermarkForReadSeeker(bytes.NewReader(stamp), stampDescription, true, false, types.POINTS)
+	if err != nil {
+		return err
+	}
+	return api.AddWatermarks(in, out, []string{"l"}, wm, model.NewDefaultConfiguration())
+}
+
+// InvoicePDF returns a PDF with one page of 210×105 mm showing the invoice.
+func InvoicePDF(data swissqr.Payload, opts swissqr.RenderOptions) ([]byte, error) {
+	doc := swissqr.NewDocument()
+	canvas := doc.NewPage(21.0*swissqr.Cm, 10.5*swissqr.Cm)
+	if err := swissqr.DrawInvoiceWithOptions(canvas, data, opts); err != nil {
+		return nil, err
+	}
+	if err := canvas.Close(); err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := doc.Encode(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
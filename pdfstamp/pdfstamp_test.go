@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package pdfstamp_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/krepost/swissqr"
+	"github.com/krepost/swissqr/pdfstamp"
+)
+
+func TestStamp(t *testing.T) {
+	account, err := swissqr.ParseIBAN("CH3709000000304442225")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := swissqr.Payload{
+		Account: swissqr.AccountNumber{IBAN: account},
+		Creditor: swissqr.Entity{
+			Name:        "Salvation Army Foundation Switzerland",
+			Address:     swissqr.CombinedAddress{AddressLine2: "3000 Bern"},
+			CountryCode: "CH",
+		},
+		CurrencyAmount: swissqr.PaymentAmount{Currency: swissqr.CHF},
+	}
+
+	// The invoice of the accounting system.
+	doc := swissqr.NewDocument()
+	for i := 0; i < 2; i++ {
+		canvas := doc.NewPage(21.0*swissqr.Cm, 29.7*swissqr.Cm)
+		if err := canvas.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var invoice bytes.Buffer
+	if err := doc.Encode(&invoice); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := swissqr.RenderOptions{Language: "de", Separator: swissqr.ScissorsSeparator}
+	if err := pdfstamp.Stamp(bytes.NewReader(invoice.Bytes()), &out, data, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF")) {
+		t.Errorf("Expected PDF, got: %q", out.String())
+	}
+
+	out.Reset()
+	if err := pdfstamp.Stamp(bytes.NewReader(invoice.Bytes()), &out, swissqr.Payload{}, opts); err == nil {
+		t.Error("Expected error for empty payload")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package postcode checks Swiss and Liechtenstein post codes against town
+// names, so that typos in addresses are found before bills are sent. The
+// data is not part of the package; download the post code directory of
+// Swiss Post (“PLZ Verzeichnis”) or the official directory of localities
+// of swisstopo (“Amtliches Ortschaftenverzeichnis”) in CSV format and load
+// it with Load.
+package postcode
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/krepost/swissqr"
+)
+
+// Directory maps post codes to the names of their towns.
+type Directory struct {
+	towns map[string][]string
+}
+
+// Column headers of the supported directories: Swiss Post uses
+// POSTLEITZAHL with the town names ORTBEZ18 and ORTBEZ27, swisstopo uses
+// PLZ and Ortschaftsname.
+var (
+	postCodeColumns = []string{"POSTLEITZAHL", "PLZ"}
+	townColumns     = []string{"ORTBEZ18", "ORTBEZ27", "Ortschaftsname"}
+)
+
+// Load reads a post code directory in CSV format, with fields separated by
+// “;” and a header row naming the columns; other columns are ignored. A post
+// code may occur in several rows, each adding the names of a town.
+func Load(r io.Reader) (*Directory, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Could not read post code directory header: %v", err)
+	}
+	postCodeIndex, townIndexes := -1, []int(nil)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for _, column := range postCodeColumns {
+			if strings.EqualFold(name, column) && postCodeIndex < 0 {
+				postCodeIndex = i
+			}
+		}
+		for _, column := range townColumns {
+			if strings.EqualFold(name, column) {
+				townIndexes = append(townIndexes, i)
+			}
+		}
+	}
+	if postCodeIndex < 0 || len(townIndexes) == 0 {
+		return nil, fmt.Errorf("Post code directory must have a post code and a town column: %v", strings.Join(header, ";"))
+	}
+	d := &Directory{towns: make(map[string][]string)}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+		if postCodeIndex >= len(record) {
+			continue
+		}
+		postCode := strings.TrimSpace(record[postCodeIndex])
+		if postCode == "" {
+			continue
+		}
+		for _, i := range townIndexes {
+			if i < len(record) {
+				d.add(postCode, strings.TrimSpace(record[i]))
+			}
+		}
+	}
+	return d, nil
+}
+
+// add adds a town to a post code, unless it is empty or known already.
+func (d *Directory) add(postCode, town string) {
+	if town == "" {
+		return
+	}
+	for _, known := range d.towns[postCode] {
+		if known == town {
+			return
+		}
+	}
+	d.towns[postCode] = append(d.towns[postCode], town)
+}
+
+// Towns returns the names of the towns with the given post code, or nil if
+// the post code is unknown.
+func (d *Directory) Towns(postCode string) []string {
+	return d.towns[strings.TrimSpace(postCode)]
+}
+
+// Len returns the number of post codes in the directory.
+func (d *Directory) Len() int {
+	return len(d.towns)
+}
+
+// Match reports whether town is a name of a town with the given post code.
+// Case, spacing and the name in another language, such as “Biel” for
+// “Biel/Bienne”, are tolerated.
+func (d *Directory) Match(postCode, town string) bool {
+	town = normalizeTown(town)
+	for _, known := range d.Towns(postCode) {
+		if normalizeTown(known) == town {
+			return true
+		}
+		for _, name := range strings.Split(known, "/") {
+			if normalizeTown(name) == town {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeTown folds case and spacing of a town name.
+func normalizeTown(town string) string {
+	return strings.ToLower(strings.Join(strings.Fields(town), " "))
+}
+
+// Check returns warnings for an entity in Switzerland or Liechtenstein
+// whose post code is unknown or does not match its town. Entities in other
+// countries and combined addresses that cannot be split are not checked.
+func (d *Directory) Check(e swissqr.Entity) []string {
+	country := swissqr.NormalizeCountryCode(e.CountryCode)
+	if country != "CH" && country != "LI" {
+		return nil
+	}
+	var sa swissqr.StructuredAddress
+	switch a := e.Address.(type) {
+	case swissqr.StructuredAddress:
+		sa = a
+	case swissqr.CombinedAddress:
+		var err error
+		if sa, err = a.ToStructured(country); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	towns := d.Towns(sa.PostCode)
+	if towns == nil {
+		return []string{fmt.Sprintf("Unknown post code: %v", sa.PostCode)}
+	}
+	if !d.Match(sa.PostCode, sa.TownName) {
+		return []string{fmt.Sprintf("Town %v does not match post code %v: %v",
+			sa.TownName, sa.PostCode, strings.Join(towns, ", "))}
+	}
+	return nil
+}
+
+// CheckPayload checks the creditor and the ultimate debtor of a payload
+// with Check. The warnings are prefixed with the party, e.g. “Creditor: ”.
+func (d *Directory) CheckPayload(p swissqr.Payload) []string {
+	var warnings []string
+	for _, w := range d.Check(p.Creditor) {
+		warnings = append(warnings, "Creditor: "+w)
+	}
+	for _, w := range d.Check(p.UltimateDebtor) {
+		warnings = append(warnings, "UltimateDebtor: "+w)
+	}
+	return warnings
+}
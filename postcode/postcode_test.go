@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postcode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/krepost/swissqr"
+)
+
+const postExample = "\ufeffREC_ART;ONRP;BFSNR;PLZ_TYP;POSTLEITZAHL;PLZ_ZZ;GPLZ;ORTBEZ18;ORTBEZ27;KANTON;SPRACHCODE\n" +
+	"01;100;351;20;3000;00;3000;Bern;Bern;BE;1\n" +
+	"01;200;371;10;2501;00;2500;Biel/Bienne;Biel/Bienne;BE;1\n" +
+	"01;300;261;20;8001;00;8000;Zürich;Zürich;ZH;1\n" +
+	"01;400;7001;20;9490;00;9490;Vaduz;Vaduz;FL;1\n" +
+	"01;500;6621;20;1201;00;1200;Genève;Genève;GE;2\n"
+
+const swisstopoExample = "Ortschaftsname;PLZ;Zusatzziffer;Gemeindename;BFS-Nr;Kantonskürzel;E;N;Sprache\n" +
+	"Rorschach;9400;0;Rorschach;3215;SG;2754469;1260398;de\n" +
+	"Rorschacherberg;9404;0;Rorschacherberg;3216;SG;2753000;1259000;de\n"
+
+func TestLoad(t *testing.T) {
+	d, err := Load(strings.NewReader(postExample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d.Len() != 5 {
+		t.Errorf("Expected 5 post codes, got %d", d.Len())
+	}
+	if towns := d.Towns("2501"); !reflect.DeepEqual(towns, []string{"Biel/Bienne"}) {
+		t.Errorf("Unexpected towns: %v", towns)
+	}
+	d, err = Load(strings.NewReader(swisstopoExample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if towns := d.Towns(" 9400 "); !reflect.DeepEqual(towns, []string{"Rorschach"}) {
+		t.Errorf("Unexpected towns: %v", towns)
+	}
+	if _, err := Load(strings.NewReader("Name;Town\nA;B\n")); err == nil {
+		t.Error("Expected error for missing columns")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	d, err := Load(strings.NewReader(postExample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entity := func(postCode, town, country string) swissqr.Entity {
+		return swissqr.Entity{
+			Name:        "Robert Schneider AG",
+			Address:     swissqr.StructuredAddress{PostCode: postCode, TownName: town},
+			CountryCode: country,
+		}
+	}
+	var testdata = []struct {
+		entity   swissqr.Entity
+		warnings []string
+	}{
+		{entity("3000", "Bern", "CH"), nil},
+		{entity("2501", "biel", "CH"), nil},
+		{entity("2501", "Bienne", "CH"), nil},
+		{entity("9490", "Vaduz", "LI"), nil},
+		{entity("8001", "Zurich", "CH"), []string{"Town Zurich does not match post code 8001: Zürich"}},
+		{entity("3001", "Bern", "CH"), []string{"Unknown post code: 3001"}},
+		{entity("80331", "München", "DE"), nil},
+		{swissqr.Entity{Name: "Pia", Address: swissqr.CombinedAddress{AddressLine2: "1201 Genf"}, CountryCode: "CH"},
+			[]string{"Town Genf does not match post code 1201: Genève"}},
+		{swissqr.Entity{}, nil},
+	}
+	for i, item := range testdata {
+		if warnings := d.Check(item.entity); !reflect.DeepEqual(warnings, item.warnings) {
+			t.Errorf("Item %v: expected %q, got: %q", i, item.warnings, warnings)
+		}
+	}
+
+	p := swissqr.Payload{Creditor: entity("3000", "Bern", "CH"), UltimateDebtor: entity("3001", "Bern", "CH")}
+	if warnings := d.CheckPayload(p); !reflect.DeepEqual(warnings, []string{"UltimateDebtor: Unknown post code: 3001"}) {
+		t.Errorf("Unexpected warnings: %q", warnings)
+	}
+}
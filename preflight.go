@@ -0,0 +1,331 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/math/f64"
+)
+
+// Proof describes how the invoices of a generated document are printed.
+// Generators fill in a Proof while drawing, so that Preflight can check the
+// print requirements of the style guide before the document is encoded.
+type Proof struct {
+	Invoices []ProofInvoice
+}
+
+// ProofInvoice describes one invoice in a document.
+type ProofInvoice struct {
+	// Page is the zero-based page number of the invoice.
+	Page int
+
+	// PageWidth and PageHeight give the size of the page.
+	PageWidth  Millimeter
+	PageHeight Millimeter
+
+	// X and Y give the position of the lower left corner of the invoice
+	// on the page.
+	X Millimeter
+	Y Millimeter
+
+	// QRSize is the side length of the QR code and QuietZone the free
+	// margin around it.
+	QRSize    Millimeter
+	QuietZone Millimeter
+
+	// Grey is the colour of the invoice; 0 is black.
+	Grey float32
+
+	// Fonts lists the PostScript names of the fonts used.
+	Fonts []string
+}
+
+// RenderInvoiceWithProof draws an invoice with r like RenderInvoice and
+// describes the drawn invoice for Preflight. The origin of r is at x, y on
+// the given page of the given size. The size of the QR code, its quiet zone
+// within the payment part and the colour are measured from the calls to r,
+// with lines measured along their centre; the fonts are only reported if r
+// implements FontNamer.
+func RenderInvoiceWithProof(r Renderer, data Payload, opts RenderOptions,
+	page int, pageWidth, pageHeight, x, y Millimeter) (ProofInvoice, error) {
+	recorder := &proofRenderer{Renderer: r}
+	recorder.state.transform = f64.Aff3{1, 0, 0, 0, 1, 0}
+	if err := RenderInvoice(recorder, data, opts); err != nil {
+		return ProofInvoice{}, err
+	}
+	p := ProofInvoice{
+		Page:       page,
+		PageWidth:  pageWidth,
+		PageHeight: pageHeight,
+		X:          x + opts.Layout.OffsetX,
+		Y:          y + opts.Layout.OffsetY,
+		Grey:       float32(recorder.grey),
+		Fonts:      recorder.fonts,
+	}
+	qr := recorder.qrCode()
+	if qr < 0 {
+		return p, nil
+	}
+	code := recorder.boxes[qr]
+	p.QRSize = millimeters(math.Min(code.maxX-code.minX, code.maxY-code.minY))
+	// The quiet zone ends at the edge of the payment part at the latest.
+	// Marks on the separation line, like the scissors symbol, lie on the
+	// edge and are not counted.
+	left, bottom := opts.Layout.OffsetX.points()+6.2*PointsPerCm, opts.Layout.OffsetY.points()
+	right, top := left+14.8*PointsPerCm, bottom+10.5*PointsPerCm
+	quietZone := math.Min(math.Min(code.minX-left, right-code.maxX),
+		math.Min(code.minY-bottom, top-code.maxY))
+	for i, b := range recorder.boxes {
+		if i != qr && !b.inside(code) && (b.minX >= left || b.maxX <= left) {
+			quietZone = math.Min(quietZone, code.distance(b))
+		}
+	}
+	p.QuietZone = millimeters(math.Max(quietZone, 0))
+	return p, nil
+}
+
+// FontNamer is implemented by renderers that report the PostScript names
+// of their fonts, e.g. “Helvetica-Bold”.
+type FontNamer interface {
+	FontName(style FontStyle) string
+}
+
+// millimeters converts points to millimeters, rounded to 0.01 mm so that
+// distances given in centimetres by the layout are measured exactly.
+func millimeters(points float64) Millimeter {
+	return Millimeter(math.Round(points/PointsPerCm*1000) / 100)
+}
+
+// proofRenderer passes all calls on to a Renderer and records the area,
+// the colour and the fonts of the drawn elements.
+type proofRenderer struct {
+	Renderer
+	state proofState
+	saved []proofState // States saved by Push.
+	boxes []proofBox   // Areas of the drawn elements.
+	areas []bool       // Whether each box is filled or an image.
+	grey  float64      // Darkest grey that is not white; 0 is black.
+	fonts []string
+}
+
+type proofState struct {
+	transform f64.Aff3
+	grey      float64
+	style     FontStyle
+	size      float64
+}
+
+// proofBox is the bounding box of an element, in points of the page.
+type proofBox struct {
+	minX, minY, maxX, maxY float64
+}
+
+func (r *proofRenderer) Push() {
+	r.Renderer.Push()
+	r.saved = append(r.saved, r.state)
+}
+
+func (r *proofRenderer) Pop() {
+	r.Renderer.Pop()
+	r.state = r.saved[len(r.saved)-1]
+	r.saved = r.saved[:len(r.saved)-1]
+}
+
+func (r *proofRenderer) Translate(x, y float64) {
+	r.Renderer.Translate(x, y)
+	m := &r.state.transform
+	m[2] += m[0]*x + m[1]*y
+	m[5] += m[3]*x + m[4]*y
+}
+
+func (r *proofRenderer) Rotate(angle float64) {
+	r.Renderer.Rotate(angle)
+	sin, cos := math.Sincos(angle)
+	m := r.state.transform
+	r.state.transform = f64.Aff3{
+		m[0]*cos + m[1]*sin, m[1]*cos - m[0]*sin, m[2],
+		m[3]*cos + m[4]*sin, m[4]*cos - m[3]*sin, m[5],
+	}
+}
+
+func (r *proofRenderer) SetGrey(grey float64) {
+	r.Renderer.SetGrey(grey)
+	r.state.grey = grey
+}
+
+func (r *proofRenderer) SetFont(style FontStyle, size float64) error {
+	if err := r.Renderer.SetFont(style, size); err != nil {
+		return err
+	}
+	r.state.style = style
+	r.state.size = size
+	return nil
+}
+
+func (r *proofRenderer) Text(x, y float64, s string) {
+	r.Renderer.Text(x, y, s)
+	// The ascender and descender of Helvetica.
+	size := r.state.size
+	r.record(false, x, y-0.207*size, x+r.Renderer.TextWidth(s), y+0.718*size)
+	if namer, ok := r.Renderer.(FontNamer); ok {
+		name := namer.FontName(r.state.style)
+		if !slices.Contains(r.fonts, name) {
+			r.fonts = append(r.fonts, name)
+		}
+	}
+}
+
+func (r *proofRenderer) Stroke(path *Path) {
+	r.Renderer.Stroke(path)
+	r.recordPath(false, path)
+}
+
+func (r *proofRenderer) Fill(path *Path) {
+	r.Renderer.Fill(path)
+	r.recordPath(true, path)
+}
+
+func (r *proofRenderer) Image(img image.Image, minX, minY, maxX, maxY float64) {
+	r.Renderer.Image(img, minX, minY, maxX, maxY)
+	r.record(true, minX, minY, maxX, maxY)
+}
+
+// recordPath records the bounding box of all points of path.
+func (r *proofRenderer) recordPath(area bool, path *Path) {
+	b := proofBox{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, segment := range path.Segments {
+		for _, p := range segment.Points {
+			b = proofBox{math.Min(b.minX, p.X), math.Min(b.minY, p.Y),
+				math.Max(b.maxX, p.X), math.Max(b.maxY, p.Y)}
+		}
+	}
+	if b.minX <= b.maxX {
+		r.record(area, b.minX, b.minY, b.maxX, b.maxY)
+	}
+}
+
+// record records an element drawn in the current colour, with the
+// rectangle from minX, minY to maxX, maxY transformed to the page.
+func (r *proofRenderer) record(area bool, minX, minY, maxX, maxY float64) {
+	if r.state.grey >= 1 {
+		// White knocks out other elements, like the border of the
+		// Swiss cross.
+		return
+	}
+	r.grey = math.Max(r.grey, r.state.grey)
+	m := r.state.transform
+	b := proofBox{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range [][2]float64{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}} {
+		x := m[0]*p[0] + m[1]*p[1] + m[2]
+		y := m[3]*p[0] + m[4]*p[1] + m[5]
+		b = proofBox{math.Min(b.minX, x), math.Min(b.minY, y),
+			math.Max(b.maxX, x), math.Max(b.maxY, y)}
+	}
+	r.boxes = append(r.boxes, b)
+	r.areas = append(r.areas, area)
+}
+
+// qrCode returns the index of the box of the QR code, which is the largest
+// filled area or image, or -1 if nothing was filled.
+func (r *proofRenderer) qrCode() int {
+	qr := -1
+	for i, b := range r.boxes {
+		if r.areas[i] && (qr < 0 || b.area() > r.boxes[qr].area()) {
+			qr = i
+		}
+	}
+	return qr
+}
+
+func (b proofBox) area() float64 {
+	return (b.maxX - b.minX) * (b.maxY - b.minY)
+}
+
+// inside reports whether b lies within c.
+func (b proofBox) inside(c proofBox) bool {
+	return b.minX >= c.minX && b.maxX <= c.maxX && b.minY >= c.minY && b.maxY <= c.maxY
+}
+
+// distance returns the width of the margin between b and c, which is zero
+// if they overlap.
+func (b proofBox) distance(c proofBox) float64 {
+	dx := math.Max(0, math.Max(c.minX-b.maxX, b.minX-c.maxX))
+	dy := math.Max(0, math.Max(c.minY-b.maxY, b.minY-c.maxY))
+	return math.Max(dx, dy)
+}
+
+// allowedFonts contains the fonts permitted by the style guide.
+var allowedFonts = map[string]bool{
+	"Helvetica":           true,
+	"Helvetica-Bold":      true,
+	"Arial":               true,
+	"Arial-Bold":          true,
+	"Frutiger":            true,
+	"Frutiger-Bold":       true,
+	"LiberationSans":      true,
+	"LiberationSans-Bold": true,
+}
+
+// PreflightError lists all violations found by Preflight.
+type PreflightError struct {
+	Violations []string
+}
+
+func (e *PreflightError) Error() string {
+	return "Preflight failed: " + strings.Join(e.Violations, "; ")
+}
+
+// Preflight checks the print requirements of the style guide for all
+// invoices of a document, similar to the print checks of the validation
+// portal: the QR code is at least 46×46 mm with a quiet zone of at least
+// 5 mm, the invoice is printed in black with permitted fonts only, and
+// the invoice is placed at the bottom of the page across its full width.
+// It returns a *PreflightError listing all violations, or nil.
+func Preflight(doc Proof) error {
+	var violations []string
+	for _, inv := range doc.Invoices {
+		report := func(format string, a ...interface{}) {
+			violations = append(violations,
+				fmt.Sprintf("Page %d: ", inv.Page)+fmt.Sprintf(format, a...))
+		}
+		if inv.QRSize < 46 {
+			report("QR code smaller than 46 mm: %v mm", inv.QRSize)
+		}
+		if inv.QuietZone < 5 {
+			report("Quiet zone smaller than 5 mm: %v mm", inv.QuietZone)
+		}
+		if inv.Grey != 0 {
+			report("Invoice not printed in black")
+		}
+		for _, font := range inv.Fonts {
+			if !allowedFonts[font] {
+				report("Font not permitted: %v", font)
+			}
+		}
+		if inv.Y != 0 || inv.X != 0 || inv.PageWidth != 210 {
+			report("Invoice not at bottom of page: %v×%v mm at %v, %v mm",
+				inv.PageWidth, inv.PageHeight, inv.X, inv.Y)
+		}
+	}
+	if len(violations) > 0 {
+		return &PreflightError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+func TestRenderInvoiceWithProof(t *testing.T) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 29.7*pdf.Cm)
+	defer canvas.Close()
+	opts := RenderOptions{Language: "de", Separator: ScissorsSeparator}
+	p, err := RenderInvoiceWithProof(NewPDFRenderer(canvas), examplePayload3, opts, 0, 210, 297, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ProofInvoice{PageWidth: 210, PageHeight: 297, QRSize: 46, QuietZone: 5,
+		Fonts: []string{"Helvetica-Bold", "Helvetica"}}
+	if !reflect.DeepEqual(expected, p) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, p)
+	}
+	if err := Preflight(Proof{Invoices: []ProofInvoice{p}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreflight(t *testing.T) {
+	good := ProofInvoice{PageWidth: 210, PageHeight: 297, QRSize: 46, QuietZone: 5,
+		Fonts: []string{"Helvetica", "Helvetica-Bold"}}
+	if err := Preflight(Proof{Invoices: []ProofInvoice{good}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	bad := good
+	bad.Page = 1
+	bad.QRSize = 40
+	bad.QuietZone = 2
+	bad.Grey = 0.5
+	bad.Fonts = []string{"Helvetica", "Times-Roman"}
+	bad.X, bad.Y = 0, 10
+	err := Preflight(Proof{Invoices: []ProofInvoice{good, bad}})
+	perr, ok := err.(*PreflightError)
+	if !ok {
+		t.Fatalf("Expected preflight error, got: %v", err)
+	}
+	expected := []string{
+		"Page 1: QR code smaller than 46 mm: 40 mm",
+		"Page 1: Quiet zone smaller than 5 mm: 2 mm",
+		"Page 1: Invoice not printed in black",
+		"Page 1: Font not permitted: Times-Roman",
+		"Page 1: Invoice not at bottom of page: 210×297 mm at 0, 10 mm",
+	}
+	if !reflect.DeepEqual(expected, perr.Violations) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, perr.Violations)
+	}
+}
+
+// timesRenderer is a renderer with a font not permitted by the style guide.
+type timesRenderer struct {
+	recordingRenderer
+}
+
+func (r *timesRenderer) FontName(style FontStyle) string {
+	return "Times-Roman"
+}
+
+func TestRenderInvoiceWithProofViolations(t *testing.T) {
+	opts := RenderOptions{Language: "en", Draft: true, VectorQR: true, Layout: Layout{OffsetY: 5}}
+	p, err := RenderInvoiceWithProof(new(timesRenderer), examplePayload2, opts, 2, 210, 297, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Preflight(Proof{Invoices: []ProofInvoice{p}})
+	perr, ok := err.(*PreflightError)
+	if !ok {
+		t.Fatalf("Expected preflight error, got: %v", err)
+	}
+	// The draft banner runs across the QR code.
+	expected := []string{
+		"Page 2: Quiet zone smaller than 5 mm: 0 mm",
+		"Page 2: Invoice not printed in black",
+		"Page 2: Font not permitted: Times-Roman",
+		"Page 2: Invoice not at bottom of page: 210×297 mm at 0, 5 mm",
+	}
+	if !reflect.DeepEqual(expected, perr.Violations) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, perr.Violations)
+	}
+	if p.QRSize != 46 {
+		t.Errorf("Expected 46 mm QR code, got: %v mm", p.QRSize)
+	}
+}
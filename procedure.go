@@ -0,0 +1,82 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ProcedureValidator checks the parameters of an alternative procedure of
+// one scheme, beyond the length and character set checked for all schemes.
+type ProcedureValidator func(procedure string) error
+
+var (
+	procedureValidatorsMu sync.RWMutex
+	procedureValidators   = map[string]ProcedureValidator{
+		"ebill": validateEBillProcedure,
+		"twint": validateTWINTProcedure,
+	}
+)
+
+// RegisterProcedureValidator sets the validator used by
+// AlternativeProcedures.Validate for the procedures of a scheme. The scheme
+// is the first parameter of the procedure, up to the first “/” or “;”, e.g.
+// “eBill” for “eBill/B/peter@sample.ch”; it is matched without regard to
+// case. Validators for eBill and TWINT are registered by default;
+// registering a scheme again replaces its validator, and a nil validator
+// removes it. The registration is global: it applies to the validation of
+// all payloads afterwards in any goroutine, so call it during
+// initialization.
+func RegisterProcedureValidator(scheme string, v ProcedureValidator) {
+	procedureValidatorsMu.Lock()
+	defer procedureValidatorsMu.Unlock()
+	if v == nil {
+		delete(procedureValidators, strings.ToLower(scheme))
+		return
+	}
+	procedureValidators[strings.ToLower(scheme)] = v
+}
+
+// procedureScheme returns the scheme of a procedure.
+func procedureScheme(procedure string) string {
+	if i := strings.IndexAny(procedure, "/;"); i >= 0 {
+		procedure = procedure[:i]
+	}
+	return strings.ToLower(procedure)
+}
+
+// validateProcedure runs the validator registered for the scheme of the
+// procedure, if any.
+func validateProcedure(procedure string) error {
+	procedureValidatorsMu.RLock()
+	v := procedureValidators[procedureScheme(procedure)]
+	procedureValidatorsMu.RUnlock()
+	if v == nil {
+		return nil
+	}
+	return v(procedure)
+}
+
+// validateTWINTProcedure checks the structure of a TWINT procedure, which
+// starts with “twint/light/” followed by the TWINT data.
+func validateTWINTProcedure(procedure string) error {
+	parameters := strings.SplitN(procedure, "/", 3)
+	if len(parameters) != 3 || parameters[1] != "light" || parameters[2] == "" {
+		return fmt.Errorf("TWINT procedure must be twint/light/data: %v", procedure)
+	}
+	return nil
+}
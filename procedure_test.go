@@ -0,0 +1,55 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProcedureValidators(t *testing.T) {
+	RegisterProcedureValidator("XY", func(procedure string) error {
+		if !strings.HasPrefix(procedure, "XY;XYService;") {
+			return errors.New("Unknown XY service")
+		}
+		return nil
+	})
+	defer RegisterProcedureValidator("XY", nil)
+	for i, test := range []struct {
+		procedure string
+		expected  string
+	}{
+		{"eBill/B/peter@sample.ch", ""},
+		{"EBILL/R/41010560425610173/10201409", ""},
+		{"eBill/B", "eBill procedure must be"},
+		{"eBill/X/peter@sample.ch", "Unknown eBill type: X"},
+		{"eBill/B/peter@sample.ch/10201409", "Referenced bill only allowed for reminders"},
+		{"eBill/B/CHE12345678", "eBill enterprise ID must be a UID"},
+		{"twint/light/02:6f9e5c#c3b1a0#", ""},
+		{"twint/heavy/02", "TWINT procedure must be"},
+		{"XY;XYService;54321", ""},
+		{"XY;Other;54321", "Unknown XY service"},
+		{"UV;UltraPay005;12345", ""},
+	} {
+		err := AlternativeProcedures{{Label: "Name", Procedure: test.procedure}}.Validate()
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("Item %v: expected no error, got: %v", i, err)
+		case test.expected != "" && (err == nil || !strings.HasPrefix(err.Error(), test.expected)):
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReferencePrinter is a payment reference in the Number field of a
+// PaymentReference: a *QRReference, a *CreditorReference, or another type
+// with the same methods, such as the types of github.com/krepost/structref,
+// whose digital format is a valid QR reference or creditor reference.
+type ReferencePrinter interface {
+	// PrintFormat returns the reference in groups, as printed on the
+	// invoice.
+	PrintFormat() string
+
+	// DigitalFormat returns the reference without spaces, as encoded in
+	// the QR code.
+	DigitalFormat() string
+}
+
+// QRReference is a 27-digit QR reference, whose last digit is a check digit
+// computed with the recursive modulo 10 algorithm.
+type QRReference struct {
+	digits string
+}
+
+// ParseQRReference parses a QR reference; spaces are ignored.
+func ParseQRReference(s string) (*QRReference, error) {
+	if err := ValidateQRReference(s); err != nil {
+		return nil, err
+	}
+	return &QRReference{digits: strings.ReplaceAll(s, " ", "")}, nil
+}
+
+// ParseQRReferenceOrDie is like ParseQRReference but panics on error.
+// Useful when initializing a Payload struct programmatically.
+func ParseQRReferenceOrDie(s string) *QRReference {
+	ref, err := ParseQRReference(s)
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
+
+// DigitalFormat returns the 27 digits of the reference.
+func (r *QRReference) DigitalFormat() string {
+	return r.digits
+}
+
+// PrintFormat returns the reference in groups of five digits from the
+// right, e.g. “21 00000 00003 13947 14300 09017”.
+func (r *QRReference) PrintFormat() string {
+	groups := []string{r.digits[:2]}
+	for i := 2; i < len(r.digits); i += 5 {
+		groups = append(groups, r.digits[i:i+5])
+	}
+	return strings.Join(groups, " ")
+}
+
+// CreditorReference is a creditor reference according to ISO 11649: “RF”,
+// two check digits and up to 21 letters and digits.
+type CreditorReference struct {
+	code string
+}
+
+// ParseCreditorReference parses a creditor reference; spaces are ignored
+// and letters may be lower case.
+func ParseCreditorReference(s string) (*CreditorReference, error) {
+	code := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(code) < 5 || len(code) > 25 || !strings.HasPrefix(code, "RF") {
+		return nil, fmt.Errorf("Creditor reference must be RF with 3 to 23 characters: %v", s)
+	}
+	for _, r := range code {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return nil, fmt.Errorf("Creditor reference may only contain letters A-Z and digits 0-9: %v", s)
+		}
+	}
+	if !isDigits(code[2:4]) || mod97(code[4:]+code[:4]) != 1 {
+		return nil, fmt.Errorf("Invalid check digits of creditor reference: %v", s)
+	}
+	return &CreditorReference{code: code}, nil
+}
+
+// ParseCreditorReferenceOrDie is like ParseCreditorReference but panics on
+// error. Useful when initializing a Payload struct programmatically.
+func ParseCreditorReferenceOrDie(s string) *CreditorReference {
+	ref, err := ParseCreditorReference(s)
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
+
+// DigitalFormat returns the reference without spaces.
+func (r *CreditorReference) DigitalFormat() string {
+	return r.code
+}
+
+// PrintFormat returns the reference in groups of four characters, e.g.
+// “RF18 5390 0754 7034”.
+func (r *CreditorReference) PrintFormat() string {
+	var groups []string
+	for i := 0; i < len(r.code); i += 4 {
+		groups = append(groups, r.code[i:min(i+4, len(r.code))])
+	}
+	return strings.Join(groups, " ")
+}
+
+// Type returns the reference type encoded in the QR code: “QRR” for a QR
+// reference, “SCOR” for a creditor reference and “NON” without reference.
+// Other reference printers are classified by their digital format; an
+// empty string means that the reference is neither.
+func (pr PaymentReference) Type() string {
+	switch ref := pr.Number.(type) {
+	case nil:
+		return "NON"
+	case *QRReference:
+		return "QRR"
+	case *CreditorReference:
+		return "SCOR"
+	default:
+		if _, err := ParseQRReference(ref.DigitalFormat()); err == nil {
+			return "QRR"
+		}
+		if _, err := ParseCreditorReference(ref.DigitalFormat()); err == nil {
+			return "SCOR"
+		}
+		return ""
+	}
+}
+
+// NewQRReference builds a QR reference from up to 26 digits. The digits are
+// padded with leading zeros and the check digit (modulo 10, recursive) is
+// appended, giving the 27-digit reference required for a QR-IBAN.
+func NewQRReference(digits string) (PaymentReference, error) {
+	digits = strings.ReplaceAll(digits, " ", "")
+	if len(digits) > 26 {
+		return PaymentReference{}, fmt.Errorf("Maximum 26 digits allowed for QR reference: %v", digits)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return PaymentReference{}, fmt.Errorf("QR reference may only contain digits 0-9: %v", digits)
+		}
+	}
+	digits = strings.Repeat("0", 26-len(digits)) + digits
+	return PaymentReference{Number: &QRReference{digits: fmt.Sprintf("%v%d", digits, qrReferenceCheckDigit(digits))}}, nil
+}
+
+// qrReferenceCheckDigit computes the check digit of a QR reference using
+// the recursive modulo 10 algorithm. It is assumed that digits only
+// contains the characters 0-9.
+func qrReferenceCheckDigit(digits string) int {
+	carry := 0
+	for _, r := range digits {
+		carry = mod10Table[(carry+int(r-'0'))%10]
+	}
+	return (10 - carry) % 10
+}
+
+var mod10Table = [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+
+// NewCreditorReference builds a creditor reference according to ISO 11649
+// from up to 21 letters and digits. Spaces are ignored and letters are
+// converted to upper case; the check digits are computed and the reference
+// “RFxx…” is returned.
+func NewCreditorReference(base string) (PaymentReference, error) {
+	base = strings.ToUpper(strings.ReplaceAll(base, " ", ""))
+	if base == "" || len(base) > 21 {
+		return PaymentReference{}, fmt.Errorf("Creditor reference must have 1 to 21 characters: %v", base)
+	}
+	for _, r := range base {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return PaymentReference{}, fmt.Errorf("Creditor reference may only contain letters A-Z and digits 0-9: %v", base)
+		}
+	}
+	check := 98 - mod97(base+"RF00")
+	return PaymentReference{Number: &CreditorReference{code: fmt.Sprintf("RF%02d%v", check, base)}}, nil
+}
+
+// mod97 computes the remainder modulo 97 of s with letters replaced by
+// numbers, A by 10 to Z by 35, as for IBANs and creditor references. It is
+// assumed that s only contains the characters 0-9 and A-Z.
+func mod97(s string) int {
+	remainder := 0
+	for _, r := range s {
+		if r >= 'A' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+// CheckDigitError is returned by ValidateQRReference for a QR reference
+// whose check digit is wrong.
+type CheckDigitError struct {
+	Reference string
+	Expected  int
+	Actual    int
+}
+
+func (e *CheckDigitError) Error() string {
+	return fmt.Sprintf("Invalid check digit %d of QR reference, expected %d: %v", e.Actual, e.Expected, e.Reference)
+}
+
+// ValidateQRReference verifies that s, with spaces ignored, is a 27-digit QR
+// reference with the correct check digit, without building a payload. If
+// only the check digit is wrong, the error is a *CheckDigitError giving the
+// expected digit.
+func ValidateQRReference(s string) error {
+	digits := strings.ReplaceAll(s, " ", "")
+	if len(digits) != 27 {
+		return fmt.Errorf("QR reference must have 27 digits: %v", s)
+	}
+	if !isDigits(digits) {
+		return fmt.Errorf("QR reference may only contain digits 0-9: %v", s)
+	}
+	expected, actual := qrReferenceCheckDigit(digits[:26]), int(digits[26]-'0')
+	if expected != actual {
+		return &CheckDigitError{Reference: s, Expected: expected, Actual: actual}
+	}
+	return nil
+}
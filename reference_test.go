@@ -0,0 +1,156 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewQRReference(t *testing.T) {
+	var testdata = []struct {
+		digits   string
+		expected string
+		message  string
+	}{
+		{"21000000000313947143000901", "210000000003139471430009017", ""},
+		{"21 00000 00003 13947 14300 0901", "210000000003139471430009017", ""},
+		{"3139471430009", "000000000000031394714300098", ""},
+		{"", "000000000000000000000000000", ""},
+		{"123456789012345678901234567", "", "Maximum 26 digits"},
+		{"RF123", "", "only contain digits"},
+	}
+	for i, data := range testdata {
+		ref, err := NewQRReference(data.digits)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			} else if actual := ref.Number.DigitalFormat(); actual != data.expected {
+				t.Errorf("Item %v: expected %v, got %v", i, data.expected, actual)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
+
+func TestValidateQRReference(t *testing.T) {
+	var testdata = []struct {
+		reference string
+		message   string
+	}{
+		{"210000000003139471430009017", ""},
+		{"21 00000 00003 13947 14300 09017", ""},
+		{"210000000003139471430009013", "Invalid check digit 3 of QR reference, expected 7"},
+		{"21000000000313947143000901", "QR reference must have 27 digits"},
+		{"21000000000313947143000901X", "QR reference may only contain digits 0-9"},
+	}
+	for i, data := range testdata {
+		err := ValidateQRReference(data.reference)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+	var checkDigitError *CheckDigitError
+	if err := ValidateQRReference("210000000003139471430009013"); !errors.As(err, &checkDigitError) || checkDigitError.Expected != 7 {
+		t.Errorf("Expected CheckDigitError with expected digit 7, got: %v", err)
+	}
+}
+
+func TestNewCreditorReference(t *testing.T) {
+	var testdata = []struct {
+		base     string
+		expected string
+		message  string
+	}{
+		{"539007547034", "RF18539007547034", ""},
+		{"5390 0754 7034", "RF18539007547034", ""},
+		{"a", "RF25A", ""},
+		{"", "", "Creditor reference must have 1 to 21 characters"},
+		{"1234567890123456789012", "", "Creditor reference must have 1 to 21 characters"},
+		{"ABC-123", "", "Creditor reference may only contain letters A-Z and digits 0-9"},
+	}
+	for i, data := range testdata {
+		ref, err := NewCreditorReference(data.base)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			} else if actual := ref.Number.DigitalFormat(); actual != data.expected {
+				t.Errorf("Item %v: expected %v, got %v", i, data.expected, actual)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
+
+// printer is a reference printer of another package.
+type printer string
+
+func (p printer) DigitalFormat() string { return string(p) }
+func (p printer) PrintFormat() string   { return string(p) }
+
+func TestReferenceTypes(t *testing.T) {
+	var testdata = []struct {
+		ref         ReferencePrinter
+		typ         string
+		printFormat string
+	}{
+		{nil, "NON", ""},
+		{ParseQRReferenceOrDie("210000000003139471430009017"), "QRR", "21 00000 00003 13947 14300 09017"},
+		{ParseCreditorReferenceOrDie("rf18 5390 0754 7034"), "SCOR", "RF18 5390 0754 7034"},
+		{ParseCreditorReferenceOrDie("RF8312345678912345678912"), "SCOR", "RF83 1234 5678 9123 4567 8912"},
+		{printer("210000000003139471430009017"), "QRR", "210000000003139471430009017"},
+		{printer("RF18539007547034"), "SCOR", "RF18539007547034"},
+		{printer("RF19539007547034"), "", "RF19539007547034"},
+	}
+	for i, data := range testdata {
+		pr := PaymentReference{Number: data.ref}
+		if actual := pr.Type(); actual != data.typ {
+			t.Errorf("Item %v: expected type %#v, got %#v", i, data.typ, actual)
+		}
+		if data.ref != nil && data.ref.PrintFormat() != data.printFormat {
+			t.Errorf("Item %v: expected %#v, got %#v", i, data.printFormat, data.ref.PrintFormat())
+		}
+	}
+}
+
+func TestParseCreditorReference(t *testing.T) {
+	var testdata = []struct {
+		reference string
+		message   string
+	}{
+		{"RF18 5390 0754 7034", ""},
+		{"RF1", "Creditor reference must be RF with 3 to 23 characters"},
+		{"XY18539007547034", "Creditor reference must be RF with 3 to 23 characters"},
+		{"RF18-5390", "Creditor reference may only contain letters A-Z and digits 0-9"},
+		{"RF19539007547034", "Invalid check digits of creditor reference"},
+	}
+	for i, data := range testdata {
+		_, err := ParseCreditorReference(data.reference)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDuplicateReference is returned by a ReferenceRegistry for a reference
+// that has been registered before.
+var ErrDuplicateReference = errors.New("Duplicate payment reference")
+
+// ReferenceRegistry remembers the payment references of all generated
+// invoices, so that a reference that is issued twice is detected before
+// the invoice is sent. Implementations must be safe for concurrent use.
+type ReferenceRegistry interface {
+	// Register records reference, given in digital format. It returns an
+	// error wrapping ErrDuplicateReference if reference is known already.
+	Register(ctx context.Context, reference string) error
+
+	// Release forgets a reference recorded by Register, e.g. because
+	// the invoice could not be written after all. Releasing an unknown
+	// reference is not an error.
+	Release(ctx context.Context, reference string) error
+}
+
+// RegisterReference registers the payment reference of the payload, if it
+// has one.
+func RegisterReference(ctx context.Context, r ReferenceRegistry, data Payload) error {
+	ref := data.Reference.Number
+	if ref == nil {
+		return nil
+	}
+	return r.Register(ctx, ref.DigitalFormat())
+}
+
+// ReleaseReference releases the payment reference of the payload, if it has
+// one.
+func ReleaseReference(ctx context.Context, r ReferenceRegistry, data Payload) error {
+	ref := data.Reference.Number
+	if ref == nil {
+		return nil
+	}
+	return r.Release(ctx, ref.DigitalFormat())
+}
+
+// MemoryRegistry keeps references in memory. It detects duplicates within
+// one run only; use a FileRegistry or an SQLRegistry to detect duplicates
+// across runs.
+type MemoryRegistry struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// Register records reference in memory.
+func (mr *MemoryRegistry) Register(ctx context.Context, reference string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	if mr.seen[reference] {
+		return fmt.Errorf("%w: %v", ErrDuplicateReference, reference)
+	}
+	if mr.seen == nil {
+		mr.seen = make(map[string]bool)
+	}
+	mr.seen[reference] = true
+	return nil
+}
+
+// Release removes reference from memory.
+func (mr *MemoryRegistry) Release(ctx context.Context, reference string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	delete(mr.seen, reference)
+	return nil
+}
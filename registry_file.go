@@ -0,0 +1,95 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FileRegistry keeps references in a text file, one reference per line.
+// Like FileNumbering, concurrent processes are serialized by a lock file
+// next to the registry file.
+type FileRegistry struct {
+	// Path of the registry file. The lock file is Path + “.lock”.
+	Path string
+
+	mu sync.Mutex
+}
+
+// Register records reference in the file.
+func (fr *FileRegistry) Register(ctx context.Context, reference string) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	unlock, err := lockFile(ctx, fr.Path+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(fr.Path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == reference {
+			return fmt.Errorf("%w: %v", ErrDuplicateReference, reference)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if _, err := f.WriteString(reference + "\n"); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Release removes reference from the file.
+func (fr *FileRegistry) Release(ctx context.Context, reference string) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	unlock, err := lockFile(ctx, fr.Path+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	b, err := os.ReadFile(fr.Path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var kept strings.Builder
+	for _, line := range strings.SplitAfter(string(b), "\n") {
+		if line != "" && strings.TrimSpace(line) != reference {
+			kept.WriteString(line)
+		}
+	}
+	// The file is replaced at once, so that a crash leaves either the old
+	// or the new list.
+	tmp := fr.Path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(kept.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fr.Path)
+}
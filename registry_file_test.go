@@ -0,0 +1,60 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileRegistry(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "references")
+	first := &FileRegistry{Path: path}
+	for _, ref := range []string{"210000000003139471430009017", "RF18539007547034"} {
+		if err := first.Register(ctx, ref); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	// A second run sees the references of the first.
+	second := &FileRegistry{Path: path}
+	err := second.Register(ctx, "RF18539007547034")
+	if !errors.Is(err, ErrDuplicateReference) {
+		t.Errorf("Expected duplicate reference, got: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "210000000003139471430009017\nRF18539007547034\n"; string(b) != expected {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, string(b))
+	}
+
+	// A released reference can be registered again.
+	if err := second.Release(ctx, "210000000003139471430009017"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := first.Register(ctx, "210000000003139471430009017"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "RF18539007547034\n210000000003139471430009017\n" {
+		t.Errorf("Unexpected registry file: %#v", string(b))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SQLRegistry keeps references in a database table. The default statements
+// expect a table created like this:
+//
+//	CREATE TABLE swissqr_references (reference VARCHAR(35) PRIMARY KEY);
+type SQLRegistry struct {
+	DB *sql.DB
+
+	// Insert adds the reference. It must fail if the reference exists
+	// already, e.g. due to the primary key. Default uses “?” placeholders.
+	Insert string
+
+	// Delete removes the reference. Default uses “?” placeholders.
+	Delete string
+
+	// IsDuplicate reports whether an error of Insert is due to an existing
+	// reference. Default recognizes the messages of unique key violations
+	// of common databases, such as SQLite, PostgreSQL and MySQL.
+	IsDuplicate func(err error) bool
+}
+
+const (
+	defaultInsertReferenceStatement = "INSERT INTO swissqr_references (reference) VALUES (?)"
+	defaultDeleteReferenceStatement = "DELETE FROM swissqr_references WHERE reference = ?"
+)
+
+// Register records reference in the database with a single insert, so that
+// concurrent jobs cannot both register the same reference.
+func (sr SQLRegistry) Register(ctx context.Context, reference string) error {
+	if sr.DB == nil {
+		return errors.New("No database specified.")
+	}
+	insert, isDuplicate := sr.Insert, sr.IsDuplicate
+	if insert == "" {
+		insert = defaultInsertReferenceStatement
+	}
+	if isDuplicate == nil {
+		isDuplicate = isUniqueViolation
+	}
+	_, err := sr.DB.ExecContext(ctx, insert, reference)
+	if err != nil && isDuplicate(err) {
+		return fmt.Errorf("%w: %v", ErrDuplicateReference, reference)
+	}
+	return err
+}
+
+// isUniqueViolation recognizes unique key violations by the messages of
+// common databases.
+func isUniqueViolation(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, s := range []string{"unique constraint", "duplicate key", "duplicate entry"} {
+		if strings.Contains(message, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Release removes reference from the database.
+func (sr SQLRegistry) Release(ctx context.Context, reference string) error {
+	if sr.DB == nil {
+		return errors.New("No database specified.")
+	}
+	statement := sr.Delete
+	if statement == "" {
+		statement = defaultDeleteReferenceStatement
+	}
+	_, err := sr.DB.ExecContext(ctx, statement, reference)
+	return err
+}
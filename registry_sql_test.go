@@ -0,0 +1,52 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSQLRegistry(t *testing.T) {
+	registry := SQLRegistry{DB: new(fakeDB).open()}
+	ctx := context.Background()
+	if err := registry.Register(ctx, "RF18539007547034"); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(ctx, "RF18539007547034"); !errors.Is(err, ErrDuplicateReference) {
+		t.Errorf("Expected duplicate reference, got: %v", err)
+	}
+	if err := registry.Release(ctx, "RF18539007547034"); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(ctx, "RF18539007547034"); err != nil {
+		t.Errorf("Expected released reference to be registered again, got: %v", err)
+	}
+	// Other errors are passed on.
+	registry.Insert = "INSERT INTO invoices (reference) VALUES (?)"
+	if err := registry.Register(ctx, "RF18539007547034"); err == nil || errors.Is(err, ErrDuplicateReference) {
+		t.Errorf("Expected error due to unknown statement, got: %v", err)
+	}
+}
+
+func TestSQLRegistryWithoutDatabase(t *testing.T) {
+	err := SQLRegistry{}.Register(context.Background(), "RF18539007547034")
+	if err == nil || err.Error() != "No database specified." {
+		t.Errorf("Expected error due to missing database, got: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var exampleCreditorReference = func() Payload {
+	p := examplePayload3
+	p.Reference = PaymentReference{ParseCreditorReferenceOrDie("RF18539007547034")}
+	return p
+}()
+
+func TestMemoryRegistry(t *testing.T) {
+	ctx := context.Background()
+	registry := new(MemoryRegistry)
+	if err := RegisterReference(ctx, registry, examplePayload2); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := RegisterReference(ctx, registry, exampleCreditorReference); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	err := RegisterReference(ctx, registry, examplePayload2)
+	if !errors.Is(err, ErrDuplicateReference) {
+		t.Errorf("Expected duplicate reference, got: %v", err)
+	}
+	// Payloads without reference are never duplicates.
+	for i := 0; i < 2; i++ {
+		if err := RegisterReference(ctx, registry, examplePayload3); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	// A released reference can be registered again.
+	if err := ReleaseReference(ctx, registry, examplePayload2); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := RegisterReference(ctx, registry, examplePayload2); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,359 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Size of the invoice in points.
+const (
+	invoiceWidth  = 21.0 * PointsPerCm
+	invoiceHeight = 10.5 * PointsPerCm
+)
+
+// RenderImage draws the invoice like DrawInvoice into an image of 210×105 mm
+// at the given resolution in dots per inch, e.g. for previews in a web
+// browser; encode the image with image/png. The positions and line breaks
+// are the same as in the PDF, but the text is set in the Go fonts, whose
+// glyphs are slightly different from Helvetica. The image is meant for
+// display only; print the PDF for paying.
+func RenderImage(data Payload, language Language, dpi int) (image.Image, error) {
+	return RenderImageWithOptions(data, RenderOptions{Language: language}, dpi)
+}
+
+// RenderImageWithOptions draws the invoice like RenderImage as selected by
+// the options, e.g. a draft for review. The text above the border of
+// BorderSeparator lies outside of the image and is not drawn.
+func RenderImageWithOptions(data Payload, opts RenderOptions, dpi int) (image.Image, error) {
+	if dpi <= 0 {
+		return nil, fmt.Errorf("Resolution must be positive: %d dpi", dpi)
+	}
+	scale := float64(dpi) / 72.0
+	bounds := image.Rect(0, 0, roundPixels(invoiceWidth*scale), roundPixels(invoiceHeight*scale))
+	img := image.NewRGBA(bounds)
+	xdraw.Draw(img, bounds, image.White, image.Point{}, xdraw.Src)
+	r := &imageRenderer{
+		img:    img,
+		dpi:    float64(dpi),
+		scale:  scale,
+		height: invoiceHeight,
+		faces:  make(map[faceKey]font.Face),
+	}
+	r.state.transform = f64.Aff3{1, 0, 0, 0, 1, 0}
+	r.state.lineWidth = 1
+	if err := RenderInvoice(r, data, opts); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// referenceDPI is the resolution of RenderReferenceBitmap.
+const referenceDPI = 72
+
+// RenderReferenceBitmap draws the payment part of the invoice as a black and
+// white image of 148×105 mm at 72 dpi, for golden-file comparisons in the
+// test suites of applications that print invoices: store the PNG encoded
+// bitmap and compare it with the output of a new release of this package to
+// detect changes of the layout. Every pixel is either black or white, so that
+// small differences of anti-aliasing do not change the bitmap; it is
+// identical from run to run and only changes with the layout or the fonts.
+func RenderReferenceBitmap(p Payload, language Language) (image.Image, error) {
+	img, err := RenderImageWithOptions(p, RenderOptions{Language: language}, referenceDPI)
+	if err != nil {
+		return nil, err
+	}
+	scale := referenceDPI / 72.0
+	bounds := img.Bounds()
+	bounds.Min.X = roundPixels(6.2 * PointsPerCm * scale)
+	bitmap := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			grey := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			if grey.Y >= 0x80 {
+				bitmap.SetGray(x-bounds.Min.X, y-bounds.Min.Y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	return bitmap, nil
+}
+
+// imageRenderer is the Renderer for an image.
+type imageRenderer struct {
+	img    *image.RGBA
+	dpi    float64
+	scale  float64 // Pixels per point.
+	height float64 // Height of the image in points.
+	faces  map[faceKey]font.Face
+	state  imageState
+	saved  []imageState // States saved by Push.
+}
+
+type imageState struct {
+	// transform maps the coordinates of the renderer to the coordinates
+	// of the invoice, in points with the y axis pointing upwards.
+	transform f64.Aff3
+	grey      float64
+	lineWidth float64
+	face      font.Face
+}
+
+type faceKey struct {
+	style FontStyle
+	size  float64
+}
+
+// goFonts holds the parsed Go fonts, which are shared by all images.
+var goFonts struct {
+	once    sync.Once
+	regular *opentype.Font
+	bold    *opentype.Font
+	err     error
+}
+
+func (r *imageRenderer) Push() {
+	r.saved = append(r.saved, r.state)
+}
+
+func (r *imageRenderer) Pop() {
+	r.state = r.saved[len(r.saved)-1]
+	r.saved = r.saved[:len(r.saved)-1]
+}
+
+func (r *imageRenderer) Translate(x, y float64) {
+	m := &r.state.transform
+	m[2] += m[0]*x + m[1]*y
+	m[5] += m[3]*x + m[4]*y
+}
+
+func (r *imageRenderer) Rotate(angle float64) {
+	sin, cos := math.Sincos(angle)
+	m := r.state.transform
+	r.state.transform = f64.Aff3{
+		m[0]*cos + m[1]*sin, m[1]*cos - m[0]*sin, m[2],
+		m[3]*cos + m[4]*sin, m[4]*cos - m[3]*sin, m[5],
+	}
+}
+
+func (r *imageRenderer) SetGrey(grey float64) {
+	r.state.grey = grey
+}
+
+func (r *imageRenderer) SetLineWidth(width float64) {
+	r.state.lineWidth = width
+}
+
+func (r *imageRenderer) SetFont(style FontStyle, size float64) error {
+	goFonts.once.Do(func() {
+		if goFonts.regular, goFonts.err = opentype.Parse(goregular.TTF); goFonts.err != nil {
+			return
+		}
+		goFonts.bold, goFonts.err = opentype.Parse(gobold.TTF)
+	})
+	if goFonts.err != nil {
+		return goFonts.err
+	}
+	key := faceKey{style, size}
+	if face, ok := r.faces[key]; ok {
+		r.state.face = face
+		return nil
+	}
+	f := goFonts.regular
+	if style == BoldFont {
+		f = goFonts.bold
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     r.dpi,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return err
+	}
+	r.faces[key] = face
+	r.state.face = face
+	return nil
+}
+
+func (r *imageRenderer) FontName(style FontStyle) string {
+	if style == BoldFont {
+		return "Go-Bold"
+	}
+	return "Go-Regular"
+}
+
+func (r *imageRenderer) TextWidth(s string) float64 {
+	return float64(font.MeasureString(r.state.face, s)) / 64.0 / r.scale
+}
+
+func (r *imageRenderer) Text(x, y float64, s string) {
+	m := r.state.transform
+	if m[1] == 0 && m[3] == 0 {
+		px, py := r.pixel(x, y)
+		d := font.Drawer{
+			Dst:  r.img,
+			Src:  r.colour(),
+			Face: r.state.face,
+			Dot:  fixed.Point26_6{X: fixed.Int26_6(px * 64), Y: fixed.Int26_6(py * 64)},
+		}
+		d.DrawString(s)
+		return
+	}
+	// Rotated text is set into a separate image first, which is then
+	// transformed onto the invoice.
+	metrics := r.state.face.Metrics()
+	ascent := metrics.Ascent.Ceil()
+	width := font.MeasureString(r.state.face, s).Ceil()
+	text := image.NewRGBA(image.Rect(0, 0, width, ascent+metrics.Descent.Ceil()))
+	d := font.Drawer{
+		Dst:  text,
+		Src:  r.colour(),
+		Face: r.state.face,
+		Dot:  fixed.P(0, ascent),
+	}
+	d.DrawString(s)
+	px, py := r.pixel(x, y)
+	s2d := f64.Aff3{
+		m[0], -m[1], px + m[1]*float64(ascent),
+		-m[3], m[4], py - m[4]*float64(ascent),
+	}
+	xdraw.BiLinear.Transform(r.img, s2d, text, text.Bounds(), xdraw.Over, nil)
+}
+
+func (r *imageRenderer) Stroke(path *Path) {
+	z := vector.NewRasterizer(r.img.Bounds().Dx(), r.img.Bounds().Dy())
+	h := r.state.lineWidth / 2.0
+	// Each straight piece of the path is drawn as a rectangle, extended
+	// by half the line width at both ends so that corners are closed. All
+	// rectangles have the same orientation, so their overlaps are filled.
+	line := func(p0, p1 PathPoint) {
+		length := math.Hypot(p1.X-p0.X, p1.Y-p0.Y)
+		if length == 0 {
+			return
+		}
+		dx, dy := (p1.X-p0.X)/length*h, (p1.Y-p0.Y)/length*h
+		r.moveTo(z, p0.X-dx+dy, p0.Y-dy-dx)
+		r.lineTo(z, p1.X+dx+dy, p1.Y+dy-dx)
+		r.lineTo(z, p1.X+dx-dy, p1.Y+dy+dx)
+		r.lineTo(z, p0.X-dx-dy, p0.Y-dy+dx)
+		z.ClosePath()
+	}
+	var current, start PathPoint
+	for _, segment := range path.Segments {
+		switch segment.Op {
+		case MoveTo:
+			current, start = segment.Points[0], segment.Points[0]
+		case LineTo:
+			line(current, segment.Points[0])
+			current = segment.Points[0]
+		case CurveTo:
+			// Curves are approximated by straight lines.
+			const pieces = 16
+			c1, c2, end := segment.Points[0], segment.Points[1], segment.Points[2]
+			from := current
+			for n := 1; n <= pieces; n++ {
+				t := float64(n) / pieces
+				u := 1 - t
+				next := PathPoint{
+					X: u*u*u*from.X + 3*u*u*t*c1.X + 3*u*t*t*c2.X + t*t*t*end.X,
+					Y: u*u*u*from.Y + 3*u*u*t*c1.Y + 3*u*t*t*c2.Y + t*t*t*end.Y,
+				}
+				line(current, next)
+				current = next
+			}
+		case ClosePath:
+			line(current, start)
+			current = start
+		}
+	}
+	z.Draw(r.img, r.img.Bounds(), r.colour(), image.Point{})
+}
+
+func (r *imageRenderer) Fill(path *Path) {
+	z := vector.NewRasterizer(r.img.Bounds().Dx(), r.img.Bounds().Dy())
+	for _, segment := range path.Segments {
+		p := segment.Points
+		switch segment.Op {
+		case MoveTo:
+			r.moveTo(z, p[0].X, p[0].Y)
+		case LineTo:
+			r.lineTo(z, p[0].X, p[0].Y)
+		case CurveTo:
+			x1, y1 := r.pixel(p[0].X, p[0].Y)
+			x2, y2 := r.pixel(p[1].X, p[1].Y)
+			x3, y3 := r.pixel(p[2].X, p[2].Y)
+			z.CubeTo(float32(x1), float32(y1), float32(x2), float32(y2), float32(x3), float32(y3))
+		case ClosePath:
+			z.ClosePath()
+		}
+	}
+	z.Draw(r.img, r.img.Bounds(), r.colour(), image.Point{})
+}
+
+func (r *imageRenderer) Image(img image.Image, minX, minY, maxX, maxY float64) {
+	m := r.state.transform
+	sr := img.Bounds()
+	kx := (maxX - minX) / float64(sr.Dx())
+	ky := (maxY - minY) / float64(sr.Dy())
+	// Position of the source pixel 0, 0 in the coordinates of the renderer.
+	x0 := minX - float64(sr.Min.X)*kx
+	y0 := maxY + float64(sr.Min.Y)*ky
+	s := r.scale
+	s2d := f64.Aff3{
+		s * m[0] * kx, -s * m[1] * ky, s * (m[0]*x0 + m[1]*y0 + m[2]),
+		-s * m[3] * kx, s * m[4] * ky, s * (r.height - m[3]*x0 - m[4]*y0 - m[5]),
+	}
+	xdraw.ApproxBiLinear.Transform(r.img, s2d, img, sr, xdraw.Over, nil)
+}
+
+// pixel maps a point of the renderer to pixel coordinates.
+func (r *imageRenderer) pixel(x, y float64) (float64, float64) {
+	m := r.state.transform
+	return (m[0]*x + m[1]*y + m[2]) * r.scale,
+		(r.height - (m[3]*x + m[4]*y + m[5])) * r.scale
+}
+
+func (r *imageRenderer) moveTo(z *vector.Rasterizer, x, y float64) {
+	px, py := r.pixel(x, y)
+	z.MoveTo(float32(px), float32(py))
+}
+
+func (r *imageRenderer) lineTo(z *vector.Rasterizer, x, y float64) {
+	px, py := r.pixel(x, y)
+	z.LineTo(float32(px), float32(py))
+}
+
+// colour returns the current colour as image.
+func (r *imageRenderer) colour() image.Image {
+	return image.NewUniform(color.Gray{Y: uint8(r.state.grey*0xff + 0.5)})
+}
+
+// roundPixels rounds a length in pixels to the nearest integer.
+func roundPixels(f float64) int {
+	return int(f + 0.5)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderImage(t *testing.T) {
+	for i, p := range []Payload{examplePayload1, examplePayload2, examplePayload3} {
+		img, err := RenderImage(p, "de", 150)
+		if err != nil {
+			t.Fatalf("Item %v: unexpected error: %v", i, err)
+		}
+		// 210×105 mm at 150 dpi.
+		if b := img.Bounds(); b.Dx() != 1240 || b.Dy() != 620 {
+			t.Errorf("Item %v: unexpected image size: %v", i, b)
+		}
+		// The black square of the Swiss cross at the center of the QR
+		// code covers the point 87 mm from the left and 36 mm from the top.
+		if c := color.GrayModel.Convert(img.At(516, 215)).(color.Gray); c.Y > 0x40 {
+			t.Errorf("Item %v: expected black pixel, got %v", i, c)
+		}
+		// The upper left corner of the invoice is white.
+		if c := color.GrayModel.Convert(img.At(5, 5)).(color.Gray); c.Y != 0xff {
+			t.Errorf("Item %v: expected white pixel, got %v", i, c)
+		}
+	}
+}
+
+func TestRenderImageErrors(t *testing.T) {
+	tests := []struct {
+		data     Payload
+		language Language
+		dpi      int
+		err      string
+	}{
+		{examplePayload1, "de", 0, "Resolution must be positive"},
+		{Payload{}, "de", 72, "No account specified"},
+	}
+	for i, test := range tests {
+		_, err := RenderImage(test.data, test.language, test.dpi)
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.err, err)
+		}
+	}
+}
+
+func TestRenderReferenceBitmap(t *testing.T) {
+	img, err := RenderReferenceBitmap(examplePayload2, "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 419 || b.Dy() != 298 {
+		t.Errorf("Expected 419×298 pixels, got: %v", b)
+	}
+	again, err := RenderReferenceBitmap(examplePayload2, "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(img, again) {
+		t.Error("Expected identical bitmaps")
+	}
+	black := 0
+	gray := img.(*image.Gray)
+	for _, y := range gray.Pix {
+		switch y {
+		case 0x00:
+			black++
+		case 0xff:
+		default:
+			t.Fatalf("Expected black or white pixels, got: %#x", y)
+		}
+	}
+	if black == 0 {
+		t.Error("Expected black pixels")
+	}
+	if _, err := RenderReferenceBitmap(Payload{}, "de"); err == nil {
+		t.Error("Expected error for empty payload")
+	}
+}
+
+func TestRenderImageString(t *testing.T) {
+	language := "fr"
+	if _, err := RenderImageString(examplePayload1, language, 20); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "image"
+
+// Renderer is a drawing backend for invoices. RenderInvoice lays out the
+// invoice once and draws it by calling the methods of a Renderer, so that
+// all output formats share the same layout. NewPDFRenderer returns the
+// renderer for gopdf canvases, and RenderImage draws into an image; other
+// backends, such as other PDF libraries or SVG, implement this interface.
+//
+// Coordinates and lengths are given in points, with the origin at the lower
+// left corner of the invoice and the y axis pointing upwards, as in PDF.
+type Renderer interface {
+	// Push saves the transformation, the colour, the line width and the
+	// font; Pop restores the state saved by the matching Push.
+	Push()
+	Pop()
+
+	// Translate moves the origin of the coordinate system to x, y.
+	Translate(x, y float64)
+
+	// Rotate rotates the coordinate system counterclockwise by angle,
+	// given in radians.
+	Rotate(angle float64)
+
+	// SetGrey sets the colour of text, lines and filled areas to a grey
+	// level between 0 (black) and 1 (white).
+	SetGrey(grey float64)
+
+	// SetLineWidth sets the width of lines drawn by Stroke.
+	SetLineWidth(width float64)
+
+	// SetFont selects the font used by TextWidth and Text. The regular
+	// font is Helvetica or a similar sans-serif font; the bold font is the
+	// bold variant of the same typeface.
+	SetFont(style FontStyle, size float64) error
+
+	// TextWidth returns the width of s when set in the current font.
+	TextWidth(s string) float64
+
+	// Text draws s in the current font, with its base line starting at x, y.
+	Text(x, y float64, s string)
+
+	// Stroke draws the outline of the path; Fill fills its interior using
+	// the nonzero winding rule.
+	Stroke(path *Path)
+	Fill(path *Path)
+
+	// Image draws img scaled to the rectangle from minX, minY to maxX, maxY.
+	Image(img image.Image, minX, minY, maxX, maxY float64)
+}
+
+// FontStyle selects the font of a Renderer.
+type FontStyle int
+
+const (
+	// RegularFont is used for the values of the invoice.
+	RegularFont FontStyle = iota
+
+	// BoldFont is used for titles and headings.
+	BoldFont
+)
+
+// PathOp is the operation of a PathSegment.
+type PathOp int
+
+const (
+	MoveTo PathOp = iota
+	LineTo
+	CurveTo
+	ClosePath
+)
+
+// PathPoint is a point of a Path.
+type PathPoint struct {
+	X, Y float64
+}
+
+// PathSegment is one segment of a Path. Points holds the end point for
+// MoveTo and LineTo, the two control points of a cubic Bézier curve followed
+// by its end point for CurveTo, and no points for ClosePath.
+type PathSegment struct {
+	Op     PathOp
+	Points []PathPoint
+}
+
+// Path is an outline drawn by a Renderer. It is built like a path in PDF:
+// each subpath starts with Move and is optionally closed with Close.
+type Path struct {
+	Segments []PathSegment
+}
+
+// Move starts a new subpath at x, y.
+func (p *Path) Move(x, y float64) {
+	p.Segments = append(p.Segments, PathSegment{MoveTo, []PathPoint{{x, y}}})
+}
+
+// Line adds a straight line to x, y.
+func (p *Path) Line(x, y float64) {
+	p.Segments = append(p.Segments, PathSegment{LineTo, []PathPoint{{x, y}}})
+}
+
+// Curve adds a cubic Bézier curve with the control points x1, y1 and x2, y2
+// to x3, y3.
+func (p *Path) Curve(x1, y1, x2, y2, x3, y3 float64) {
+	p.Segments = append(p.Segments, PathSegment{CurveTo, []PathPoint{{x1, y1}, {x2, y2}, {x3, y3}}})
+}
+
+// Close closes the current subpath with a straight line to its start.
+func (p *Path) Close() {
+	p.Segments = append(p.Segments, PathSegment{Op: ClosePath})
+}
+
+// Rectangle adds a closed subpath along the edges of the rectangle.
+func (p *Path) Rectangle(minX, minY, maxX, maxY float64) {
+	p.Move(minX, minY)
+	p.Line(maxX, minY)
+	p.Line(maxX, maxY)
+	p.Line(minX, maxY)
+	p.Close()
+}
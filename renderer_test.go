@@ -0,0 +1,185 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordingRenderer records the calls of RenderInvoice. Text is measured
+// with the Helvetica metrics of the PDF renderer.
+type recordingRenderer struct {
+	calls []string
+	size  float64
+	depth int
+}
+
+func (r *recordingRenderer) record(format string, args ...interface{}) {
+	r.calls = append(r.calls, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingRenderer) Push()                      { r.depth++ }
+func (r *recordingRenderer) Pop()                       { r.depth-- }
+func (r *recordingRenderer) Translate(x, y float64)     {}
+func (r *recordingRenderer) Rotate(angle float64)       { r.record("rotate") }
+func (r *recordingRenderer) SetGrey(grey float64)       {}
+func (r *recordingRenderer) SetLineWidth(width float64) {}
+func (r *recordingRenderer) Stroke(path *Path)          { r.record("stroke") }
+func (r *recordingRenderer) Fill(path *Path)            { r.record("fill") }
+
+func (r *recordingRenderer) SetFont(style FontStyle, size float64) error {
+	r.size = size
+	return nil
+}
+
+func (r *recordingRenderer) TextWidth(s string) float64 {
+	return stringWidth(s) * r.size
+}
+
+func (r *recordingRenderer) Text(x, y float64, s string) {
+	r.record("text %v", s)
+}
+
+func (r *recordingRenderer) Image(img image.Image, minX, minY, maxX, maxY float64) {
+	r.record("image %.1f×%.1f", maxX-minX, maxY-minY)
+}
+
+func TestRenderInvoice(t *testing.T) {
+	r := new(recordingRenderer)
+	if err := RenderInvoice(r, examplePayload3, RenderOptions{Language: "en"}); err != nil {
+		t.Fatal(err)
+	}
+	if r.depth != 0 {
+		t.Errorf("Unbalanced Push and Pop: %v", r.depth)
+	}
+	expected := []string{
+		"text Receipt",
+		"text Currency",
+		"text Amount",
+		"text CHF",
+		"text Account / Payable to",
+		"text CH37 0900 0000 3044 4222 5",
+		"text Salvation Army Foundation Switzerland",
+		"text 3000 Bern",
+		"text Payable by (name/address)",
+		"text Acceptance point",
+		"text Payment part",
+	}
+	var texts []string
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, "text ") {
+			texts = append(texts, call)
+		}
+	}
+	if len(texts) < len(expected) || !reflect.DeepEqual(expected, texts[:len(expected)]) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, texts)
+	}
+	// The QR code is 46×46 mm.
+	if !strings.Contains(strings.Join(r.calls, "\n"), "image 130.4×130.4") {
+		t.Errorf("Expected QR code image, got: %v", r.calls)
+	}
+
+	r = new(recordingRenderer)
+	opts := RenderOptions{Language: "en", Draft: true, VectorQR: true, Separator: ScissorsSeparator}
+	if err := RenderInvoice(r, examplePayload2, opts); err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Join(r.calls, "\n")
+	for _, call := range []string{"fill", "rotate", "text DRAFT"} {
+		if !strings.Contains(calls, call) {
+			t.Errorf("Expected %v, got: %v", call, r.calls)
+		}
+	}
+	if strings.Contains(calls, "image") {
+		t.Errorf("Expected no image, got: %v", r.calls)
+	}
+}
+
+func TestTightParagraphSpacing(t *testing.T) {
+	section := []Paragraph{{Heading: "Heading", Lines: make([]string, 22)}}
+	options := layoutOptions{
+		headerSize: 8,
+		textSize:   10,
+		leading:    11,
+		left:       11.9 * PointsPerCm,
+		top:        10.0 * PointsPerCm,
+		maxHeight:  8.5 * PointsPerCm,
+	}
+	var testdata = []struct {
+		layout Layout
+		fits   bool
+	}{
+		{Layout{}, false},
+		{Layout{MinParagraphSpacing: 1}, false},
+		{Layout{MinLeading: 1.05}, true},
+		{Layout{MinLeading: 1.0, MinParagraphSpacing: 1}, true},
+	}
+	for _, item := range testdata {
+		invoice := &invoiceDrawer{
+			r:        new(recordingRenderer),
+			data:     examplePayload1,
+			language: "de",
+			layout:   item.layout,
+		}
+		err := invoice.drawParagraphs(section, options)
+		if item.fits && err != nil {
+			t.Errorf("Layout %v: unexpected error: %v", item.layout, err)
+		}
+		if !item.fits && err == nil {
+			t.Errorf("Layout %v: expected error due to text height", item.layout)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	path := new(Path)
+	path.Rectangle(0, 0, 2, 1)
+	path.Curve(1, 1, 2, 2, 3, 3)
+	var ops []PathOp
+	for _, segment := range path.Segments {
+		ops = append(ops, segment.Op)
+	}
+	expected := []PathOp{MoveTo, LineTo, LineTo, LineTo, ClosePath, CurveTo}
+	if !reflect.DeepEqual(expected, ops) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, ops)
+	}
+	if p := path.Segments[5].Points; len(p) != 3 || p[2] != (PathPoint{3, 3}) {
+		t.Errorf("Unexpected curve: %v", p)
+	}
+}
+
+func TestRenderPreprinted(t *testing.T) {
+	r := new(recordingRenderer)
+	opts := RenderOptions{Language: "en", Preprinted: true, Separator: ScissorsSeparator}
+	if err := RenderInvoice(r, examplePayload3, opts); err != nil {
+		t.Fatal(err)
+	}
+	calls := strings.Join(r.calls, "\n")
+	for _, call := range []string{"text CH37 0900 0000 3044 4222 5", "text CHF", "image"} {
+		if !strings.Contains(calls, call) {
+			t.Errorf("Expected %v, got: %v", call, r.calls)
+		}
+	}
+	for _, call := range []string{"text Receipt", "text Payment part", "text Currency",
+		"text Account / Payable to", "text Acceptance point", "stroke", "rotate"} {
+		if strings.Contains(calls, call) {
+			t.Errorf("Expected no %v, got: %v", call, r.calls)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// FileRouting selects where RenderFiles writes each invoice.
+type FileRouting struct {
+	// Name is a text/template executed with the FileNameData of each
+	// payload; the result is the slash-separated output name, e.g.
+	// “{{.Debtor.Name}}/{{.Reference}}.pdf”.
+	Name string
+
+	// Storage receives the files.
+	Storage Storage
+
+	// Route, if set, selects the storage for each payload, e.g. one
+	// storage per creditor. If it returns nil, Storage is used.
+	Route func(data Payload) Storage
+}
+
+// FileNameData is passed to the name template of FileRouting. All values
+// are safe to use in a name: slashes, backslashes and control characters
+// are replaced by “_”, so that only slashes in the template itself create
+// subdirectories.
+type FileNameData struct {
+	// Index is the position of the payload in the sequence.
+	Index int
+
+	// Reference is the payment reference in digital format, if any.
+	Reference string
+
+	Creditor FileNameParty
+	Debtor   FileNameParty
+
+	Currency string
+
+	// Amount is formatted with two decimals and without grouping, e.g.
+	// “1949.75”, or empty if the payload contains no amount.
+	Amount string
+}
+
+// FileNameParty is the part of an Entity available to name templates.
+type FileNameParty struct {
+	Name        string
+	CountryCode string
+}
+
+// template parses the name template.
+func (fr FileRouting) template() (*template.Template, error) {
+	if fr.Name == "" {
+		return nil, errors.New("No name template specified.")
+	}
+	if fr.Storage == nil && fr.Route == nil {
+		return nil, errors.New("No storage specified.")
+	}
+	return template.New("name").Option("missingkey=error").Parse(fr.Name)
+}
+
+// storage returns the storage for data.
+func (fr FileRouting) storage(data Payload) Storage {
+	if fr.Route != nil {
+		if s := fr.Route(data); s != nil {
+			return s
+		}
+	}
+	return fr.Storage
+}
+
+// executeFileName returns the output name of the payload at position i.
+func executeFileName(name *template.Template, data Payload, i int) (string, error) {
+	v := FileNameData{
+		Index:    i,
+		Creditor: fileNameParty(data.Creditor),
+		Debtor:   fileNameParty(data.UltimateDebtor),
+		Currency: data.CurrencyAmount.Currency,
+	}
+	if ref := data.Reference.Number; ref != nil {
+		v.Reference = sanitizeFileName(ref.DigitalFormat())
+	}
+	if data.CurrencyAmount.Amount > 0 {
+		v.Amount = fmt.Sprintf("%.2f", data.CurrencyAmount.Amount)
+	}
+	var buffer bytes.Buffer
+	if err := name.Execute(&buffer, v); err != nil {
+		return "", err
+	}
+	if err := checkStorageName(buffer.String()); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func fileNameParty(e Entity) FileNameParty {
+	return FileNameParty{
+		Name:        sanitizeFileName(e.Name),
+		CountryCode: sanitizeFileName(NormalizeCountryCode(e.CountryCode)),
+	}
+}
+
+// sanitizeFileName replaces characters that must not appear in a single
+// path element.
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestExecuteFileName(t *testing.T) {
+	slash := examplePayload2
+	slash.UltimateDebtor.Name = "Müller/Meier AG"
+	var testdata = []struct {
+		name     string
+		data     Payload
+		expected string
+		message  string
+	}{
+		{"{{.Reference}}-{{.Debtor.Name}}.pdf", examplePayload2,
+			"210000000003139471430009017-Pia-Maria Rutschmann-Schnyder.pdf", ""},
+		{"{{.Debtor.Name}}/{{.Index}}.pdf", slash, "Müller_Meier AG/7.pdf", ""},
+		{"{{.Currency}}/{{.Amount}}.pdf", examplePayload2, "CHF/1949.75.pdf", ""},
+		{"{{.Reference}}.pdf", examplePayload3, ".pdf", ""},
+		{"{{.Debtor.Name}}/x.pdf", examplePayload3, "", "Invalid output name"},
+		{"{{.Debtor.Town}}.pdf", examplePayload2, "", "can't evaluate field Town"},
+	}
+	for i, data := range testdata {
+		name := template.Must(template.New("name").Parse(data.name))
+		actual, err := executeFileName(name, data.data, 7)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			} else if actual != data.expected {
+				t.Errorf("Item %v: expected %q, got %q", i, data.expected, actual)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "fmt"
+
+// SpecVersion identifies a version of the Swiss Implementation Guidelines
+// QR-bill.
+type SpecVersion int
+
+const (
+	// SpecVersion20 is version 2.0 of 15 November 2018, which is
+	// implemented by Validate and Serialize.
+	SpecVersion20 SpecVersion = iota
+
+	// SpecVersion23 is version 2.3, which applies from November 2025. It
+	// extends the character set to the Latin letters of most European
+	// languages and no longer permits combined addresses.
+	SpecVersion23
+)
+
+// String returns the version number, e.g. “2.0”.
+func (v SpecVersion) String() string {
+	switch v {
+	case SpecVersion20:
+		return "2.0"
+	case SpecVersion23:
+		return "2.3"
+	}
+	return fmt.Sprintf("SpecVersion(%d)", int(v))
+}
+
+// ParseSpecVersion returns the version with the given number, e.g. “2.3”.
+func ParseSpecVersion(s string) (SpecVersion, error) {
+	for _, v := range []SpecVersion{SpecVersion20, SpecVersion23} {
+		if s == v.String() || s+".0" == v.String() {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("Unsupported version: %v", s)
+}
+
+// MarshalText encodes the version as its number, e.g. in JSON.
+func (v SpecVersion) MarshalText() ([]byte, error) {
+	if _, err := SpecRules(v); err != nil {
+		return nil, err
+	}
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText decodes a version number with ParseSpecVersion.
+func (v *SpecVersion) UnmarshalText(b []byte) error {
+	version, err := ParseSpecVersion(string(b))
+	if err != nil {
+		return err
+	}
+	*v = version
+	return nil
+}
+
+// RuleSet describes the rules that Validate enforces for a version of the
+// standard, as data for display to users, e.g. in the help text of a form.
+// Lengths are given in characters, as counted by Validate.
+type RuleSet struct {
+	Version SpecVersion
+
+	// QRVersion is the version of the QR code data written in its header.
+	// Version 2.3 of the guidelines keeps the data version “0200”.
+	QRVersion string
+
+	// CharacterSet contains all characters permitted in text fields.
+	CharacterSet string
+
+	// AccountCountries lists the permitted countries of the IBAN.
+	AccountCountries []string
+
+	// AddressTypes lists the permitted address types as encoded in the QR
+	// code: “S” for structured and “K” for combined addresses.
+	AddressTypes []string
+
+	// UltimateCreditor tells whether an ultimate creditor may be set. The
+	// standard reserves it for future use; Payload.Rules permits it if
+	// Payload.EnableUltimateCreditor is set.
+	UltimateCreditor bool
+
+	// Currencies lists the permitted currencies.
+	Currencies []string
+
+	// MaxAmount is the largest permitted amount.
+	MaxAmount float64
+
+	// ReferenceTypes lists the permitted reference types as encoded in
+	// the QR code.
+	ReferenceTypes []string
+
+	// Maximum lengths of the fields.
+	MaxNameLength           int
+	MaxAddressLineLength    int
+	MaxStreetNameLength     int
+	MaxBuildingNumberLength int
+	MaxPostCodeLength       int
+	MaxTownNameLength       int
+
+	// MaxInformationLength is the maximum combined length of the
+	// unstructured message and the bill information.
+	MaxInformationLength int
+
+	// MaxAlternativeProcedures is the maximum number of alternative
+	// procedures, each of at most MaxAlternativeProcedureLength.
+	MaxAlternativeProcedures      int
+	MaxAlternativeProcedureLength int
+}
+
+// SpecRules returns the rules enforced for the given version of the
+// standard.
+func SpecRules(version SpecVersion) (RuleSet, error) {
+	characterSet, addressTypes := validRunes, []string{"S", "K"}
+	switch version {
+	case SpecVersion20:
+	case SpecVersion23:
+		characterSet, addressTypes = extendedRunes, []string{"S"}
+	default:
+		return RuleSet{}, fmt.Errorf("Unsupported version: %v", version)
+	}
+	return RuleSet{
+		Version:                       version,
+		QRVersion:                     "0200",
+		CharacterSet:                  characterSet,
+		AccountCountries:              []string{"CH", "LI"},
+		AddressTypes:                  addressTypes,
+		Currencies:                    []string{CHF, EUR},
+		MaxAmount:                     999999999.99,
+		ReferenceTypes:                []string{"QRR", "SCOR", "NON"},
+		MaxNameLength:                 70,
+		MaxAddressLineLength:          70,
+		MaxStreetNameLength:           70,
+		MaxBuildingNumberLength:       16,
+		MaxPostCodeLength:             16,
+		MaxTownNameLength:             35,
+		MaxInformationLength:          maxInformationLength,
+		MaxAlternativeProcedures:      2,
+		MaxAlternativeProcedureLength: 100,
+	}, nil
+}
+
+// Rules returns the rules enforced for the payload by Validate: those of
+// its version, with an ultimate creditor permitted if
+// EnableUltimateCreditor is set.
+func (p Payload) Rules() (RuleSet, error) {
+	rules, err := SpecRules(p.Version)
+	if err != nil {
+		return RuleSet{}, err
+	}
+	rules.UltimateCreditor = p.EnableUltimateCreditor
+	return rules, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSpecRules(t *testing.T) {
+	rules, err := SpecRules(SpecVersion20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Version.String() != "2.0" {
+		t.Errorf("Expected version 2.0, got: %v", rules.Version)
+	}
+	if err := ValidateCharacterSet(rules.CharacterSet); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// The limits are the ones enforced by Validate.
+	p := examplePayload1
+	p.Creditor.Name = strings.Repeat("x", rules.MaxNameLength)
+	if err := p.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	p.Creditor.Name += "x"
+	if err := p.Validate(); err == nil {
+		t.Errorf("Expected error for name longer than %d", rules.MaxNameLength)
+	}
+	p = examplePayload1
+	p.CurrencyAmount.Amount = rules.MaxAmount
+	if err := p.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	p.CurrencyAmount.Amount = rules.MaxAmount + 1
+	if err := p.Validate(); err == nil {
+		t.Errorf("Expected error for amount larger than %v", rules.MaxAmount)
+	}
+	p = examplePayload1
+	p.AdditionalInformation = PaymentInformation{
+		UnstructuredMessage: strings.Repeat("x", rules.MaxInformationLength+1),
+	}
+	if err := p.Validate(); err == nil {
+		t.Errorf("Expected error for message longer than %d", rules.MaxInformationLength)
+	}
+
+	if _, err := SpecRules(SpecVersion(7)); err == nil || err.Error() != "Unsupported version: SpecVersion(7)" {
+		t.Errorf("Expected error for unknown version, got: %v", err)
+	}
+}
+
+func TestPayloadRules(t *testing.T) {
+	p := examplePayload1
+	p.UltimateCreditor = p.Creditor
+	for _, enable := range []bool{false, true} {
+		p.EnableUltimateCreditor = enable
+		rules, err := p.Rules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The rules tell whether Validate accepts the ultimate creditor.
+		if valid := p.Validate() == nil; rules.UltimateCreditor != enable || valid != enable {
+			t.Errorf("Enable %v: expected ultimate creditor permitted %v, got: %v, valid %v",
+				enable, enable, rules.UltimateCreditor, valid)
+		}
+	}
+}
+
+func TestSpecVersionText(t *testing.T) {
+	for _, v := range []SpecVersion{SpecVersion20, SpecVersion23} {
+		b, err := v.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded SpecVersion
+		if err := decoded.UnmarshalText(b); err != nil || decoded != v {
+			t.Errorf("Expected %v, got: %v %v", v, decoded, err)
+		}
+	}
+	if v, err := ParseSpecVersion("2"); err != nil || v != SpecVersion20 {
+		t.Errorf("Expected version 2.0, got: %v %v", v, err)
+	}
+	if _, err := ParseSpecVersion("2.1"); err == nil {
+		t.Errorf("Expected error for version 2.1")
+	}
+	if _, err := SpecVersion(7).MarshalText(); err == nil {
+		t.Errorf("Expected error for unknown version")
+	}
+	p := examplePayload1
+	p.Version = SpecVersion(7)
+	if err := p.Validate(); err == nil || err.(*ValidationError).Field != "Version" {
+		t.Errorf("Expected error for unknown version, got: %v", err)
+	}
+	rules, err := SpecRules(SpecVersion23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SpecVersion23.ValidateCharacterSet(rules.CharacterSet); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSpecVersion23Addresses(t *testing.T) {
+	p := examplePayload1
+	p.Version = SpecVersion23
+	p.Creditor.Address = CombinedAddress{AddressLine1: "Rue du Lac 1268", AddressLine2: "2501 Biel"}
+	err := p.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Field != "Creditor.Address" || ve.Code != CodeNotAllowed {
+		t.Errorf("Expected error for combined address, got: %v", err)
+	}
+	if r := p.Check(); len(r.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", r.Warnings)
+	}
+	var buffer bytes.Buffer
+	if err := p.Serialize(&buffer); err == nil {
+		t.Errorf("Expected error for combined address")
+	}
+
+	p.Version = SpecVersion20
+	if err := p.Validate(); err != nil {
+		t.Errorf("Unexpected error for version 2.0: %v", err)
+	}
+	rules, err := SpecRules(SpecVersion23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rules.AddressTypes, ",") != "S" || rules.QRVersion != "0200" {
+		t.Errorf("Unexpected rules: %v %v", rules.AddressTypes, rules.QRVersion)
+	}
+}
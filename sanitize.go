@@ -0,0 +1,110 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sanitize cleans up the text fields of the payload before validation, as
+// needed for data imported in bulk: it trims whitespace and collapses runs
+// of whitespace to a single space, transliterates characters outside the
+// character set of the version of the payload like Transliterate and
+// removes those without replacement, and truncates fields longer than
+// permitted. Country codes are normalized like NormalizeCountryCode, and
+// alpha-3 codes are converted with CountryCodeFromAlpha3. The
+// unstructured message is truncated to the space left by the bill
+// information. Account, amount, reference and alternative procedures are
+// not changed. Sanitize returns the cleaned payload together with one
+// description per changed field; the same payload always yields the same
+// result.
+func (p Payload) Sanitize() (Payload, []string) {
+	var changes []string
+	change := func(field string, s *string, sanitized string) {
+		if sanitized != *s {
+			changes = append(changes, fmt.Sprintf("%v changed from %q to %q", field, *s, sanitized))
+			*s = sanitized
+		}
+	}
+	clean := func(field string, s *string, maxLength int) {
+		change(field, s, sanitizeText(*s, maxLength, p.Version))
+	}
+	for _, party := range []struct {
+		field  string
+		entity *Entity
+	}{
+		{"Creditor", &p.Creditor},
+		{"UltimateCreditor", &p.UltimateCreditor},
+		{"UltimateDebtor", &p.UltimateDebtor},
+	} {
+		e := party.entity
+		clean(party.field+".Name", &e.Name, 70)
+		if code, err := CountryCodeFromAlpha3(e.CountryCode); err == nil {
+			change(party.field+".CountryCode", &e.CountryCode, code)
+		} else {
+			change(party.field+".CountryCode", &e.CountryCode,
+				NormalizeCountryCode(sanitizeText(e.CountryCode, 2, p.Version)))
+		}
+		switch a := e.Address.(type) {
+		case CombinedAddress:
+			clean(party.field+".Address.AddressLine1", &a.AddressLine1, 70)
+			clean(party.field+".Address.AddressLine2", &a.AddressLine2, 70)
+			e.Address = a
+		case StructuredAddress:
+			clean(party.field+".Address.StreetName", &a.StreetName, 70)
+			clean(party.field+".Address.BuildingNumber", &a.BuildingNumber, 16)
+			clean(party.field+".Address.PostCode", &a.PostCode, 16)
+			clean(party.field+".Address.TownName", &a.TownName, 35)
+			e.Address = a
+		}
+	}
+	bi := &p.AdditionalInformation.StructuredMessage
+	clean("AdditionalInformation.StructuredMessage.InvoiceNumber", &bi.InvoiceNumber, maxInformationLength)
+	clean("AdditionalInformation.StructuredMessage.CustomerReference", &bi.CustomerReference, maxInformationLength)
+	remaining := maxInformationLength - utf8.RuneCountInString(bi.ToString())
+	if remaining < 0 {
+		remaining = 0
+	}
+	clean("AdditionalInformation.UnstructuredMessage", &p.AdditionalInformation.UnstructuredMessage, remaining)
+	return p, changes
+}
+
+// sanitizeText returns s with whitespace collapsed, characters outside the
+// character set of version v transliterated or removed, and cut to at most
+// maxLength characters.
+func sanitizeText(s string, maxLength int, v SpecVersion) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	var b strings.Builder
+	for _, r := range s {
+		if v.isValidRune(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(transliterations[r])
+		}
+	}
+	s = strings.Join(strings.Fields(b.String()), " ")
+	if r := []rune(s); len(r) > maxLength {
+		s = strings.TrimRight(string(r[:maxLength]), " ")
+	}
+	return s
+}
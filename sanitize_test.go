@@ -0,0 +1,81 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	payload := minimalCorrectPayload
+	payload.Creditor = Entity{
+		Name: "  Søren\tØstergaard   ApS ",
+		Address: StructuredAddress{
+			StreetName: "Strøget",
+			PostCode:   "1160",
+			TownName:   "København K – Indre By, Region Hovedstaden",
+		},
+		CountryCode: " dnk",
+	}
+	payload.AdditionalInformation.UnstructuredMessage = "Order\n" + strings.Repeat("x", 200)
+	sanitized, changes := payload.Sanitize()
+	if err := sanitized.Validate(); err != nil {
+		t.Errorf("Expected valid payload, got: %v", err)
+	}
+	if sanitized.Creditor.Name != "Soeren OEstergaard ApS" {
+		t.Errorf("Unexpected name: %q", sanitized.Creditor.Name)
+	}
+	if town := sanitized.Creditor.Address.(StructuredAddress).TownName; town != "Koebenhavn K - Indre By, Region Hov" {
+		t.Errorf("Unexpected town: %q", town)
+	}
+	if sanitized.Creditor.CountryCode != "DK" {
+		t.Errorf("Unexpected country code: %q", sanitized.Creditor.CountryCode)
+	}
+	if message := sanitized.AdditionalInformation.UnstructuredMessage; len(message) != 140 || !strings.HasPrefix(message, "Order x") {
+		t.Errorf("Unexpected message: %q", message)
+	}
+	if len(changes) != 5 {
+		t.Errorf("Expected 5 changes, got: %q", changes)
+	}
+	if payload.Creditor.Name != "  Søren\tØstergaard   ApS " {
+		t.Errorf("Original payload changed: %q", payload.Creditor.Name)
+	}
+
+	// Sanitizing again changes nothing.
+	if again, changes := sanitized.Sanitize(); len(changes) != 0 || again.Creditor != sanitized.Creditor {
+		t.Errorf("Expected no changes, got: %q", changes)
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	var testdata = []struct {
+		input     string
+		maxLength int
+		expected  string
+	}{
+		{"Robert Schneider AG", 70, "Robert Schneider AG"},
+		{" a   b\r\nc ", 70, "a b c"},
+		{"漢字 Name", 70, "Name"},
+		{"Zürich", 2, "Zü"},
+		{"Zürich Äsch", 7, "Zürich"},
+		{"ab cd", 3, "ab"},
+	}
+	for i, item := range testdata {
+		if result := sanitizeText(item.input, item.maxLength, SpecVersion20); result != item.expected {
+			t.Errorf("Item %v: expected %q, got: %q", i, item.expected, result)
+		}
+	}
+}
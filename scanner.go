@@ -0,0 +1,116 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyboardLayout tells NormalizeScan how a keyboard-wedge scanner mangles
+// its input. Such scanners “type” the payload by sending key codes, which
+// the host translates using its own keyboard layout.
+type KeyboardLayout int
+
+const (
+	// KeyboardMatching is used if the scanner and the host use the same
+	// keyboard layout, or if the scanner does not emulate a keyboard.
+	KeyboardMatching KeyboardLayout = iota
+
+	// KeyboardUSOnSwiss is used if the scanner emulates a US keyboard
+	// while the host uses the Swiss German or Swiss French layout. This
+	// swaps Y and Z and garbles most punctuation, e.g. “/” arrives as “-”.
+	KeyboardUSOnSwiss
+)
+
+// usOnSwiss maps characters produced by a Swiss host to the characters
+// sent by a scanner emulating a US keyboard.
+var usOnSwiss = map[rune]rune{
+	'z': 'y', 'y': 'z', 'Z': 'Y', 'Y': 'Z',
+	'\'': '-', '-': '/', '+': '!', '"': '@', '*': '#', 'ç': '$',
+	'&': '^', '/': '&', '(': '*', ')': '(', '=': ')', '?': '_',
+	';': '<', ':': '>', '_': '?', 'ü': '[', 'ö': ';', 'ä': '\'',
+	'$': '\\', 'é': ':', 'à': '"', 'è': '{', '§': '`',
+}
+
+// NormalizeScan turns raw scanner input into text suitable for the payload
+// parser. It decodes Latin-1 input and repairs UTF-8 text that was encoded
+// twice, undoes the key mapping of the given keyboard layout, converts all
+// line breaks to CR LF as required by the standard, drops other control
+// characters and removes trailing line breaks appended by the scanner.
+func NormalizeScan(raw []byte, layout KeyboardLayout) string {
+	s := decodeScan(raw)
+	s = strings.TrimPrefix(s, "\ufeff") // Byte order mark.
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == '\r' || r == '\n':
+			// Any of CR, LF, CR LF and CR CR LF ends one line; other
+			// runs of CR and LF end one line each, since scanners that
+			// end lines with CR only send one CR per empty field.
+			if r == '\r' {
+				switch {
+				case strings.HasPrefix(s[i:], "\n"):
+					i++
+				case strings.HasPrefix(s[i:], "\r\n"):
+					i += 2
+				}
+			}
+			b.WriteString("\r\n")
+		case r < ' ' || r == 0x7f:
+			// Other control characters are never part of a payload.
+		default:
+			if layout == KeyboardUSOnSwiss {
+				if m, ok := usOnSwiss[r]; ok {
+					r = m
+				}
+			}
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimRight(b.String(), "\r\n")
+}
+
+// decodeScan converts raw input to a string. Input that is not valid UTF-8
+// is taken to be Latin-1. Valid UTF-8 whose runes, taken as Latin-1 bytes,
+// again form UTF-8 multi-byte sequences was encoded twice and is decoded
+// once more.
+func decodeScan(raw []byte) string {
+	if !utf8.Valid(raw) {
+		runes := make([]rune, len(raw))
+		for i, c := range raw {
+			runes[i] = rune(c)
+		}
+		return string(runes)
+	}
+	s := string(raw)
+	inner := make([]byte, 0, len(s))
+	multiByte := false
+	for _, r := range s {
+		if r > 0xff {
+			return s
+		}
+		if r >= 0x80 {
+			multiByte = true
+		}
+		inner = append(inner, byte(r))
+	}
+	if multiByte && utf8.Valid(inner) {
+		return string(inner)
+	}
+	return s
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeScan(t *testing.T) {
+	var testdata = []struct {
+		raw      []byte
+		layout   KeyboardLayout
+		expected string
+	}{
+		{[]byte("SPC\r\n0200\r\n1"), KeyboardMatching, "SPC\r\n0200\r\n1"},
+		{[]byte("SPC\n0200\n1\n"), KeyboardMatching, "SPC\r\n0200\r\n1"},
+		{[]byte("SPC\r0200\r\r\n1\r"), KeyboardMatching, "SPC\r\n0200\r\n1"},
+		{[]byte("SPC\r\n\r\nEPD"), KeyboardMatching, "SPC\r\n\r\nEPD"},
+		{[]byte("SPC\r\r\rEPD"), KeyboardMatching, "SPC\r\n\r\n\r\nEPD"},
+		{[]byte("SPC\n\nEPD"), KeyboardMatching, "SPC\r\n\r\nEPD"},
+		{[]byte("\ufeffZ\x00ürich\t"), KeyboardMatching, "Zürich"},
+		{[]byte("Z\xfcrich"), KeyboardMatching, "Zürich"},             // Latin-1.
+		{[]byte("Z\xc3\x83\xc2\xbcrich"), KeyboardMatching, "Zürich"}, // UTF-8 twice.
+		{[]byte("Zürich"), KeyboardMatching, "Zürich"},
+		{[]byte("--S1-10-10201409"), KeyboardUSOnSwiss, "//S1/10/10201409"},
+		{[]byte("6300 Yug"), KeyboardUSOnSwiss, "6300 Zug"},
+	}
+	for i, data := range testdata {
+		if actual := NormalizeScan(data.raw, data.layout); actual != data.expected {
+			t.Errorf("Item %v: expected %#v, got %#v", i, data.expected, actual)
+		}
+	}
+}
+
+func TestNormalizeScanCROnly(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := minimalCorrectPayload.Serialize(&buffer); err != nil {
+		t.Fatalf("Could not serialize payload: %v", err)
+	}
+	expected := buffer.String()
+	raw := strings.ReplaceAll(expected, "\r\n", "\r")
+	normalized := NormalizeScan([]byte(raw), KeyboardMatching)
+	if !strings.HasPrefix(expected, normalized) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, normalized)
+	}
+	p, err := Parse(normalized)
+	if err != nil {
+		t.Fatalf("Could not parse payload: %v", err)
+	}
+	buffer.Reset()
+	if err := p.Serialize(&buffer); err != nil {
+		t.Fatalf("Could not serialize parsed payload: %v", err)
+	}
+	if buffer.String() != expected {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, buffer.String())
+	}
+}
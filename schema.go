@@ -0,0 +1,183 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type schema map[string]interface{}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the JSON
+// representation of a Payload. The schema is generated from the Go types,
+// so it always matches the encoding implemented by this package. Note that
+// the schema only checks the shape of a document; a payload that conforms
+// to the schema must still pass Payload.Validate().
+func JSONSchema() ([]byte, error) {
+	defs := map[string]schema{"Entity": entitySchema()}
+	root := schemaFor(reflect.TypeOf(Payload{}), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "Swiss QR bill payload"
+	root["$defs"] = defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaFor returns the schema for t. Types with a custom JSON encoding are
+// described by schemaOverrides; all other types are derived by reflection.
+// Named struct types are placed in defs and referenced.
+func schemaFor(t reflect.Type, defs map[string]schema) schema {
+	if override, ok := schemaOverrides[t]; ok {
+		return override()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t != reflect.TypeOf(Payload{}) {
+			if _, ok := defs[t.Name()]; !ok {
+				defs[t.Name()] = nil // Guard against recursion.
+				defs[t.Name()] = structSchema(t, defs)
+			}
+			return schema{"$ref": "#/$defs/" + t.Name()}
+		}
+		return structSchema(t, defs)
+	case reflect.Slice:
+		return schema{
+			"type":  []string{"array", "null"},
+			"items": schemaFor(t.Elem(), defs),
+		}
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return schema{"type": "integer"}
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	}
+	return schema{}
+}
+
+func structSchema(t reflect.Type, defs map[string]schema) schema {
+	properties := schema{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // Unexported.
+		}
+		properties[field.Name] = schemaFor(field.Type, defs)
+	}
+	return schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// schemaOverrides describes the types that have a custom JSON encoding, or
+// whose values are restricted beyond what the Go type expresses.
+var schemaOverrides = map[reflect.Type]func() schema{
+	reflect.TypeOf(AccountNumber{}): func() schema {
+		return schema{
+			"description": "IBAN or QR-IBAN of the creditor, with or without spaces.",
+			"type":        []string{"string", "null"},
+			"pattern":     "^(CH|LI)[0-9 ]{2}[0-9A-Z ]+$",
+		}
+	},
+	reflect.TypeOf(PaymentReference{}): func() schema {
+		return schema{
+			"description": "QR reference (27 digits) or creditor reference (“RF…”).",
+			"type":        []string{"string", "null"},
+			"maxLength":   35,
+		}
+	},
+	reflect.TypeOf(dates{}): func() schema {
+		return schema{
+			"description": "A date “YYYY-MM-DD” or an interval “YYYY-MM-DD/YYYY-MM-DD”.",
+			"type":        "string",
+			"pattern":     "^([0-9]{4}-[0-9]{2}-[0-9]{2}(/[0-9]{4}-[0-9]{2}-[0-9]{2})?)?$",
+		}
+	},
+	reflect.TypeOf(Entity{}): func() schema {
+		return schema{"$ref": "#/$defs/Entity"}
+	},
+	reflect.TypeOf(PaymentAmount{}): func() schema {
+		return schema{
+			"type": "object",
+			"properties": schema{
+				"Amount":   schema{"type": "number", "minimum": 0},
+				"Currency": schema{"enum": []string{CHF, EUR}},
+				"Mode":     schema{"enum": []AmountMode{AmountBox, AmountZero, AmountOmit}},
+			},
+			"required":             []string{"Currency"},
+			"additionalProperties": false,
+		}
+	},
+	reflect.TypeOf(SpecVersion(0)): func() schema {
+		return schema{"enum": []string{SpecVersion20.String(), SpecVersion23.String()}}
+	},
+	reflect.TypeOf(AlternativeProcedure{}): func() schema {
+		return schema{
+			"type": "object",
+			"properties": schema{
+				"Label": schema{"type": "string", "minLength": 1},
+				"Labels": schema{
+					"type":                 []string{"object", "null"},
+					"additionalProperties": schema{"type": "string", "minLength": 1},
+				},
+				"Procedure": schema{"type": "string", "minLength": 1, "maxLength": 100},
+			},
+			"additionalProperties": false,
+		}
+	},
+}
+
+// entitySchema describes the JSON representation of an Entity, which is
+// shared by all parties of a payload.
+func entitySchema() schema {
+	return schema{
+		"type": "object",
+		"properties": schema{
+			"Name":        schema{"type": "string", "maxLength": 70},
+			"CountryCode": schema{"type": "string", "pattern": "^\\s*([A-Za-z]{2})?\\s*$"},
+			"Address": schema{
+				"oneOf": []schema{
+					{
+						"type": "object",
+						"properties": schema{
+							"Type":         schema{"const": "K"},
+							"AddressLine1": schema{"type": "string", "maxLength": 70},
+							"AddressLine2": schema{"type": "string", "maxLength": 70},
+						},
+						"required":             []string{"Type", "AddressLine2"},
+						"additionalProperties": false,
+					},
+					{
+						"type": "object",
+						"properties": schema{
+							"Type":           schema{"const": "S"},
+							"StreetName":     schema{"type": "string", "maxLength": 70},
+							"BuildingNumber": schema{"type": "string", "maxLength": 16},
+							"PostCode":       schema{"type": "string", "maxLength": 16},
+							"TownName":       schema{"type": "string", "maxLength": 35},
+						},
+						"required":             []string{"Type", "PostCode", "TownName"},
+						"additionalProperties": false,
+					},
+				},
+			},
+		},
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	b, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("Could not generate schema: %v", err)
+	}
+	var s struct {
+		Properties map[string]map[string]interface{}
+		Defs       map[string]interface{} `json:"$defs"`
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	for _, field := range []string{"Account", "Creditor", "UltimateCreditor",
+		"CurrencyAmount", "UltimateDebtor", "Reference",
+		"AdditionalInformation", "AlternativeProcedureParameters"} {
+		if _, ok := s.Properties[field]; !ok {
+			t.Errorf("Missing property %v in schema", field)
+		}
+	}
+	for _, def := range []string{"Entity", "PaymentInformation", "BillInformation"} {
+		if _, ok := s.Defs[def]; !ok {
+			t.Errorf("Missing definition %v in schema", def)
+		}
+	}
+}
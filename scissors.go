@@ -0,0 +1,28 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import "github.com/krepost/gopdf/pdf"
+
+// DrawScissors draws a scissors symbol as vector path, so that it does not
+// depend on the fonts available in the PDF viewer. The symbol is size long,
+// centered at center, and its blades point in the direction given by angle
+// in radians, counterclockwise from the positive x axis. The symbol is
+// drawn in the current fill and stroke colours.
+func DrawScissors(canvas *pdf.Canvas, center pdf.Point, size pdf.Unit, angle float64) {
+	drawScissors(NewPDFRenderer(canvas), float64(center.X), float64(center.Y), float64(size), angle)
+}
@@ -0,0 +1,354 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+// SeqOptions controls how RenderSeq lays out the invoices.
+type SeqOptions struct {
+	// RenderOptions apply to each invoice.
+	RenderOptions
+
+	// SlipOnly selects pages of the size of the invoice (210×105 mm)
+	// instead of A4 pages with the invoice at the bottom.
+	SlipOnly bool
+
+	// Registry, if set, registers the payment reference of each payload.
+	// A duplicate reference stops rendering, unless OnDuplicate is set:
+	// then OnDuplicate is called with the position of the payload in the
+	// sequence and the invoice is rendered anyway. References of invoices
+	// that are not written after all are released again.
+	Registry    ReferenceRegistry
+	OnDuplicate func(i int, reference string)
+
+	// Preflight checks the print requirements of all invoices with
+	// Preflight before the document is written.
+	Preflight bool
+
+	// Audit, if set, receives an AuditRecord for each invoice after the
+	// document has been written.
+	Audit AuditSink
+}
+
+// BatchItem is the outcome of one payload of a batch.
+type BatchItem struct {
+	// Index is the position of the payload in the sequence.
+	Index int
+
+	// Page is the zero-based page number of the invoice, or -1 if the
+	// payload was not rendered.
+	Page int
+
+	// Warnings lists problems that did not prevent rendering, such as a
+	// duplicate reference reported to OnDuplicate.
+	Warnings []string
+
+	// Err is the reason why the payload was not rendered.
+	Err error
+
+	// Name is the output name of the invoice, for RenderFiles only.
+	Name string
+}
+
+// BatchResult reports the outcome of each payload of a batch.
+type BatchResult struct {
+	Items []BatchItem
+
+	// Rendered and Failed count the payloads with and without a page.
+	Rendered int
+	Failed   int
+}
+
+// Failures returns the items that were not rendered, e.g. to retry them.
+func (r BatchResult) Failures() []BatchItem {
+	var failures []BatchItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failures = append(failures, item)
+		}
+	}
+	return failures
+}
+
+// ErrNoInvoices is returned by RenderSeq and RenderBatch instead of writing
+// a document without pages, e.g. if the sequence is empty or every payload
+// failed.
+var ErrNoInvoices = errors.New("No invoices rendered")
+
+// RenderSeq draws one invoice per page for each payload produced by seq and
+// writes the resulting PDF document to w. Payloads are consumed one at a
+// time, so they can be streamed from a database cursor without collecting
+// them in a slice first. Rendering stops at the first invalid payload; the
+// error reports its position in the sequence.
+func RenderSeq(seq iter.Seq[Payload], w io.Writer, opts SeqOptions) error {
+	_, err := renderSeq(context.Background(), seq, w, opts, true)
+	return err
+}
+
+// RenderBatch works like RenderSeq, but skips invalid payloads instead of
+// stopping, and reports the outcome of every payload in the result. The
+// error is only set if no document could be written at all, e.g. due to
+// invalid options, a failed preflight or ErrNoInvoices if every payload
+// failed.
+func RenderBatch(seq iter.Seq[Payload], w io.Writer, opts SeqOptions) (BatchResult, error) {
+	return renderSeq(context.Background(), seq, w, opts, false)
+}
+
+// renderSeq implements RenderSeq and RenderBatch. If no document is written,
+// all references registered so far are released.
+func renderSeq(ctx context.Context, seq iter.Seq[Payload], w io.Writer, opts SeqOptions, stop bool) (BatchResult, error) {
+	var result BatchResult
+	if err := opts.RenderOptions.Validate(); err != nil {
+		return result, err
+	}
+	var registered []Payload
+	fail := func(err error) (BatchResult, error) {
+		return result, opts.release(ctx, err, registered...)
+	}
+	warnings := opts.warnings()
+	doc := pdf.New()
+	height := Millimeter(297)
+	if opts.SlipOnly {
+		height = 105
+	}
+	var proof Proof
+	var records []AuditRecord
+	i := 0
+	for data := range seq {
+		if i > 0 && opts.QRImage != nil {
+			return fail(errQRImageForSeveral)
+		}
+		item, ok := opts.checkPayload(ctx, data, i, warnings)
+		i++
+		if ok {
+			registered = append(registered, data)
+		}
+		var record AuditRecord
+		if item.Err == nil && opts.Audit != nil {
+			record, item.Err = newAuditRecord(data, opts.RenderOptions, result.Rendered)
+		}
+		if item.Err != nil {
+			if stop {
+				return fail(fmt.Errorf("Payload %d: %w", item.Index, item.Err))
+			}
+			if ok {
+				registered = registered[:len(registered)-1]
+				item.Err = opts.release(ctx, item.Err, data)
+			}
+			result.Items = append(result.Items, item)
+			result.Failed++
+			continue
+		}
+		// The payload is valid, so drawing it only fails due to a bug;
+		// since the page cannot be removed again, this stops the batch.
+		item.Page = result.Rendered
+		canvas := doc.NewPage(21.0*pdf.Cm, height.Unit())
+		invoice, err := RenderInvoiceWithProof(NewPDFRenderer(canvas), data,
+			opts.RenderOptions, item.Page, 210, height, 0, 0)
+		canvas.Close()
+		if err != nil {
+			return fail(fmt.Errorf("Payload %d: %w", item.Index, err))
+		}
+		proof.Invoices = append(proof.Invoices, invoice)
+		if opts.Audit != nil {
+			records = append(records, record)
+		}
+		result.Items = append(result.Items, item)
+		result.Rendered++
+	}
+	if result.Rendered == 0 {
+		return fail(ErrNoInvoices)
+	}
+	if opts.Preflight {
+		if err := Preflight(proof); err != nil {
+			return fail(err)
+		}
+	}
+	h := sha256.New()
+	if err := doc.Encode(io.MultiWriter(w, h)); err != nil {
+		return fail(err)
+	}
+	if opts.Audit == nil {
+		return result, nil
+	}
+	for _, record := range records {
+		record.Output = fingerprint(h)
+		if err := opts.Audit.Record(ctx, record); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// warnings returns the warnings that apply to all payloads.
+func (opts SeqOptions) warnings() []string {
+	var warnings []string
+	if requested, language := opts.Language, opts.language(); language != requested {
+		warnings = append(warnings, fmt.Sprintf("Unsupported language %v replaced by %v", requested, language))
+	}
+	return warnings
+}
+
+// checkPayload validates the payload at position i and registers its
+// reference. The returned item is not rendered yet. It reports whether a
+// reference was registered, which must be released with release if the
+// invoice is not written.
+func (opts SeqOptions) checkPayload(ctx context.Context, data Payload, i int, warnings []string) (BatchItem, bool) {
+	item := BatchItem{Index: i, Page: -1, Warnings: append([]string(nil), warnings...)}
+	item.Err = data.Validate()
+	if item.Err != nil || opts.Registry == nil || data.Reference.Number == nil {
+		return item, false
+	}
+	err := RegisterReference(ctx, opts.Registry, data)
+	if errors.Is(err, ErrDuplicateReference) && opts.OnDuplicate != nil {
+		opts.OnDuplicate(item.Index, data.Reference.Number.DigitalFormat())
+		item.Warnings = append(item.Warnings, err.Error())
+		return item, false
+	}
+	item.Err = err
+	return item, err == nil
+}
+
+// release releases the references of payloads registered by checkPayload
+// after their invoices failed with err, and returns err together with the
+// errors of the release. It also runs if ctx has been cancelled, since the
+// references would otherwise be lost.
+func (opts SeqOptions) release(ctx context.Context, err error, payloads ...Payload) error {
+	ctx = context.WithoutCancel(ctx)
+	errs := []error{err}
+	for _, data := range payloads {
+		if releaseErr := ReleaseReference(ctx, opts.Registry, data); releaseErr != nil {
+			errs = append(errs, releaseErr)
+		}
+	}
+	if len(errs) == 1 {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// RenderFiles renders each payload produced by seq into a PDF document of
+// its own, which is written to the storage selected by routing under the
+// name given by its template. The invoices are placed as by RenderSeq.
+// Like RenderBatch, RenderFiles skips payloads that cannot be rendered or
+// written; with Preflight set, each document is checked before it is
+// written. Audit records carry the fingerprint of the respective file. If
+// the audit record of a written file fails, the item fails, but the file
+// and its reference are kept.
+func RenderFiles(ctx context.Context, seq iter.Seq[Payload], routing FileRouting, opts SeqOptions) (BatchResult, error) {
+	var result BatchResult
+	if err := opts.RenderOptions.Validate(); err != nil {
+		return result, err
+	}
+	name, err := routing.template()
+	if err != nil {
+		return result, err
+	}
+	warnings := opts.warnings()
+	height := Millimeter(297)
+	if opts.SlipOnly {
+		height = 105
+	}
+	names := make(map[string]bool)
+	i := 0
+	for data := range seq {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if i > 0 && opts.QRImage != nil {
+			return result, errQRImageForSeveral
+		}
+		item, ok := opts.checkPayload(ctx, data, i, warnings)
+		i++
+		if item.Err == nil {
+			item.Name, item.Err = executeFileName(name, data, item.Index)
+		}
+		if item.Err == nil && names[item.Name] {
+			item.Err = fmt.Errorf("Duplicate output name: %v", item.Name)
+		}
+		written := false
+		if item.Err == nil {
+			names[item.Name] = true
+			written, item.Err = renderFile(ctx, data, routing.storage(data), item.Name, height, opts)
+		}
+		if item.Err != nil && ok && !written {
+			item.Err = opts.release(ctx, item.Err, data)
+		}
+		if item.Err != nil {
+			result.Items = append(result.Items, item)
+			result.Failed++
+			continue
+		}
+		item.Page = 0
+		result.Items = append(result.Items, item)
+		result.Rendered++
+	}
+	return result, nil
+}
+
+// renderFile renders a single invoice and writes it to s. It reports
+// whether the file was written, which may be the case even if the audit
+// record fails.
+func renderFile(ctx context.Context, data Payload, s Storage, name string,
+	height Millimeter, opts SeqOptions) (bool, error) {
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, height.Unit())
+	invoice, err := RenderInvoiceWithProof(NewPDFRenderer(canvas), data,
+		opts.RenderOptions, 0, 210, height, 0, 0)
+	canvas.Close()
+	if err != nil {
+		return false, err
+	}
+	if opts.Preflight {
+		proof := Proof{Invoices: []ProofInvoice{invoice}}
+		if err := Preflight(proof); err != nil {
+			return false, err
+		}
+	}
+	var record AuditRecord
+	if opts.Audit != nil {
+		if record, err = newAuditRecord(data, opts.RenderOptions, 0); err != nil {
+			return false, err
+		}
+	}
+	w, err := s.Create(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	h := sha256.New()
+	if err := doc.Encode(io.MultiWriter(w, h)); err != nil {
+		w.Close()
+		return false, err
+	}
+	if err := w.Close(); err != nil {
+		return false, err
+	}
+	if opts.Audit == nil {
+		return true, nil
+	}
+	record.Output = fingerprint(h)
+	return true, opts.Audit.Record(ctx, record)
+}
@@ -0,0 +1,307 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"iter"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenderSeq(t *testing.T) {
+	payloads := []Payload{examplePayload1, examplePayload2, examplePayload3}
+	var buffer bytes.Buffer
+	opts := SeqOptions{
+		RenderOptions: RenderOptions{Language: "de", Separator: ScissorsSeparator},
+	}
+	if err := RenderSeq(slices.Values(payloads), &buffer, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buffer.String(), "%PDF") {
+		t.Errorf("Expected PDF document, got %q", buffer.String())
+	}
+}
+
+func TestRenderSeqStopsAtInvalidPayload(t *testing.T) {
+	consumed := 0
+	seq := iter.Seq[Payload](func(yield func(Payload) bool) {
+		for _, p := range []Payload{examplePayload1, Payload{}, examplePayload3} {
+			consumed++
+			if !yield(p) {
+				return
+			}
+		}
+	})
+	err := RenderSeq(seq, ioutil.Discard, SeqOptions{
+		RenderOptions: RenderOptions{Language: "en"},
+		SlipOnly:      true,
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "Payload 1: ") {
+		t.Errorf("Expected error for payload 1, got %v", err)
+	}
+	if consumed != 2 {
+		t.Errorf("Expected 2 payloads to be consumed, got %v", consumed)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Account.IBAN" {
+		t.Errorf("Expected validation error, got %#v", err)
+	}
+}
+
+func TestRenderSeqDuplicateReferences(t *testing.T) {
+	payloads := []Payload{examplePayload2, exampleCreditorReference, examplePayload2}
+	opts := SeqOptions{
+		RenderOptions: RenderOptions{Language: "de"},
+		Registry:      new(MemoryRegistry),
+	}
+	err := RenderSeq(slices.Values(payloads), ioutil.Discard, opts)
+	if err == nil || !strings.HasPrefix(err.Error(), "Payload 2: Duplicate payment reference") {
+		t.Errorf("Expected duplicate reference for payload 2, got %v", err)
+	}
+	// No document was written, so the references are released again.
+	if err := RegisterReference(context.Background(), opts.Registry, examplePayload2); err != nil {
+		t.Errorf("Expected released reference, got: %v", err)
+	}
+
+	var flagged []int
+	opts = SeqOptions{
+		RenderOptions: RenderOptions{Language: "de"},
+		Registry:      new(MemoryRegistry),
+		OnDuplicate:   func(i int, reference string) { flagged = append(flagged, i) },
+	}
+	if err := RenderSeq(slices.Values(payloads), ioutil.Discard, opts); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(flagged, []int{2}) {
+		t.Errorf("Expected payload 2 to be flagged, got %v", flagged)
+	}
+}
+
+func TestRenderSeqPreflight(t *testing.T) {
+	payloads := []Payload{examplePayload1, examplePayload3}
+	opts := SeqOptions{
+		RenderOptions: RenderOptions{Language: "it", Separator: ScissorsSeparator},
+		Preflight:     true,
+	}
+	var buffer bytes.Buffer
+	if err := RenderSeq(slices.Values(payloads), &buffer, opts); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	opts.Draft = true
+	opts.Layout.OffsetY = 20
+	buffer.Reset()
+	err := RenderSeq(slices.Values(payloads), &buffer, opts)
+	// The draft is grey, above the bottom and has the banner in the quiet
+	// zone.
+	if perr, ok := err.(*PreflightError); !ok || len(perr.Violations) != 6 {
+		t.Errorf("Expected six violations, got: %v", err)
+	}
+	if buffer.Len() != 0 {
+		t.Error("Expected no output after failed preflight")
+	}
+}
+
+func TestRenderSeqAudit(t *testing.T) {
+	payloads := []Payload{examplePayload1, examplePayload2}
+	audit := new(MemoryAudit)
+	opts := SeqOptions{
+		RenderOptions: RenderOptions{Language: "de"},
+		Audit:         audit,
+	}
+	var buffer bytes.Buffer
+	if err := RenderSeq(slices.Values(payloads), &buffer, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	records := audit.Records()
+	if len(records) != 2 {
+		t.Fatalf("Expected two records, got %v", len(records))
+	}
+	sum := sha256.Sum256(buffer.Bytes())
+	for i, record := range records {
+		var payload bytes.Buffer
+		if err := payloads[i].Serialize(&payload); err != nil {
+			t.Fatal(err)
+		}
+		if record.Page != i || record.Payload != payload.String() {
+			t.Errorf("Item %v: unexpected record %#v", i, record)
+		}
+		if record.Options != opts.RenderOptions.Hash() {
+			t.Errorf("Item %v: unexpected options hash %v", i, record.Options)
+		}
+		if record.Output != hex.EncodeToString(sum[:]) {
+			t.Errorf("Item %v: unexpected output fingerprint %v", i, record.Output)
+		}
+	}
+}
+
+func TestRenderBatch(t *testing.T) {
+	payloads := []Payload{examplePayload2, Payload{}, examplePayload3, examplePayload2}
+	opts := SeqOptions{
+		RenderOptions: RenderOptions{Language: "de-CH", FallbackLanguage: "de"},
+		Registry:      new(MemoryRegistry),
+		OnDuplicate:   func(i int, reference string) {},
+	}
+	var buffer bytes.Buffer
+	result, err := RenderBatch(slices.Values(payloads), &buffer, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Rendered != 3 || result.Failed != 1 || len(result.Items) != 4 {
+		t.Errorf("Unexpected totals: %v rendered, %v failed", result.Rendered, result.Failed)
+	}
+	pages := []int{}
+	for _, item := range result.Items {
+		pages = append(pages, item.Page)
+	}
+	if expected := []int{0, -1, 1, 2}; !slices.Equal(expected, pages) {
+		t.Errorf("Expected pages %v, got %v", expected, pages)
+	}
+	failures := result.Failures()
+	if len(failures) != 1 || failures[0].Index != 1 ||
+		failures[0].Err.Error() != "No account specified" {
+		t.Errorf("Unexpected failures: %#v", failures)
+	}
+	var verr *ValidationError
+	if !errors.As(failures[0].Err, &verr) || verr.Field != "Account.IBAN" {
+		t.Errorf("Expected validation error, got %#v", failures[0].Err)
+	}
+	// Every item warns about the fallback language; the last one also
+	// about its duplicate reference.
+	if n := len(result.Items[0].Warnings); n != 1 {
+		t.Errorf("Expected one warning for item 0, got %v", n)
+	}
+	if n := len(result.Items[3].Warnings); n != 2 {
+		t.Errorf("Expected two warnings for item 3, got %v", n)
+	}
+	if !strings.HasPrefix(buffer.String(), "%PDF") {
+		t.Error("Expected PDF document")
+	}
+
+	opts.FallbackLanguage = "sv"
+	if _, err := RenderBatch(slices.Values(payloads), ioutil.Discard, opts); err == nil {
+		t.Error("Expected error due to unsupported fallback language")
+	}
+}
+
+func TestRenderBatchWithoutInvoices(t *testing.T) {
+	opts := SeqOptions{RenderOptions: RenderOptions{Language: "de"}}
+	var buffer bytes.Buffer
+	result, err := RenderBatch(slices.Values([]Payload{{}, {}}), &buffer, opts)
+	if err != ErrNoInvoices || result.Failed != 2 {
+		t.Errorf("Expected no invoices, got: %+v, %v", result, err)
+	}
+	if err := RenderSeq(slices.Values([]Payload{}), &buffer, opts); err != ErrNoInvoices {
+		t.Errorf("Expected no invoices, got: %v", err)
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no output, got %v bytes", buffer.Len())
+	}
+}
+
+func TestRenderFiles(t *testing.T) {
+	dir := t.TempDir()
+	payloads := []Payload{examplePayload2, exampleCreditorReference, Payload{}, examplePayload2}
+	audit := new(MemoryAudit)
+	routing := FileRouting{
+		Name:    "{{.Creditor.Name}}/{{.Reference}}.pdf",
+		Storage: DirStorage{Dir: dir},
+	}
+	opts := SeqOptions{RenderOptions: RenderOptions{Language: "de"}, Audit: audit}
+	result, err := RenderFiles(context.Background(), slices.Values(payloads), routing, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Rendered != 2 || result.Failed != 2 || result.Items[2].Err == nil {
+		t.Errorf("Unexpected totals: %v rendered, %v failed", result.Rendered, result.Failed)
+	}
+	if err := result.Items[3].Err; err == nil || !strings.HasPrefix(err.Error(), "Duplicate output name") {
+		t.Errorf("Expected duplicate output name, got: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "Robert Schneider AG", "210000000003139471430009017.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(b)
+	if records := audit.Records(); len(records) != 2 || records[0].Output != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected audit records: %#v", records)
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(dir, "Salvation Army Foundation Switzerland", "RF18539007547034.pdf")); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := RenderFiles(context.Background(), slices.Values(payloads), FileRouting{}, opts); err == nil {
+		t.Error("Expected error due to missing name template")
+	}
+}
+
+// failingAudit fails to record anything.
+type failingAudit struct{}
+
+func (failingAudit) Record(ctx context.Context, record AuditRecord) error {
+	return errors.New("Audit unavailable")
+}
+
+func TestRenderFilesKeepsWrittenReferences(t *testing.T) {
+	dir := t.TempDir()
+	registry := new(MemoryRegistry)
+	routing := FileRouting{Name: "{{.Reference}}.pdf", Storage: DirStorage{Dir: dir}}
+	opts := SeqOptions{RenderOptions: RenderOptions{Language: "de"}, Registry: registry, Audit: failingAudit{}}
+	result, err := RenderFiles(context.Background(), slices.Values([]Payload{examplePayload2}), routing, opts)
+	if err != nil || result.Failed != 1 || result.Items[0].Err.Error() != "Audit unavailable" {
+		t.Fatalf("Expected audit failure, got: %v %v", result, err)
+	}
+	// The file has been written, so its reference stays registered.
+	if _, err := ioutil.ReadFile(filepath.Join(dir, result.Items[0].Name)); err != nil {
+		t.Error(err)
+	}
+	if err := RegisterReference(context.Background(), registry, examplePayload2); !errors.Is(err, ErrDuplicateReference) {
+		t.Errorf("Expected reference to be kept, got: %v", err)
+	}
+}
+
+// failingStorage fails to create any file.
+type failingStorage struct{}
+
+func (failingStorage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	return nil, errors.New("Disk full")
+}
+
+func TestRenderFilesReleasesReferences(t *testing.T) {
+	registry := new(MemoryRegistry)
+	routing := FileRouting{Name: "{{.Reference}}.pdf", Storage: failingStorage{}}
+	opts := SeqOptions{RenderOptions: RenderOptions{Language: "de"}, Registry: registry}
+	payloads := []Payload{examplePayload2}
+	result, err := RenderFiles(context.Background(), slices.Values(payloads), routing, opts)
+	if err != nil || result.Failed != 1 {
+		t.Fatalf("Expected one failure, got: %v %v", result, err)
+	}
+	// A retry is not a duplicate.
+	routing.Storage = DirStorage{Dir: t.TempDir()}
+	result, err = RenderFiles(context.Background(), slices.Values(payloads), routing, opts)
+	if err != nil || result.Rendered != 1 {
+		t.Errorf("Expected retry to succeed, got: %v %v", result.Failures(), err)
+	}
+}
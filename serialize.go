@@ -16,19 +16,22 @@ package swissqr
 
 import (
 	"fmt"
-	"github.com/krepost/structref"
 	"io"
 	"strings"
 )
 
 // Serialize serializes the payload data to w in a form that can
 // be encoded in a Swiss QR Code. The payload is validated before
-// serialization.
+// serialization with the rules of its version.
 func (p Payload) Serialize(w io.Writer) error {
 	if err := p.Validate(); err != nil {
 		return err
 	}
-	io.WriteString(w, "SPC\r\n0200\r\n1\r\n") // Header.
+	rules, err := p.Rules()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "SPC\r\n%v\r\n1\r\n", rules.QRVersion) // Header.
 	if err := p.Account.Serialize(w); err != nil {
 		return err
 	}
@@ -56,11 +59,16 @@ func (p Payload) Serialize(w io.Writer) error {
 	if err := p.AdditionalInformation.Serialize(w); err != nil {
 		return err
 	}
-	io.WriteString(w, "\r\n")
-	if err := p.AlternativeProcedureParameters.Serialize(w); err != nil {
-		return err
+	// The optional fields after the trailer are omitted unless they are
+	// followed by alternative procedures.
+	if len(p.AlternativeProcedureParameters) == 0 {
+		return nil
 	}
-	return nil
+	if p.AdditionalInformation.BillInformation() == "" {
+		io.WriteString(w, "\r\n")
+	}
+	io.WriteString(w, "\r\n")
+	return p.AlternativeProcedureParameters.Serialize(w)
 }
 
 // Serialize serializes an account record.
@@ -80,7 +88,7 @@ func (e Entity) Serialize(w io.Writer) error {
 	if err := e.Address.Serialize(e.Name, w); err != nil {
 		return err
 	}
-	if _, err := io.WriteString(w, "\r\n"+e.CountryCode); err != nil {
+	if _, err := io.WriteString(w, "\r\n"+NormalizeCountryCode(e.CountryCode)); err != nil {
 		return err
 	}
 	return nil
@@ -108,7 +116,7 @@ func (sa StructuredAddress) Serialize(name string, w io.Writer) error {
 // It is assumed that the record is valid.
 func (pa PaymentAmount) Serialize(w io.Writer) error {
 	s := ""
-	if pa.Amount > 0.0 {
+	if pa.Amount > 0.0 || pa.Mode == AmountZero {
 		s = s + fmt.Sprintf("%.2f", pa.Amount)
 	}
 	s = s + "\r\n" + pa.Currency
@@ -120,32 +128,37 @@ func (pa PaymentAmount) Serialize(w io.Writer) error {
 // It is assumed that the record is valid.
 func (pr PaymentReference) Serialize(w io.Writer) error {
 	var err error = nil
-	switch ref := pr.Number.(type) {
-	case *structref.ReferenceNumber:
-		_, err = io.WriteString(w, "QRR\r\n"+ref.DigitalFormat())
-	case *structref.CreditorReference:
-		_, err = io.WriteString(w, "SCOR\r\n"+ref.DigitalFormat())
-	case nil:
+	switch pr.Type() {
+	case "QRR", "SCOR":
+		_, err = io.WriteString(w, pr.Type()+"\r\n"+pr.Number.DigitalFormat())
+	case "NON":
 		_, err = io.WriteString(w, "NON\r\n")
 	}
 	return err
 }
 
-// Serialize serializes additional payment information record.
-// It is assumed that the record is valid.
+// Serialize serializes additional payment information record. The bill
+// information is omitted if it is empty. It is assumed that the record is
+// valid.
 func (pi PaymentInformation) Serialize(w io.Writer) error {
-	s := pi.UnstructuredMessage + "\r\nEPD\r\n" + pi.StructuredMessage.ToString()
+	s := pi.UnstructuredMessage + "\r\nEPD"
+	if bi := pi.BillInformation(); bi != "" {
+		s = s + "\r\n" + bi
+	}
 	_, err := io.WriteString(w, s)
 	return err
 }
 
-// Serialize serializes alternative procedure parameters.
-// It is assumed that the parameters are valid.
+// Serialize serializes alternative procedure parameters, one line per
+// procedure. Like Validate, it rejects more than two procedures.
 func (vec AlternativeProcedures) Serialize(w io.Writer) error {
-	apJoin := []string{"", ""}
-	for i, ap := range vec {
-		apJoin[i] = ap.Procedure
+	if len(vec) > 2 {
+		return fmt.Errorf("Maximum two alternate payment schemes allowed: %v", vec)
+	}
+	procedures := make([]string, 0, len(vec))
+	for _, ap := range vec {
+		procedures = append(procedures, ap.Procedure)
 	}
-	_, err := io.WriteString(w, strings.Join(apJoin, "\r\n"))
+	_, err := io.WriteString(w, strings.Join(procedures, "\r\n"))
 	return err
 }
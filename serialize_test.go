@@ -16,7 +16,8 @@ package swissqr
 
 import (
 	"bytes"
-	"github.com/krepost/structref"
+	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -100,6 +101,21 @@ func TestSerializeStructuredAddress(t *testing.T) {
 	}
 }
 
+func TestSerializeNormalizedCountryCode(t *testing.T) {
+	data := Entity{
+		Name:        "Test Name",
+		Address:     CombinedAddress{AddressLine2: "8000 Zürich"},
+		CountryCode: " li",
+	}
+	var buffer bytes.Buffer
+	if err := data.Serialize(&buffer); err != nil {
+		t.Errorf("Could not serialize payload: %v", err)
+	}
+	if actual := buffer.String(); !strings.HasSuffix(actual, "\r\nLI") {
+		t.Errorf("Expected country code LI, got: %#v", actual)
+	}
+}
+
 func TestSerializePaymentAmount(t *testing.T) {
 	data := PaymentAmount{
 		Amount:   1234.5678,
@@ -116,6 +132,26 @@ func TestSerializePaymentAmount(t *testing.T) {
 	}
 }
 
+func TestSerializePaymentAmountModes(t *testing.T) {
+	var testdata = []struct {
+		amount   PaymentAmount
+		expected string
+	}{
+		{PaymentAmount{Currency: CHF}, "\r\nCHF"},
+		{PaymentAmount{Currency: CHF, Mode: AmountZero}, "0.00\r\nCHF"},
+		{PaymentAmount{Currency: EUR, Mode: AmountOmit}, "\r\nEUR"},
+	}
+	for _, item := range testdata {
+		var buffer bytes.Buffer
+		if err := item.amount.Serialize(&buffer); err != nil {
+			t.Errorf("Could not serialize payload: %v", err)
+		}
+		if actual := buffer.String(); item.expected != actual {
+			t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", item.expected, actual)
+		}
+	}
+}
+
 func TestSerializePaymentReferenceEmpty(t *testing.T) {
 	data := PaymentReference{}
 	var buffer bytes.Buffer
@@ -132,7 +168,7 @@ func TestSerializePaymentReferenceEmpty(t *testing.T) {
 func TestSerializePaymentReferenceESR(t *testing.T) {
 	num := "210000000003139471430009017"
 	data := PaymentReference{
-		Number: structref.NewReferenceNumberOrDie(num),
+		Number: ParseQRReferenceOrDie(num),
 	}
 	var buffer bytes.Buffer
 	if err := data.Serialize(&buffer); err != nil {
@@ -148,7 +184,7 @@ func TestSerializePaymentReferenceESR(t *testing.T) {
 func TestSerializePaymentReferenceISO(t *testing.T) {
 	num := "RF8312345678912345678912"
 	data := PaymentReference{
-		Number: structref.NewCreditorReferenceOrDie(num),
+		Number: ParseCreditorReferenceOrDie(num),
 	}
 	var buffer bytes.Buffer
 	if err := data.Serialize(&buffer); err != nil {
@@ -185,6 +221,7 @@ func TestSerializeAlternativeProcedures(t *testing.T) {
 		},
 		AlternativeProcedure{
 			Label:     "Label 2",
+			Labels:    map[string]string{"fr": "Libellé 2"},
 			Procedure: "Procedure 2",
 		},
 	}
@@ -199,6 +236,20 @@ func TestSerializeAlternativeProcedures(t *testing.T) {
 	}
 }
 
+func TestLocalizedLabel(t *testing.T) {
+	ap := AlternativeProcedure{
+		Label:     "Name AV1",
+		Labels:    map[string]string{"fr": "Nom AV1"},
+		Procedure: "UV;UltraPay005;12345",
+	}
+	if label := ap.LocalizedLabel("fr"); label != "Nom AV1" {
+		t.Errorf("Expected localized label, got %v", label)
+	}
+	if label := ap.LocalizedLabel("de"); label != "Name AV1" {
+		t.Errorf("Expected default label, got %v", label)
+	}
+}
+
 func TestSerializePayloadExample1(t *testing.T) {
 	data := examplePayload1
 	var buffer bytes.Buffer
@@ -235,9 +286,7 @@ func TestSerializePayloadExample1(t *testing.T) {
 		"NON\r\n" +
 		"\r\n" +
 		"Rechnung Nr. 3139 für Gartenarbeiten und Entsorgung Schnittmaterial\r\n" +
-		"EPD\r\n" +
-		"\r\n" +
-		"\r\n"
+		"EPD"
 	actual := buffer.String()
 	if expected != actual {
 		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, actual)
@@ -278,11 +327,51 @@ func TestSerializePayloadExample3(t *testing.T) {
 		"NON\r\n" +
 		"\r\n" +
 		"Donation to the Winterfest Campaign\r\n" +
-		"EPD\r\n" +
-		"\r\n" +
-		"\r\n"
+		"EPD"
 	actual := buffer.String()
 	if expected != actual {
 		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, actual)
 	}
 }
+
+func TestSerializeOptionalTrailingFields(t *testing.T) {
+	procedures := AlternativeProcedures{
+		AlternativeProcedure{Label: "Name AV1", Procedure: "UV;UltraPay005;12345"},
+	}
+	withProcedure := examplePayload3
+	withProcedure.AlternativeProcedureParameters = procedures
+	withBoth := withProcedure
+	withBoth.AdditionalInformation.StructuredMessage = BillInformation{CustomerReference: "ref"}
+	var testdata = []struct {
+		data   Payload
+		suffix string
+	}{
+		{examplePayload3, "Donation to the Winterfest Campaign\r\nEPD"},
+		{withProcedure, "Donation to the Winterfest Campaign\r\nEPD\r\n\r\nUV;UltraPay005;12345"},
+		{withBoth, "Donation to the Winterfest Campaign\r\nEPD\r\n//S1/20/ref\r\nUV;UltraPay005;12345"},
+	}
+	for i, data := range testdata {
+		var buffer bytes.Buffer
+		if err := data.data.Serialize(&buffer); err != nil {
+			t.Errorf("Item %v: could not serialize payload: %v", i, err)
+		}
+		if !strings.HasSuffix(buffer.String(), data.suffix) {
+			t.Errorf("Item %v: expected suffix %#v, got %#v", i, data.suffix, buffer.String())
+		}
+	}
+
+	tooMany := append(procedures, procedures[0], procedures[0])
+	if err := tooMany.Serialize(ioutil.Discard); err == nil {
+		t.Error("Expected error due to too many alternative procedures")
+	}
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	var buffer bytes.Buffer
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		if err := examplePayload2.Serialize(&buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"strings"
+	"time"
+)
+
+// OpenInvoice is an unpaid invoice listed on a statement.
+type OpenInvoice struct {
+	Number  string
+	Date    time.Time
+	DueDate time.Time
+	Amount  float64
+}
+
+// StatementReference selects how the QR bill of a statement refers to the
+// invoices it settles.
+type StatementReference int
+
+const (
+	// NewStatementReference uses the reference of Statement.Bill, which
+	// identifies the statement as a whole, e.g. a QR reference built from
+	// a statement number.
+	NewStatementReference StatementReference = iota
+
+	// InvoiceListReference uses no reference and appends the numbers of
+	// all invoices to the unstructured message. This requires a regular
+	// IBAN, since a QR-IBAN always requires a QR reference.
+	InvoiceListReference
+)
+
+// Statement combines several open invoices of the same debtor into one
+// letter with a single QR bill for the total, e.g. for reminders.
+type Statement struct {
+	Invoices []OpenInvoice
+
+	// Bill contains all QR bill data except the amount.
+	Bill Payload
+
+	// Reference selects the reference strategy of the QR bill.
+	Reference StatementReference
+}
+
+// Total returns the sum of all open invoices, rounded to 0.01.
+func (s Statement) Total() float64 {
+	sum := 0.0
+	for _, invoice := range s.Invoices {
+		sum += invoice.Amount
+	}
+	return roundTo(sum, 0.01)
+}
+
+// Payload returns the QR bill payload with the amount set to Total() and
+// the reference set according to the reference strategy.
+func (s Statement) Payload() Payload {
+	p := s.Bill
+	p.CurrencyAmount.Amount = s.Total()
+	if s.Reference == InvoiceListReference {
+		p.Reference = PaymentReference{}
+		numbers := make([]string, 0, len(s.Invoices))
+		for _, invoice := range s.Invoices {
+			numbers = append(numbers, invoice.Number)
+		}
+		message := strings.Join(numbers, ", ")
+		if m := p.AdditionalInformation.UnstructuredMessage; m != "" {
+			message = m + " " + message
+		}
+		p.AdditionalInformation.UnstructuredMessage = message
+	}
+	return p
+}
+
+// Table returns the open invoices and their total as a table for a Letter.
+func (s Statement) Table(language Language) (LetterTable, error) {
+	if err := checkLanguage(language); err != nil {
+		return LetterTable{}, err
+	}
+	labels, ok := statementLabels[language]
+	if !ok {
+		labels = statementLabels[EN] // Registered with RegisterLanguage.
+	}
+	format := displayFormat(language)
+	dateFormat := localized(DateFormatHeading, language)
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(dateFormat)
+	}
+	table := LetterTable{
+		Header: []string{labels.invoice, labels.date, labels.dueDate, labels.amount},
+	}
+	for _, invoice := range s.Invoices {
+		table.Rows = append(table.Rows, []string{
+			invoice.Number,
+			date(invoice.Date),
+			date(invoice.DueDate),
+			format.amount(invoice.Amount),
+		})
+	}
+	table.Totals = append(table.Totals, []string{
+		labels.total + " " + s.Bill.CurrencyAmount.Currency, "", "", format.amount(s.Total()),
+	})
+	return table, nil
+}
+
+// Letter returns a copy of letter whose table and QR bill are taken from
+// the statement.
+func (s Statement) Letter(letter Letter, language Language) (Letter, error) {
+	table, err := s.Table(language)
+	if err != nil {
+		return Letter{}, err
+	}
+	letter.Table = table
+	letter.Bill = s.Payload()
+	return letter, nil
+}
+
+// statementLabels contains the localized labels of the statement table.
+var statementLabels = map[Language]struct {
+	invoice, date, dueDate, amount, total string
+}{
+	DE: {"Rechnung", "Datum", "Fällig am", "Betrag", "Total"},
+	FR: {"Facture", "Date", "Échéance", "Montant", "Total"},
+	IT: {"Fattura", "Data", "Scadenza", "Importo", "Totale"},
+	EN: {"Invoice", "Date", "Due date", "Amount", "Total"},
+	RM: {"Quint", "Data", "Scadenza", "Import", "Total"},
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var exampleStatement = Statement{
+	Invoices: []OpenInvoice{
+		{
+			Number:  "2019-101",
+			Date:    time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC),
+			DueDate: time.Date(2019, 3, 31, 0, 0, 0, 0, time.UTC),
+			Amount:  1491.40,
+		},
+		{
+			Number:  "2019-117",
+			Date:    time.Date(2019, 4, 2, 0, 0, 0, 0, time.UTC),
+			DueDate: time.Date(2019, 5, 2, 0, 0, 0, 0, time.UTC),
+			Amount:  250.05,
+		},
+	},
+	Bill: examplePayload3,
+}
+
+func TestStatementTable(t *testing.T) {
+	table, err := exampleStatement.Table("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := LetterTable{
+		Header: []string{"Rechnung", "Datum", "Fällig am", "Betrag"},
+		Rows: [][]string{
+			{"2019-101", "01.03.2019", "31.03.2019", "1 491.40"},
+			{"2019-117", "02.04.2019", "02.05.2019", "250.05"},
+		},
+		Totals: [][]string{{"Total CHF", "", "", "1 741.45"}},
+	}
+	if !reflect.DeepEqual(expected, table) {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, table)
+	}
+	if _, err := exampleStatement.Table("xx"); err == nil {
+		t.Error("Expected error due to unsupported language")
+	}
+}
+
+func TestStatementPayload(t *testing.T) {
+	p := exampleStatement.Payload()
+	if p.CurrencyAmount.Amount != 1741.45 {
+		t.Errorf("Unexpected amount: %v", p.CurrencyAmount.Amount)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	statement := exampleStatement
+	statement.Bill.AdditionalInformation.UnstructuredMessage = "Rechnungen"
+	statement.Reference = InvoiceListReference
+	p = statement.Payload()
+	if expected := "Rechnungen 2019-101, 2019-117"; p.AdditionalInformation.UnstructuredMessage != expected {
+		t.Errorf("Expected %q, got %q", expected, p.AdditionalInformation.UnstructuredMessage)
+	}
+	if p.Reference.Number != nil {
+		t.Errorf("Expected no reference, got %v", p.Reference.Number)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
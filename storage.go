@@ -0,0 +1,81 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+)
+
+// Storage receives generated output, such as PDF files and their sidecar
+// files. Names are slash-separated relative paths like “2019/05/3139.pdf”.
+// Implementations must be safe for concurrent use.
+type Storage interface {
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+}
+
+// ObjectUploader is implemented by thin wrappers around the clients of
+// object stores such as Amazon S3 or Google Cloud Storage, which keeps
+// their SDKs out of this package. Upload stores body under key.
+type ObjectUploader interface {
+	Upload(ctx context.Context, key string, contentType string, body io.Reader) error
+}
+
+// ObjectStorage stores output in an object store. Output is buffered in
+// memory and uploaded when the writer is closed. The content type is
+// derived from the file extension.
+type ObjectStorage struct {
+	Uploader ObjectUploader
+
+	// Prefix is prepended to all names, e.g. “invoices/”.
+	Prefix string
+}
+
+// Create returns a writer that uploads its content on Close.
+func (s ObjectStorage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	if err := checkStorageName(name); err != nil {
+		return nil, err
+	}
+	return &objectWriter{ctx: ctx, storage: s, key: s.Prefix + name}, nil
+}
+
+type objectWriter struct {
+	bytes.Buffer
+	ctx     context.Context
+	storage ObjectStorage
+	key     string
+}
+
+func (ow *objectWriter) Close() error {
+	contentType := mime.TypeByExtension(path.Ext(ow.key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return ow.storage.Uploader.Upload(ow.ctx, ow.key, contentType, &ow.Buffer)
+}
+
+// checkStorageName rejects names that would escape the storage root.
+func checkStorageName(name string) error {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, "\\") ||
+		path.Clean(name) != name || strings.HasPrefix(name, "../") || name == ".." {
+		return fmt.Errorf("Invalid output name: %#v", name)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+// DirStorage stores output as files below a local directory. Missing
+// subdirectories are created.
+type DirStorage struct {
+	Dir string
+}
+
+// Create creates the file name below the storage directory.
+func (ds DirStorage) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	if err := checkStorageName(name); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p := filepath.Join(ds.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(p)
+}
+
+// WriteDocument encodes doc and stores it under name.
+func WriteDocument(ctx context.Context, s Storage, name string, doc *pdf.Document) error {
+	w, err := s.Create(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := doc.Encode(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
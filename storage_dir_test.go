@@ -0,0 +1,57 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(js && wasm)
+
+package swissqr
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/krepost/gopdf/pdf"
+)
+
+func TestDirStorage(t *testing.T) {
+	dir := t.TempDir()
+	doc := pdf.New()
+	canvas := doc.NewPage(21.0*pdf.Cm, 10.5*pdf.Cm)
+	if err := DrawInvoice(canvas, examplePayload1, "de"); err != nil {
+		t.Fatal(err)
+	}
+	canvas.Close()
+	if err := WriteDocument(context.Background(), DirStorage{Dir: dir}, "2019/05/3139.pdf", doc); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "2019", "05", "3139.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "%PDF") {
+		t.Errorf("Expected PDF file, got %q", b)
+	}
+}
+
+func TestStorageInvalidNames(t *testing.T) {
+	storage := DirStorage{Dir: os.TempDir()}
+	for _, name := range []string{"", "/etc/passwd", "../x.pdf", "a/../../x.pdf", "a\\b.pdf", "a//b.pdf"} {
+		if _, err := storage.Create(context.Background(), name); err == nil {
+			t.Errorf("Expected error for name %#v", name)
+		}
+	}
+}
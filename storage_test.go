@@ -0,0 +1,49 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+type fakeUploader map[string]string
+
+func (fu fakeUploader) Upload(ctx context.Context, key string, contentType string, body io.Reader) error {
+	b, err := ioutil.ReadAll(body)
+	fu[key] = contentType + ":" + string(b)
+	return err
+}
+
+func TestObjectStorage(t *testing.T) {
+	uploader := fakeUploader{}
+	storage := ObjectStorage{Uploader: uploader, Prefix: "invoices/"}
+	w, err := storage.Create(context.Background(), "3139.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "{}")
+	if _, ok := uploader["invoices/3139.json"]; ok {
+		t.Error("Expected upload to happen on Close only")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := "application/json:{}", uploader["invoices/3139.json"]; expected != actual {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+}
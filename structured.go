@@ -16,7 +16,6 @@ package swissqr
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 )
@@ -99,65 +98,78 @@ type PaymentConditions []PaymentCondition
 
 // Validate valides a given BillInformation.
 func (bi BillInformation) Validate() error {
-	if err := ValidateCharacterSet(bi.InvoiceNumber); err != nil {
-		return err
+	return firstError(bi.violations(SpecVersion20))
+}
+
+func (bi BillInformation) violations(v SpecVersion) []error {
+	var errs []error
+	if err := v.ValidateCharacterSet(bi.InvoiceNumber); err != nil {
+		errs = append(errs, inField("InvoiceNumber", err))
 	}
 	if !bi.InvoiceDate.End.IsZero() {
-		return fmt.Errorf("Invoice date may not have an end date: %v", bi.InvoiceDate.End)
+		errs = append(errs, validationError("InvoiceDate", CodeNotAllowed, bi.InvoiceDate.End,
+			"Invoice date may not have an end date: %v", bi.InvoiceDate.End))
 	}
-	if err := ValidateCharacterSet(bi.CustomerReference); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(bi.CustomerReference); err != nil {
+		errs = append(errs, inField("CustomerReference", err))
 	}
-	if match, _ := regexp.MatchString("^[0-9]*$", bi.VATNumber); !match {
-		return fmt.Errorf("VAT number may only contain digits 0-9: %v", bi.VATNumber)
+	if !isDigits(bi.VATNumber) {
+		errs = append(errs, validationError("VATNumber", CodeInvalidFormat, bi.VATNumber,
+			"VAT number may only contain digits 0-9: %v", bi.VATNumber))
 	}
 	if !bi.VATDates.Date.IsZero() {
 		if !bi.VATDates.End.IsZero() {
 			if !bi.VATDates.End.After(bi.VATDates.Date) {
-				return fmt.Errorf("End date must come after start date: %v versus %v",
-					bi.VATDates.Date, bi.VATDates.End)
+				errs = append(errs, validationError("VATDates", CodeInvalidValue, bi.VATDates.End,
+					"End date must come after start date: %v versus %v", bi.VATDates.Date, bi.VATDates.End))
 			}
 		}
 	}
-	for _, rate := range bi.VATRates {
-		if rate.Amount < 0.0 {
-			return fmt.Errorf("VAT amount may not be negative: %v", rate)
+	errs = appendInField(errs, "VATRates", bi.VATRates.violations())
+	errs = appendInField(errs, "VATImportTaxRates", bi.VATImportTaxRates.violations())
+	for i, condition := range bi.Conditions {
+		if condition.DiscountPercent < 0.0 {
+			errs = append(errs, validationError(fmt.Sprintf("Conditions[%d].DiscountPercent", i), CodeInvalidValue,
+				condition.DiscountPercent, "Discount may not be negative: %v", condition))
 		}
-		if rate.RatePercent < 0.0 {
-			return fmt.Errorf("VAT tax rate may not be negative: %v", rate)
+		if condition.NumberOfDays < 0 {
+			errs = append(errs, validationError(fmt.Sprintf("Conditions[%d].NumberOfDays", i), CodeInvalidValue,
+				condition.NumberOfDays, "Number of days may not be negative: %v", condition))
 		}
 	}
-	for _, rate := range bi.VATImportTaxRates {
+	return errs
+}
+
+// violations checks that amounts and rates are not negative.
+func (t TaxRates) violations() []error {
+	var errs []error
+	for i, rate := range t {
 		if rate.Amount < 0.0 {
-			return fmt.Errorf("VAT amount may not be negative: %v", rate)
+			errs = append(errs, validationError(fmt.Sprintf("[%d].Amount", i), CodeInvalidValue, rate.Amount,
+				"VAT amount may not be negative: %v", rate))
 		}
 		if rate.RatePercent < 0.0 {
-			return fmt.Errorf("VAT tax rate may not be negative: %v", rate)
+			errs = append(errs, validationError(fmt.Sprintf("[%d].RatePercent", i), CodeInvalidValue, rate.RatePercent,
+				"VAT tax rate may not be negative: %v", rate))
 		}
 	}
-	for _, condition := range bi.Conditions {
-		if condition.DiscountPercent < 0.0 {
-			return fmt.Errorf("Discount may not be negative: %v", condition)
-		}
-		if condition.NumberOfDays < 0 {
-			return fmt.Errorf("Number of days may not be negative: %v", condition)
-		}
-	}
-	return nil
+	return errs
 }
 
 // ToString converts a given BillInformation to a string that can be added
-// to a Swiss QR invoice. It is assumed that the parameters are valid.
+// to a Swiss QR invoice. It is assumed that the parameters are valid. As
+// the S1 syntax requires, “/” and “\” in the free text fields are escaped
+// with “\”.
 func (bi BillInformation) ToString() string {
 	result := ""
 	if bi.InvoiceNumber != "" {
-		result = result + "/10/" + bi.InvoiceNumber
+		result = result + "/10/" + escapeBillInformation(bi.InvoiceNumber)
 	}
 	if s := bi.InvoiceDate.ToString(); s != "" {
 		result = result + "/11/" + s
 	}
 	if bi.CustomerReference != "" {
-		result = result + "/20/" + bi.CustomerReference
+		result = result + "/20/" + escapeBillInformation(bi.CustomerReference)
 	}
 	if bi.VATNumber != "" {
 		result = result + "/30/" + bi.VATNumber
@@ -180,6 +192,15 @@ func (bi BillInformation) ToString() string {
 	return result
 }
 
+// billInformationEscaper escapes the characters with a special meaning in
+// the S1 syntax.
+var billInformationEscaper = strings.NewReplacer(`\`, `\\`, `/`, `\/`)
+
+// escapeBillInformation escapes a value of bill information.
+func escapeBillInformation(s string) string {
+	return billInformationEscaper.Replace(s)
+}
+
 // ToString converts a date or a date interval to a string
 // that can be added to a structured bill information.
 func (d dates) ToString() string {
@@ -33,6 +33,19 @@ func TestEmptyStructuredMessage(t *testing.T) {
 	}
 }
 
+func TestStructuredMessageEscaping(t *testing.T) {
+	msg := BillInformation{InvoiceNumber: "2020/05", CustomerReference: `A\B`}
+	if err := msg.Validate(); err != nil {
+		t.Errorf("Expected no error; got %v", err)
+		return
+	}
+	expected := `//S1/10/2020\/05/20/A\\B`
+	actual := msg.ToString()
+	if expected != actual {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
 // Example 1 from version 1.2 (2018-11-23) of the document
 // “Syntaxdefinition der Rechnungsinformationen (S1) bei der QR-Rechnung”
 func TestStructuredExample1(t *testing.T) {
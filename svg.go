@@ -0,0 +1,69 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// WriteQRSVG writes the QR code for the given payload to w as an SVG image of
+// 46×46 mm, including the Swiss cross. Unlike CreateQR, the modules of the
+// code are written as vector paths, so that the image can be scaled freely;
+// this is useful for previews in a web browser.
+func WriteQRSVG(w io.Writer, data Payload) error {
+	return WriteQRSVGWithOptions(w, data, RenderOptions{})
+}
+
+// WriteQRSVGWithOptions writes the QR code like WriteQRSVG. If the options
+// select a draft, the QR code is drawn in grey and cannot be paid.
+func WriteQRSVGWithOptions(w io.Writer, data Payload, opts RenderOptions) error {
+	qrCode, err := qrModules(data, opts.Draft)
+	if err != nil {
+		return err
+	}
+	dark := "#000"
+	if opts.Draft {
+		grey := uint8(previewGrey * 0xff)
+		dark = fmt.Sprintf("#%02x%02x%02x", grey, grey, grey)
+	}
+	// The SVG uses the same coordinates as the 1086×1086 pixels image.
+	n := qrCode.Bounds().Dx()
+	module := 1086.0 / float64(n)
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="46mm" height="46mm" viewBox="0 0 1086 1086" shape-rendering="crispEdges">`)
+	fmt.Fprintf(out, `<rect width="1086" height="1086" fill="#fff"/><path fill="%s" d="`, dark)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if qrCode.At(x, y) != color.Black {
+				continue
+			}
+			fmt.Fprintf(out, "M%.3f %.3fh%.3fv%.3fh-%.3fz", float64(x)*module, float64(y)*module, module, module, module)
+		}
+	}
+	fmt.Fprintf(out, `"/>`)
+	for _, elem := range swissCross {
+		fill := "#fff"
+		if elem.color == color.Black {
+			fill = dark
+		}
+		r := elem.rect
+		fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), fill)
+	}
+	fmt.Fprintf(out, "</svg>\n")
+	return out.Flush()
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteQRSVG(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteQRSVG(&buffer, examplePayload1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	svg := buffer.String()
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="46mm" height="46mm"`) {
+		t.Errorf("Unexpected SVG header: %.80s", svg)
+	}
+	if !strings.Contains(svg, `<rect x="472" y="472" width="142" height="142" fill="#000"/>`) {
+		t.Error("Expected Swiss cross in SVG")
+	}
+	if err := xml.Unmarshal(buffer.Bytes(), new(struct{})); err != nil {
+		t.Errorf("Invalid XML: %v", err)
+	}
+}
+
+func TestWriteQRSVGFromInvalidData(t *testing.T) {
+	var buffer bytes.Buffer
+	err := WriteQRSVG(&buffer, Payload{CurrencyAmount: PaymentAmount{Currency: CHF}})
+	if err == nil {
+		t.Error("Expected error due to invalid payload")
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no output, got: %v", buffer.String())
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package swissqrpb contains the protocol buffer messages of package
+// swissqr, defined in swissqr.proto, together with functions converting
+// them to and from the Go structs.
+package swissqrpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative swissqr.proto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/krepost/swissqr"
+)
+
+// FromPayload converts a payload to its protocol buffer message. The
+// payload is not validated.
+func FromPayload(p swissqr.Payload) *Payload {
+	m := &Payload{
+		Creditor:         fromEntity(p.Creditor),
+		UltimateCreditor: fromEntity(p.UltimateCreditor),
+		Amount: &Amount{
+			Amount:   p.CurrencyAmount.Amount,
+			Currency: p.CurrencyAmount.Currency,
+			Mode:     AmountMode(p.CurrencyAmount.Mode),
+		},
+		UltimateDebtor:      fromEntity(p.UltimateDebtor),
+		Reference:           fromReference(p.Reference),
+		UnstructuredMessage: p.AdditionalInformation.UnstructuredMessage,
+		BillInformation:     fromBillInformation(p.AdditionalInformation.StructuredMessage),
+	}
+	if p.Account.IBAN != nil {
+		m.Account = p.Account.IBAN.Code
+	}
+	for _, ap := range p.AlternativeProcedureParameters {
+		m.AlternativeProcedures = append(m.AlternativeProcedures,
+			&AlternativeProcedure{Label: ap.Label, Procedure: ap.Procedure})
+	}
+	if p.Version != swissqr.SpecVersion20 {
+		m.Version = p.Version.String()
+	}
+	return m
+}
+
+// ToPayload converts a protocol buffer message to a payload. It returns an
+// error if the IBAN or the reference cannot be parsed, but does not
+// validate the payload; call Validate before using it.
+func ToPayload(m *Payload) (swissqr.Payload, error) {
+	var p swissqr.Payload
+	if m.GetAccount() != "" {
+		code, err := swissqr.ParseIBAN(m.GetAccount())
+		if err != nil {
+			return swissqr.Payload{}, err
+		}
+		p.Account = swissqr.AccountNumber{IBAN: code}
+	}
+	p.Creditor = toEntity(m.GetCreditor())
+	p.UltimateCreditor = toEntity(m.GetUltimateCreditor())
+	p.CurrencyAmount = swissqr.PaymentAmount{
+		Amount:   m.GetAmount().GetAmount(),
+		Currency: m.GetAmount().GetCurrency(),
+		Mode:     swissqr.AmountMode(m.GetAmount().GetMode()),
+	}
+	p.UltimateDebtor = toEntity(m.GetUltimateDebtor())
+	var err error
+	if p.Reference, err = toReference(m.GetReference()); err != nil {
+		return swissqr.Payload{}, err
+	}
+	p.AdditionalInformation.UnstructuredMessage = m.GetUnstructuredMessage()
+	p.AdditionalInformation.StructuredMessage = toBillInformation(m.GetBillInformation())
+	for _, ap := range m.GetAlternativeProcedures() {
+		p.AlternativeProcedureParameters = append(p.AlternativeProcedureParameters,
+			swissqr.AlternativeProcedure{Label: ap.GetLabel(), Procedure: ap.GetProcedure()})
+	}
+	if m.GetVersion() != "" {
+		if p.Version, err = swissqr.ParseSpecVersion(m.GetVersion()); err != nil {
+			return swissqr.Payload{}, err
+		}
+	}
+	return p, nil
+}
+
+func fromEntity(e swissqr.Entity) *Entity {
+	if e.Name == "" && e.Address == nil && e.CountryCode == "" {
+		return nil
+	}
+	m := &Entity{Name: e.Name, CountryCode: e.CountryCode}
+	switch a := e.Address.(type) {
+	case swissqr.StructuredAddress:
+		m.Address = &Entity_StructuredAddress{&StructuredAddress{
+			StreetName:     a.StreetName,
+			BuildingNumber: a.BuildingNumber,
+			PostCode:       a.PostCode,
+			TownName:       a.TownName,
+		}}
+	case swissqr.CombinedAddress:
+		m.Address = &Entity_CombinedAddress{&CombinedAddress{
+			AddressLine1: a.AddressLine1,
+			AddressLine2: a.AddressLine2,
+		}}
+	}
+	return m
+}
+
+func toEntity(m *Entity) swissqr.Entity {
+	if m == nil {
+		return swissqr.Entity{}
+	}
+	e := swissqr.Entity{Name: m.GetName(), CountryCode: m.GetCountryCode()}
+	if a := m.GetStructuredAddress(); a != nil {
+		e.Address = swissqr.StructuredAddress{
+			StreetName:     a.GetStreetName(),
+			BuildingNumber: a.GetBuildingNumber(),
+			PostCode:       a.GetPostCode(),
+			TownName:       a.GetTownName(),
+		}
+	}
+	if a := m.GetCombinedAddress(); a != nil {
+		e.Address = swissqr.CombinedAddress{
+			AddressLine1: a.GetAddressLine1(),
+			AddressLine2: a.GetAddressLine2(),
+		}
+	}
+	return e
+}
+
+func fromReference(r swissqr.PaymentReference) *Reference {
+	switch r.Type() {
+	case "QRR":
+		return &Reference{Type: ReferenceType_REFERENCE_TYPE_QRR, Number: r.Number.DigitalFormat()}
+	case "SCOR":
+		return &Reference{Type: ReferenceType_REFERENCE_TYPE_SCOR, Number: r.Number.DigitalFormat()}
+	}
+	return nil
+}
+
+func toReference(m *Reference) (swissqr.PaymentReference, error) {
+	switch m.GetType() {
+	case ReferenceType_REFERENCE_TYPE_NON:
+		if m.GetNumber() != "" {
+			return swissqr.PaymentReference{}, fmt.Errorf("Reference must be empty for type NON: %v", m.GetNumber())
+		}
+		return swissqr.PaymentReference{}, nil
+	case ReferenceType_REFERENCE_TYPE_QRR:
+		ref, err := swissqr.ParseQRReference(m.GetNumber())
+		if err != nil {
+			return swissqr.PaymentReference{}, err
+		}
+		return swissqr.PaymentReference{Number: ref}, nil
+	case ReferenceType_REFERENCE_TYPE_SCOR:
+		ref, err := swissqr.ParseCreditorReference(m.GetNumber())
+		if err != nil {
+			return swissqr.PaymentReference{}, err
+		}
+		return swissqr.PaymentReference{Number: ref}, nil
+	}
+	return swissqr.PaymentReference{}, fmt.Errorf("Unknown reference type: %v", m.GetType())
+}
+
+func fromBillInformation(bi swissqr.BillInformation) *BillInformation {
+	m := &BillInformation{
+		InvoiceNumber:     bi.InvoiceNumber,
+		InvoiceDate:       fromDates(bi.InvoiceDate.Date, bi.InvoiceDate.End),
+		CustomerReference: bi.CustomerReference,
+		VatNumber:         bi.VATNumber,
+		VatDates:          fromDates(bi.VATDates.Date, bi.VATDates.End),
+		VatRates:          fromTaxRates(bi.VATRates),
+		VatImportTaxRates: fromTaxRates(bi.VATImportTaxRates),
+	}
+	for _, c := range bi.Conditions {
+		m.Conditions = append(m.Conditions, &PaymentCondition{
+			DiscountPercent: c.DiscountPercent,
+			NumberOfDays:    int32(c.NumberOfDays),
+		})
+	}
+	return m
+}
+
+func toBillInformation(m *BillInformation) swissqr.BillInformation {
+	bi := swissqr.BillInformation{
+		InvoiceNumber:     m.GetInvoiceNumber(),
+		CustomerReference: m.GetCustomerReference(),
+		VATNumber:         m.GetVatNumber(),
+		VATRates:          toTaxRates(m.GetVatRates()),
+		VATImportTaxRates: toTaxRates(m.GetVatImportTaxRates()),
+	}
+	bi.InvoiceDate.Date = toDate(m.GetInvoiceDate().GetDate())
+	bi.InvoiceDate.End = toDate(m.GetInvoiceDate().GetEnd())
+	bi.VATDates.Date = toDate(m.GetVatDates().GetDate())
+	bi.VATDates.End = toDate(m.GetVatDates().GetEnd())
+	for _, c := range m.GetConditions() {
+		bi.Conditions = append(bi.Conditions, swissqr.PaymentCondition{
+			DiscountPercent: c.GetDiscountPercent(),
+			NumberOfDays:    int(c.GetNumberOfDays()),
+		})
+	}
+	return bi
+}
+
+func fromDates(date, end time.Time) *Dates {
+	if date.IsZero() {
+		return nil
+	}
+	return &Dates{Date: fromDate(date), End: fromDate(end)}
+}
+
+func fromDate(t time.Time) *Date {
+	if t.IsZero() {
+		return nil
+	}
+	return &Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+}
+
+func toDate(m *Date) time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return time.Date(int(m.GetYear()), time.Month(m.GetMonth()), int(m.GetDay()), 0, 0, 0, 0, time.UTC)
+}
+
+func fromTaxRates(rates swissqr.TaxRates) []*TaxRate {
+	var m []*TaxRate
+	for _, r := range rates {
+		m = append(m, &TaxRate{RatePercent: r.RatePercent, Amount: r.Amount})
+	}
+	return m
+}
+
+func toTaxRates(m []*TaxRate) swissqr.TaxRates {
+	var rates swissqr.TaxRates
+	for _, r := range m {
+		rates = append(rates, swissqr.TaxRate{RatePercent: r.GetRatePercent(), Amount: r.GetAmount()})
+	}
+	return rates
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqrpb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krepost/swissqr"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRoundTrip(t *testing.T) {
+	payload := swissqr.Payload{
+		Account: swissqr.NewIBANOrDie("CH4431999123000889012"),
+		Creditor: swissqr.Entity{
+			Name: "Robert Schneider AG",
+			Address: swissqr.StructuredAddress{
+				StreetName:     "Rue du Lac",
+				BuildingNumber: "1268",
+				PostCode:       "2501",
+				TownName:       "Biel",
+			},
+			CountryCode: "CH",
+		},
+		CurrencyAmount: swissqr.PaymentAmount{Amount: 1949.75, Currency: swissqr.CHF},
+		UltimateDebtor: swissqr.Entity{
+			Name: "Pia-Maria Rutschmann-Schnyder",
+			Address: swissqr.CombinedAddress{
+				AddressLine1: "Grosse Marktgasse 28",
+				AddressLine2: "9400 Rorschach",
+			},
+			CountryCode: "CH",
+		},
+		Reference: swissqr.PaymentReference{
+			Number: swissqr.ParseQRReferenceOrDie("210000000003139471430009017"),
+		},
+		AdditionalInformation: swissqr.PaymentInformation{
+			UnstructuredMessage: "Auftrag vom 18.06.2020",
+			StructuredMessage: swissqr.BillInformation{
+				InvoiceNumber: "10201409",
+				InvoiceDate:   swissqr.OneDate(2019, time.May, 12),
+				VATNumber:     "106017086",
+				VATDates:      swissqr.StartAndEndDate(2018, time.May, 1, 2018, time.May, 31),
+				VATRates:      swissqr.TaxRates{{RatePercent: 7.7}},
+				Conditions:    swissqr.PaymentConditions{{DiscountPercent: 2, NumberOfDays: 10}},
+			},
+		},
+		AlternativeProcedureParameters: swissqr.AlternativeProcedures{
+			{Label: "Name AV1", Procedure: "UV;UltraPay005;12345"},
+		},
+	}
+	b, err := proto.Marshal(FromPayload(payload))
+	if err != nil {
+		t.Fatalf("Could not marshal message: %v", err)
+	}
+	var m Payload
+	if err := proto.Unmarshal(b, &m); err != nil {
+		t.Fatalf("Could not unmarshal message: %v", err)
+	}
+	decoded, err := ToPayload(&m)
+	if err != nil {
+		t.Fatalf("Could not convert message: %v", err)
+	}
+	var expected, actual bytes.Buffer
+	if err := payload.Serialize(&expected); err != nil {
+		t.Fatalf("Could not serialize payload: %v", err)
+	}
+	if err := decoded.Serialize(&actual); err != nil {
+		t.Fatalf("Could not serialize converted payload: %v", err)
+	}
+	if expected.String() != actual.String() {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected.String(), actual.String())
+	}
+	if decoded.AlternativeProcedureParameters[0].Label != "Name AV1" {
+		t.Errorf("Expected label, got %+v", decoded.AlternativeProcedureParameters)
+	}
+}
+
+func TestToPayloadErrors(t *testing.T) {
+	for i, test := range []struct {
+		input    *Payload
+		expected string
+	}{
+		{&Payload{Account: "CH00"}, "IBAN"},
+		{&Payload{Reference: &Reference{Number: "123"}}, "Reference must be empty for type NON: 123"},
+		{&Payload{Reference: &Reference{Type: 7}}, "Unknown reference type: 7"},
+	} {
+		if _, err := ToPayload(test.input); err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}
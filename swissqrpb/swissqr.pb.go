@@ -0,0 +1,1128 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The messages in this file mirror the struct Payload of package swissqr,
+// so that invoice data can be passed between services. Convert them with
+// swissqrpb.FromPayload and swissqrpb.ToPayload. Regenerate swissqr.pb.go
+// with “go generate” after changing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: swissqr.proto
+
+package swissqrpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AmountMode selects how a payment amount of zero is handled.
+type AmountMode int32
+
+const (
+	AmountMode_AMOUNT_MODE_BOX  AmountMode = 0
+	AmountMode_AMOUNT_MODE_ZERO AmountMode = 1
+	AmountMode_AMOUNT_MODE_OMIT AmountMode = 2
+)
+
+// Enum value maps for AmountMode.
+var (
+	AmountMode_name = map[int32]string{
+		0: "AMOUNT_MODE_BOX",
+		1: "AMOUNT_MODE_ZERO",
+		2: "AMOUNT_MODE_OMIT",
+	}
+	AmountMode_value = map[string]int32{
+		"AMOUNT_MODE_BOX":  0,
+		"AMOUNT_MODE_ZERO": 1,
+		"AMOUNT_MODE_OMIT": 2,
+	}
+)
+
+func (x AmountMode) Enum() *AmountMode {
+	p := new(AmountMode)
+	*p = x
+	return p
+}
+
+func (x AmountMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AmountMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_swissqr_proto_enumTypes[0].Descriptor()
+}
+
+func (AmountMode) Type() protoreflect.EnumType {
+	return &file_swissqr_proto_enumTypes[0]
+}
+
+func (x AmountMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AmountMode.Descriptor instead.
+func (AmountMode) EnumDescriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{0}
+}
+
+type ReferenceType int32
+
+const (
+	ReferenceType_REFERENCE_TYPE_NON  ReferenceType = 0
+	ReferenceType_REFERENCE_TYPE_QRR  ReferenceType = 1
+	ReferenceType_REFERENCE_TYPE_SCOR ReferenceType = 2
+)
+
+// Enum value maps for ReferenceType.
+var (
+	ReferenceType_name = map[int32]string{
+		0: "REFERENCE_TYPE_NON",
+		1: "REFERENCE_TYPE_QRR",
+		2: "REFERENCE_TYPE_SCOR",
+	}
+	ReferenceType_value = map[string]int32{
+		"REFERENCE_TYPE_NON":  0,
+		"REFERENCE_TYPE_QRR":  1,
+		"REFERENCE_TYPE_SCOR": 2,
+	}
+)
+
+func (x ReferenceType) Enum() *ReferenceType {
+	p := new(ReferenceType)
+	*p = x
+	return p
+}
+
+func (x ReferenceType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReferenceType) Descriptor() protoreflect.EnumDescriptor {
+	return file_swissqr_proto_enumTypes[1].Descriptor()
+}
+
+func (ReferenceType) Type() protoreflect.EnumType {
+	return &file_swissqr_proto_enumTypes[1]
+}
+
+func (x ReferenceType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReferenceType.Descriptor instead.
+func (ReferenceType) EnumDescriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{1}
+}
+
+// Payload is the content of a Swiss QR code.
+type Payload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Account is an IBAN or QR-IBAN in electronic format, without spaces.
+	Account  string  `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Creditor *Entity `protobuf:"bytes,2,opt,name=creditor,proto3" json:"creditor,omitempty"`
+	// UltimateCreditor is reserved for future use by the standard.
+	UltimateCreditor      *Entity                 `protobuf:"bytes,3,opt,name=ultimate_creditor,json=ultimateCreditor,proto3" json:"ultimate_creditor,omitempty"`
+	Amount                *Amount                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	UltimateDebtor        *Entity                 `protobuf:"bytes,5,opt,name=ultimate_debtor,json=ultimateDebtor,proto3" json:"ultimate_debtor,omitempty"`
+	Reference             *Reference              `protobuf:"bytes,6,opt,name=reference,proto3" json:"reference,omitempty"`
+	UnstructuredMessage   string                  `protobuf:"bytes,7,opt,name=unstructured_message,json=unstructuredMessage,proto3" json:"unstructured_message,omitempty"`
+	BillInformation       *BillInformation        `protobuf:"bytes,8,opt,name=bill_information,json=billInformation,proto3" json:"bill_information,omitempty"`
+	AlternativeProcedures []*AlternativeProcedure `protobuf:"bytes,9,rep,name=alternative_procedures,json=alternativeProcedures,proto3" json:"alternative_procedures,omitempty"`
+	// Version is the version of the implementation guidelines, e.g. "2.3";
+	// empty for version 2.0.
+	Version       string `protobuf:"bytes,10,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payload) Reset() {
+	*x = Payload{}
+	mi := &file_swissqr_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payload) ProtoMessage() {}
+
+func (x *Payload) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payload.ProtoReflect.Descriptor instead.
+func (*Payload) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payload) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Payload) GetCreditor() *Entity {
+	if x != nil {
+		return x.Creditor
+	}
+	return nil
+}
+
+func (x *Payload) GetUltimateCreditor() *Entity {
+	if x != nil {
+		return x.UltimateCreditor
+	}
+	return nil
+}
+
+func (x *Payload) GetAmount() *Amount {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Payload) GetUltimateDebtor() *Entity {
+	if x != nil {
+		return x.UltimateDebtor
+	}
+	return nil
+}
+
+func (x *Payload) GetReference() *Reference {
+	if x != nil {
+		return x.Reference
+	}
+	return nil
+}
+
+func (x *Payload) GetUnstructuredMessage() string {
+	if x != nil {
+		return x.UnstructuredMessage
+	}
+	return ""
+}
+
+func (x *Payload) GetBillInformation() *BillInformation {
+	if x != nil {
+		return x.BillInformation
+	}
+	return nil
+}
+
+func (x *Payload) GetAlternativeProcedures() []*AlternativeProcedure {
+	if x != nil {
+		return x.AlternativeProcedures
+	}
+	return nil
+}
+
+func (x *Payload) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// Entity is a creditor or debtor. An entity without name is empty.
+type Entity struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Types that are valid to be assigned to Address:
+	//
+	//	*Entity_StructuredAddress
+	//	*Entity_CombinedAddress
+	Address isEntity_Address `protobuf_oneof:"address"`
+	// CountryCode is the two-letter ISO 3166-1 country code.
+	CountryCode   string `protobuf:"bytes,4,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Entity) Reset() {
+	*x = Entity{}
+	mi := &file_swissqr_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entity) ProtoMessage() {}
+
+func (x *Entity) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entity.ProtoReflect.Descriptor instead.
+func (*Entity) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Entity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Entity) GetAddress() isEntity_Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Entity) GetStructuredAddress() *StructuredAddress {
+	if x != nil {
+		if x, ok := x.Address.(*Entity_StructuredAddress); ok {
+			return x.StructuredAddress
+		}
+	}
+	return nil
+}
+
+func (x *Entity) GetCombinedAddress() *CombinedAddress {
+	if x != nil {
+		if x, ok := x.Address.(*Entity_CombinedAddress); ok {
+			return x.CombinedAddress
+		}
+	}
+	return nil
+}
+
+func (x *Entity) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+type isEntity_Address interface {
+	isEntity_Address()
+}
+
+type Entity_StructuredAddress struct {
+	StructuredAddress *StructuredAddress `protobuf:"bytes,2,opt,name=structured_address,json=structuredAddress,proto3,oneof"`
+}
+
+type Entity_CombinedAddress struct {
+	CombinedAddress *CombinedAddress `protobuf:"bytes,3,opt,name=combined_address,json=combinedAddress,proto3,oneof"`
+}
+
+func (*Entity_StructuredAddress) isEntity_Address() {}
+
+func (*Entity_CombinedAddress) isEntity_Address() {}
+
+type StructuredAddress struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StreetName     string                 `protobuf:"bytes,1,opt,name=street_name,json=streetName,proto3" json:"street_name,omitempty"`
+	BuildingNumber string                 `protobuf:"bytes,2,opt,name=building_number,json=buildingNumber,proto3" json:"building_number,omitempty"`
+	PostCode       string                 `protobuf:"bytes,3,opt,name=post_code,json=postCode,proto3" json:"post_code,omitempty"`
+	TownName       string                 `protobuf:"bytes,4,opt,name=town_name,json=townName,proto3" json:"town_name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StructuredAddress) Reset() {
+	*x = StructuredAddress{}
+	mi := &file_swissqr_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StructuredAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructuredAddress) ProtoMessage() {}
+
+func (x *StructuredAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructuredAddress.ProtoReflect.Descriptor instead.
+func (*StructuredAddress) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StructuredAddress) GetStreetName() string {
+	if x != nil {
+		return x.StreetName
+	}
+	return ""
+}
+
+func (x *StructuredAddress) GetBuildingNumber() string {
+	if x != nil {
+		return x.BuildingNumber
+	}
+	return ""
+}
+
+func (x *StructuredAddress) GetPostCode() string {
+	if x != nil {
+		return x.PostCode
+	}
+	return ""
+}
+
+func (x *StructuredAddress) GetTownName() string {
+	if x != nil {
+		return x.TownName
+	}
+	return ""
+}
+
+type CombinedAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AddressLine1  string                 `protobuf:"bytes,1,opt,name=address_line1,json=addressLine1,proto3" json:"address_line1,omitempty"`
+	AddressLine2  string                 `protobuf:"bytes,2,opt,name=address_line2,json=addressLine2,proto3" json:"address_line2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CombinedAddress) Reset() {
+	*x = CombinedAddress{}
+	mi := &file_swissqr_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CombinedAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CombinedAddress) ProtoMessage() {}
+
+func (x *CombinedAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CombinedAddress.ProtoReflect.Descriptor instead.
+func (*CombinedAddress) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CombinedAddress) GetAddressLine1() string {
+	if x != nil {
+		return x.AddressLine1
+	}
+	return ""
+}
+
+func (x *CombinedAddress) GetAddressLine2() string {
+	if x != nil {
+		return x.AddressLine2
+	}
+	return ""
+}
+
+type Amount struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Amount float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Currency is CHF or EUR.
+	Currency      string     `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Mode          AmountMode `protobuf:"varint,3,opt,name=mode,proto3,enum=krepost.swissqr.AmountMode" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Amount) Reset() {
+	*x = Amount{}
+	mi := &file_swissqr_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Amount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Amount) ProtoMessage() {}
+
+func (x *Amount) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Amount.ProtoReflect.Descriptor instead.
+func (*Amount) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Amount) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Amount) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Amount) GetMode() AmountMode {
+	if x != nil {
+		return x.Mode
+	}
+	return AmountMode_AMOUNT_MODE_BOX
+}
+
+type Reference struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  ReferenceType          `protobuf:"varint,1,opt,name=type,proto3,enum=krepost.swissqr.ReferenceType" json:"type,omitempty"`
+	// Number is the reference in digital format, without spaces.
+	Number        string `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reference) Reset() {
+	*x = Reference{}
+	mi := &file_swissqr_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reference) ProtoMessage() {}
+
+func (x *Reference) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reference.ProtoReflect.Descriptor instead.
+func (*Reference) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Reference) GetType() ReferenceType {
+	if x != nil {
+		return x.Type
+	}
+	return ReferenceType_REFERENCE_TYPE_NON
+}
+
+func (x *Reference) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+// BillInformation is the structured message in the S1 syntax.
+type BillInformation struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	InvoiceNumber     string                 `protobuf:"bytes,1,opt,name=invoice_number,json=invoiceNumber,proto3" json:"invoice_number,omitempty"`
+	InvoiceDate       *Dates                 `protobuf:"bytes,2,opt,name=invoice_date,json=invoiceDate,proto3" json:"invoice_date,omitempty"`
+	CustomerReference string                 `protobuf:"bytes,3,opt,name=customer_reference,json=customerReference,proto3" json:"customer_reference,omitempty"`
+	VatNumber         string                 `protobuf:"bytes,4,opt,name=vat_number,json=vatNumber,proto3" json:"vat_number,omitempty"`
+	VatDates          *Dates                 `protobuf:"bytes,5,opt,name=vat_dates,json=vatDates,proto3" json:"vat_dates,omitempty"`
+	VatRates          []*TaxRate             `protobuf:"bytes,6,rep,name=vat_rates,json=vatRates,proto3" json:"vat_rates,omitempty"`
+	VatImportTaxRates []*TaxRate             `protobuf:"bytes,7,rep,name=vat_import_tax_rates,json=vatImportTaxRates,proto3" json:"vat_import_tax_rates,omitempty"`
+	Conditions        []*PaymentCondition    `protobuf:"bytes,8,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BillInformation) Reset() {
+	*x = BillInformation{}
+	mi := &file_swissqr_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BillInformation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BillInformation) ProtoMessage() {}
+
+func (x *BillInformation) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BillInformation.ProtoReflect.Descriptor instead.
+func (*BillInformation) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BillInformation) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
+
+func (x *BillInformation) GetInvoiceDate() *Dates {
+	if x != nil {
+		return x.InvoiceDate
+	}
+	return nil
+}
+
+func (x *BillInformation) GetCustomerReference() string {
+	if x != nil {
+		return x.CustomerReference
+	}
+	return ""
+}
+
+func (x *BillInformation) GetVatNumber() string {
+	if x != nil {
+		return x.VatNumber
+	}
+	return ""
+}
+
+func (x *BillInformation) GetVatDates() *Dates {
+	if x != nil {
+		return x.VatDates
+	}
+	return nil
+}
+
+func (x *BillInformation) GetVatRates() []*TaxRate {
+	if x != nil {
+		return x.VatRates
+	}
+	return nil
+}
+
+func (x *BillInformation) GetVatImportTaxRates() []*TaxRate {
+	if x != nil {
+		return x.VatImportTaxRates
+	}
+	return nil
+}
+
+func (x *BillInformation) GetConditions() []*PaymentCondition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+// Dates is one date or, if end is set, a date interval.
+type Dates struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          *Date                  `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	End           *Date                  `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dates) Reset() {
+	*x = Dates{}
+	mi := &file_swissqr_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dates) ProtoMessage() {}
+
+func (x *Dates) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dates.ProtoReflect.Descriptor instead.
+func (*Dates) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Dates) GetDate() *Date {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Dates) GetEnd() *Date {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// Date is a calendar date, as google.type.Date.
+type Date struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Year          int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month         int32                  `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	Day           int32                  `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Date) Reset() {
+	*x = Date{}
+	mi := &file_swissqr_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Date) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Date) ProtoMessage() {}
+
+func (x *Date) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Date.ProtoReflect.Descriptor instead.
+func (*Date) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Date) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Date) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *Date) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+type TaxRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RatePercent   float64                `protobuf:"fixed64,1,opt,name=rate_percent,json=ratePercent,proto3" json:"rate_percent,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaxRate) Reset() {
+	*x = TaxRate{}
+	mi := &file_swissqr_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaxRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxRate) ProtoMessage() {}
+
+func (x *TaxRate) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxRate.ProtoReflect.Descriptor instead.
+func (*TaxRate) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TaxRate) GetRatePercent() float64 {
+	if x != nil {
+		return x.RatePercent
+	}
+	return 0
+}
+
+func (x *TaxRate) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type PaymentCondition struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DiscountPercent float64                `protobuf:"fixed64,1,opt,name=discount_percent,json=discountPercent,proto3" json:"discount_percent,omitempty"`
+	NumberOfDays    int32                  `protobuf:"varint,2,opt,name=number_of_days,json=numberOfDays,proto3" json:"number_of_days,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PaymentCondition) Reset() {
+	*x = PaymentCondition{}
+	mi := &file_swissqr_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentCondition) ProtoMessage() {}
+
+func (x *PaymentCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentCondition.ProtoReflect.Descriptor instead.
+func (*PaymentCondition) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PaymentCondition) GetDiscountPercent() float64 {
+	if x != nil {
+		return x.DiscountPercent
+	}
+	return 0
+}
+
+func (x *PaymentCondition) GetNumberOfDays() int32 {
+	if x != nil {
+		return x.NumberOfDays
+	}
+	return 0
+}
+
+type AlternativeProcedure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Procedure     string                 `protobuf:"bytes,2,opt,name=procedure,proto3" json:"procedure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlternativeProcedure) Reset() {
+	*x = AlternativeProcedure{}
+	mi := &file_swissqr_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlternativeProcedure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlternativeProcedure) ProtoMessage() {}
+
+func (x *AlternativeProcedure) ProtoReflect() protoreflect.Message {
+	mi := &file_swissqr_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlternativeProcedure.ProtoReflect.Descriptor instead.
+func (*AlternativeProcedure) Descriptor() ([]byte, []int) {
+	return file_swissqr_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AlternativeProcedure) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AlternativeProcedure) GetProcedure() string {
+	if x != nil {
+		return x.Procedure
+	}
+	return ""
+}
+
+var File_swissqr_proto protoreflect.FileDescriptor
+
+const file_swissqr_proto_rawDesc = "" +
+	"\n" +
+	"\rswissqr.proto\x12\x0fkrepost.swissqr\"\xc3\x04\n" +
+	"\aPayload\x12\x18\n" +
+	"\aaccount\x18\x01 \x01(\tR\aaccount\x123\n" +
+	"\bcreditor\x18\x02 \x01(\v2\x17.krepost.swissqr.EntityR\bcreditor\x12D\n" +
+	"\x11ultimate_creditor\x18\x03 \x01(\v2\x17.krepost.swissqr.EntityR\x10ultimateCreditor\x12/\n" +
+	"\x06amount\x18\x04 \x01(\v2\x17.krepost.swissqr.AmountR\x06amount\x12@\n" +
+	"\x0fultimate_debtor\x18\x05 \x01(\v2\x17.krepost.swissqr.EntityR\x0eultimateDebtor\x128\n" +
+	"\treference\x18\x06 \x01(\v2\x1a.krepost.swissqr.ReferenceR\treference\x121\n" +
+	"\x14unstructured_message\x18\a \x01(\tR\x13unstructuredMessage\x12K\n" +
+	"\x10bill_information\x18\b \x01(\v2 .krepost.swissqr.BillInformationR\x0fbillInformation\x12\\\n" +
+	"\x16alternative_procedures\x18\t \x03(\v2%.krepost.swissqr.AlternativeProcedureR\x15alternativeProcedures\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\tR\aversion\"\xee\x01\n" +
+	"\x06Entity\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12S\n" +
+	"\x12structured_address\x18\x02 \x01(\v2\".krepost.swissqr.StructuredAddressH\x00R\x11structuredAddress\x12M\n" +
+	"\x10combined_address\x18\x03 \x01(\v2 .krepost.swissqr.CombinedAddressH\x00R\x0fcombinedAddress\x12!\n" +
+	"\fcountry_code\x18\x04 \x01(\tR\vcountryCodeB\t\n" +
+	"\aaddress\"\x97\x01\n" +
+	"\x11StructuredAddress\x12\x1f\n" +
+	"\vstreet_name\x18\x01 \x01(\tR\n" +
+	"streetName\x12'\n" +
+	"\x0fbuilding_number\x18\x02 \x01(\tR\x0ebuildingNumber\x12\x1b\n" +
+	"\tpost_code\x18\x03 \x01(\tR\bpostCode\x12\x1b\n" +
+	"\ttown_name\x18\x04 \x01(\tR\btownName\"[\n" +
+	"\x0fCombinedAddress\x12#\n" +
+	"\raddress_line1\x18\x01 \x01(\tR\faddressLine1\x12#\n" +
+	"\raddress_line2\x18\x02 \x01(\tR\faddressLine2\"m\n" +
+	"\x06Amount\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12/\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x1b.krepost.swissqr.AmountModeR\x04mode\"W\n" +
+	"\tReference\x122\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1e.krepost.swissqr.ReferenceTypeR\x04type\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\"\xbb\x03\n" +
+	"\x0fBillInformation\x12%\n" +
+	"\x0einvoice_number\x18\x01 \x01(\tR\rinvoiceNumber\x129\n" +
+	"\finvoice_date\x18\x02 \x01(\v2\x16.krepost.swissqr.DatesR\vinvoiceDate\x12-\n" +
+	"\x12customer_reference\x18\x03 \x01(\tR\x11customerReference\x12\x1d\n" +
+	"\n" +
+	"vat_number\x18\x04 \x01(\tR\tvatNumber\x123\n" +
+	"\tvat_dates\x18\x05 \x01(\v2\x16.krepost.swissqr.DatesR\bvatDates\x125\n" +
+	"\tvat_rates\x18\x06 \x03(\v2\x18.krepost.swissqr.TaxRateR\bvatRates\x12I\n" +
+	"\x14vat_import_tax_rates\x18\a \x03(\v2\x18.krepost.swissqr.TaxRateR\x11vatImportTaxRates\x12A\n" +
+	"\n" +
+	"conditions\x18\b \x03(\v2!.krepost.swissqr.PaymentConditionR\n" +
+	"conditions\"[\n" +
+	"\x05Dates\x12)\n" +
+	"\x04date\x18\x01 \x01(\v2\x15.krepost.swissqr.DateR\x04date\x12'\n" +
+	"\x03end\x18\x02 \x01(\v2\x15.krepost.swissqr.DateR\x03end\"B\n" +
+	"\x04Date\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\x05R\x05month\x12\x10\n" +
+	"\x03day\x18\x03 \x01(\x05R\x03day\"D\n" +
+	"\aTaxRate\x12!\n" +
+	"\frate_percent\x18\x01 \x01(\x01R\vratePercent\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\"c\n" +
+	"\x10PaymentCondition\x12)\n" +
+	"\x10discount_percent\x18\x01 \x01(\x01R\x0fdiscountPercent\x12$\n" +
+	"\x0enumber_of_days\x18\x02 \x01(\x05R\fnumberOfDays\"J\n" +
+	"\x14AlternativeProcedure\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x1c\n" +
+	"\tprocedure\x18\x02 \x01(\tR\tprocedure*M\n" +
+	"\n" +
+	"AmountMode\x12\x13\n" +
+	"\x0fAMOUNT_MODE_BOX\x10\x00\x12\x14\n" +
+	"\x10AMOUNT_MODE_ZERO\x10\x01\x12\x14\n" +
+	"\x10AMOUNT_MODE_OMIT\x10\x02*X\n" +
+	"\rReferenceType\x12\x16\n" +
+	"\x12REFERENCE_TYPE_NON\x10\x00\x12\x16\n" +
+	"\x12REFERENCE_TYPE_QRR\x10\x01\x12\x17\n" +
+	"\x13REFERENCE_TYPE_SCOR\x10\x02B&Z$github.com/krepost/swissqr/swissqrpbb\x06proto3"
+
+var (
+	file_swissqr_proto_rawDescOnce sync.Once
+	file_swissqr_proto_rawDescData []byte
+)
+
+func file_swissqr_proto_rawDescGZIP() []byte {
+	file_swissqr_proto_rawDescOnce.Do(func() {
+		file_swissqr_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_swissqr_proto_rawDesc), len(file_swissqr_proto_rawDesc)))
+	})
+	return file_swissqr_proto_rawDescData
+}
+
+var file_swissqr_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_swissqr_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_swissqr_proto_goTypes = []any{
+	(AmountMode)(0),              // 0: krepost.swissqr.AmountMode
+	(ReferenceType)(0),           // 1: krepost.swissqr.ReferenceType
+	(*Payload)(nil),              // 2: krepost.swissqr.Payload
+	(*Entity)(nil),               // 3: krepost.swissqr.Entity
+	(*StructuredAddress)(nil),    // 4: krepost.swissqr.StructuredAddress
+	(*CombinedAddress)(nil),      // 5: krepost.swissqr.CombinedAddress
+	(*Amount)(nil),               // 6: krepost.swissqr.Amount
+	(*Reference)(nil),            // 7: krepost.swissqr.Reference
+	(*BillInformation)(nil),      // 8: krepost.swissqr.BillInformation
+	(*Dates)(nil),                // 9: krepost.swissqr.Dates
+	(*Date)(nil),                 // 10: krepost.swissqr.Date
+	(*TaxRate)(nil),              // 11: krepost.swissqr.TaxRate
+	(*PaymentCondition)(nil),     // 12: krepost.swissqr.PaymentCondition
+	(*AlternativeProcedure)(nil), // 13: krepost.swissqr.AlternativeProcedure
+}
+var file_swissqr_proto_depIdxs = []int32{
+	3,  // 0: krepost.swissqr.Payload.creditor:type_name -> krepost.swissqr.Entity
+	3,  // 1: krepost.swissqr.Payload.ultimate_creditor:type_name -> krepost.swissqr.Entity
+	6,  // 2: krepost.swissqr.Payload.amount:type_name -> krepost.swissqr.Amount
+	3,  // 3: krepost.swissqr.Payload.ultimate_debtor:type_name -> krepost.swissqr.Entity
+	7,  // 4: krepost.swissqr.Payload.reference:type_name -> krepost.swissqr.Reference
+	8,  // 5: krepost.swissqr.Payload.bill_information:type_name -> krepost.swissqr.BillInformation
+	13, // 6: krepost.swissqr.Payload.alternative_procedures:type_name -> krepost.swissqr.AlternativeProcedure
+	4,  // 7: krepost.swissqr.Entity.structured_address:type_name -> krepost.swissqr.StructuredAddress
+	5,  // 8: krepost.swissqr.Entity.combined_address:type_name -> krepost.swissqr.CombinedAddress
+	0,  // 9: krepost.swissqr.Amount.mode:type_name -> krepost.swissqr.AmountMode
+	1,  // 10: krepost.swissqr.Reference.type:type_name -> krepost.swissqr.ReferenceType
+	9,  // 11: krepost.swissqr.BillInformation.invoice_date:type_name -> krepost.swissqr.Dates
+	9,  // 12: krepost.swissqr.BillInformation.vat_dates:type_name -> krepost.swissqr.Dates
+	11, // 13: krepost.swissqr.BillInformation.vat_rates:type_name -> krepost.swissqr.TaxRate
+	11, // 14: krepost.swissqr.BillInformation.vat_import_tax_rates:type_name -> krepost.swissqr.TaxRate
+	12, // 15: krepost.swissqr.BillInformation.conditions:type_name -> krepost.swissqr.PaymentCondition
+	10, // 16: krepost.swissqr.Dates.date:type_name -> krepost.swissqr.Date
+	10, // 17: krepost.swissqr.Dates.end:type_name -> krepost.swissqr.Date
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_swissqr_proto_init() }
+func file_swissqr_proto_init() {
+	if File_swissqr_proto != nil {
+		return
+	}
+	file_swissqr_proto_msgTypes[1].OneofWrappers = []any{
+		(*Entity_StructuredAddress)(nil),
+		(*Entity_CombinedAddress)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swissqr_proto_rawDesc), len(file_swissqr_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_swissqr_proto_goTypes,
+		DependencyIndexes: file_swissqr_proto_depIdxs,
+		EnumInfos:         file_swissqr_proto_enumTypes,
+		MessageInfos:      file_swissqr_proto_msgTypes,
+	}.Build()
+	File_swissqr_proto = out.File
+	file_swissqr_proto_goTypes = nil
+	file_swissqr_proto_depIdxs = nil
+}
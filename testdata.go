@@ -15,7 +15,6 @@
 package swissqr
 
 import (
-	"github.com/krepost/structref"
 	"time"
 )
 
@@ -79,7 +78,7 @@ var (
 			CountryCode: "CH",
 		},
 		Reference: PaymentReference{
-			Number: structref.NewReferenceNumberOrDie("210000000003139471430009017"),
+			Number: ParseQRReferenceOrDie("210000000003139471430009017"),
 		},
 		AdditionalInformation: PaymentInformation{
 			UnstructuredMessage: "Auftrag vom 18.06.2020",
@@ -0,0 +1,101 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "strings"
+
+// Transliterate replaces the characters that ValidateCharacterSet rejects
+// by permitted characters, following the conversion rules of the character
+// set annex of the Swiss Payment Standards: letters with diacritics that
+// are not permitted lose the diacritic, ligatures and Nordic letters are
+// spelled out (“æ” becomes “ae”, “ø” becomes “oe”), Cyrillic and Greek
+// letters are transcribed to Latin letters, and typographic quotes, dashes
+// and spaces become their ASCII counterparts. Characters without known
+// replacement are kept, so that the result may still have to be checked
+// with ValidateCharacterSet.
+func Transliterate(s string) string {
+	if ValidateCharacterSet(s) == nil {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if replacement, ok := transliterations[r]; ok {
+			b.WriteString(replacement)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// transliterations maps characters outside validRunes to their replacement.
+var transliterations = map[rune]string{
+	// Latin-1 Supplement.
+	'Ã': "A", 'Å': "AA", 'Æ': "AE", 'Ð': "D", 'Õ': "O", 'Ø': "OE", 'Ý': "Y",
+	'Þ': "TH", 'ã': "a", 'å': "aa", 'æ': "ae", 'ð': "d", 'õ': "o", 'ø': "oe",
+	'þ': "th", 'ÿ': "y",
+	// Latin Extended-A and Romanian.
+	'Ā': "A", 'ā': "a", 'Ă': "A", 'ă': "a", 'Ą': "A", 'ą': "a", 'Ć': "C",
+	'ć': "c", 'Ĉ': "C", 'ĉ': "c", 'Ċ': "C", 'ċ': "c", 'Č': "C", 'č': "c",
+	'Ď': "D", 'ď': "d", 'Đ': "D", 'đ': "d", 'Ē': "E", 'ē': "e", 'Ĕ': "E",
+	'ĕ': "e", 'Ė': "E", 'ė': "e", 'Ę': "E", 'ę': "e", 'Ě': "E", 'ě': "e",
+	'Ĝ': "G", 'ĝ': "g", 'Ğ': "G", 'ğ': "g", 'Ġ': "G", 'ġ': "g", 'Ģ': "G",
+	'ģ': "g", 'Ĥ': "H", 'ĥ': "h", 'Ħ': "H", 'ħ': "h", 'Ĩ': "I", 'ĩ': "i",
+	'Ī': "I", 'ī': "i", 'Ĭ': "I", 'ĭ': "i", 'Į': "I", 'į': "i", 'İ': "I",
+	'ı': "i", 'Ĳ': "IJ", 'ĳ': "ij", 'Ĵ': "J", 'ĵ': "j", 'Ķ': "K", 'ķ': "k",
+	'ĸ': "k", 'Ĺ': "L", 'ĺ': "l", 'Ļ': "L", 'ļ': "l", 'Ľ': "L", 'ľ': "l",
+	'Ŀ': "L", 'ŀ': "l", 'Ł': "L", 'ł': "l", 'Ń': "N", 'ń': "n", 'Ņ': "N",
+	'ņ': "n", 'Ň': "N", 'ň': "n", 'ŉ': "n", 'Ŋ': "N", 'ŋ': "n", 'Ō': "O",
+	'ō': "o", 'Ŏ': "O", 'ŏ': "o", 'Ő': "O", 'ő': "o", 'Œ': "OE", 'œ': "oe",
+	'Ŕ': "R", 'ŕ': "r", 'Ŗ': "R", 'ŗ': "r", 'Ř': "R", 'ř': "r", 'Ś': "S",
+	'ś': "s", 'Ŝ': "S", 'ŝ': "s", 'Ş': "S", 'ş': "s", 'Š': "S", 'š': "s",
+	'Ţ': "T", 'ţ': "t", 'Ť': "T", 'ť': "t", 'Ŧ': "T", 'ŧ': "t", 'Ũ': "U",
+	'ũ': "u", 'Ū': "U", 'ū': "u", 'Ŭ': "U", 'ŭ': "u", 'Ů': "U", 'ů': "u",
+	'Ű': "U", 'ű': "u", 'Ų': "U", 'ų': "u", 'Ŵ': "W", 'ŵ': "w", 'Ŷ': "Y",
+	'ŷ': "y", 'Ÿ': "Y", 'Ź': "Z", 'ź': "z", 'Ż': "Z", 'ż': "z", 'Ž': "Z",
+	'ž': "z", 'ſ': "s", 'Ș': "S", 'ș': "s", 'Ț': "T", 'ț': "t",
+	// Cyrillic.
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "E",
+	'Ж': "Zh", 'З': "Z", 'И': "I", 'Й': "I", 'К': "K", 'Л': "L", 'М': "M",
+	'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
+	'Ф': "F", 'Х': "Kh", 'Ц': "Ts", 'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch",
+	'Ъ': "Ie", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Iu", 'Я': "Ia", 'Ђ': "D",
+	'Ѓ': "G", 'Є': "Ie", 'Ѕ': "Dz", 'І': "I", 'Ї': "I", 'Ј': "J", 'Љ': "Lj",
+	'Њ': "Nj", 'Ћ': "C", 'Ќ': "K", 'Ў': "U", 'Џ': "Dz", 'Ґ': "G", 'а': "a",
+	'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+	'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "ie",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia", 'ђ': "d", 'ѓ': "g",
+	'є': "ie", 'ѕ': "dz", 'і': "i", 'ї': "i", 'ј': "j", 'љ': "lj", 'њ': "nj",
+	'ћ': "c", 'ќ': "k", 'ў': "u", 'џ': "dz", 'ґ': "g",
+	// Greek.
+	'Α': "A", 'Β': "V", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z", 'Η': "I",
+	'Θ': "Th", 'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M", 'Ν': "N", 'Ξ': "X",
+	'Ο': "O", 'Π': "P", 'Ρ': "R", 'Σ': "S", 'Τ': "T", 'Υ': "Y", 'Φ': "F",
+	'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O", 'Ά': "A", 'Έ': "E", 'Ή': "I", 'Ί': "I",
+	'Ό': "O", 'Ύ': "Y", 'Ώ': "O", 'Ϊ': "I", 'Ϋ': "Y", 'α': "a", 'β': "v",
+	'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o", 'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y",
+	'ώ': "o", 'ϊ': "i", 'ϋ': "y", 'ς': "s", 'ΐ': "i", 'ΰ': "y",
+	// Spaces and punctuation.
+	'\t': " ", '\u00a0': " ", '\u2007': " ", '\u2009': " ", '\u202f': " ",
+	'‘': "'", '’': "'", '‚': "'", '′': "'", '“': "\"", '”': "\"", '„': "\"",
+	'«': "\"", '»': "\"", '″': "\"", '‐': "-", '‑': "-", '–': "-", '—': "-",
+	'−': "-", '…': "...", '·': ".", '•': "*", '×': "x", '€': "EUR", 'ª': "a",
+	'º': "o",
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	var testdata = []struct {
+		input    string
+		expected string
+	}{
+		{"Robert Schneider AG", "Robert Schneider AG"},
+		{"Müller & Söhne", "Müller & Söhne"},
+		{"Søren Østergaard", "Soeren OEstergaard"},
+		{"Æbeltoft Ålborg", "AEbeltoft AAlborg"},
+		{"Łódź, ul. Piotrkowska", "Lódz, ul. Piotrkowska"},
+		{"Dvořák Šťastný", "Dvorák Stastný"},
+		{"Григорий Жуков", "Grigorii Zhukov"},
+		{"Αθήνα", "Athina"},
+		{"„Bestellung“ – 100 €", "\"Bestellung\" - 100 EUR"},
+		{"Zürich HB", "Zürich HB"},
+		{"漢字", "漢字"},
+	}
+	for i, item := range testdata {
+		if result := Transliterate(item.input); result != item.expected {
+			t.Errorf("Item %v: expected %q, got: %q", i, item.expected, result)
+		}
+	}
+}
+
+func TestTransliterationsValid(t *testing.T) {
+	for r, replacement := range transliterations {
+		if ValidateCharacterSet(string(r)) == nil {
+			t.Errorf("Permitted rune %#U has replacement %q", r, replacement)
+		}
+		if err := ValidateCharacterSet(replacement); err != nil {
+			t.Errorf("Invalid replacement for %#U: %v", r, err)
+		}
+	}
+}
@@ -15,235 +15,459 @@
 package swissqr
 
 import (
-	"errors"
 	"fmt"
-	"github.com/krepost/structref"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
-// Validate validates the payload and returns nil on success.
+// Validate validates the payload and returns nil on success. The error is
+// the first of the errors returned by ValidateAll.
 func (p Payload) Validate() error {
-	if err := p.Account.Validate(); err != nil {
-		return err
-	}
-	if err := p.Creditor.Validate(); err != nil {
-		return err
-	}
-	if err := p.UltimateCreditor.Validate(); err != nil {
-		return err
-	}
-	if err := p.CurrencyAmount.Validate(); err != nil {
-		return err
-	}
-	if err := p.UltimateDebtor.Validate(); err != nil {
-		return err
-	}
-	if err := p.Reference.Validate(); err != nil {
-		return err
-	}
-	if err := p.AdditionalInformation.Validate(); err != nil {
-		return err
-	}
-	if err := p.AlternativeProcedureParameters.Validate(); err != nil {
-		return err
+	return firstError(p.ValidateAll())
+}
+
+// ValidateAll validates the payload like Validate, but reports every
+// violation instead of stopping at the first, e.g. to mark all invalid
+// fields of a form at once. The errors are of type *ValidationError. The
+// rules that involve several fields, such as the reference type required
+// by a QR-IBAN, are only checked if all fields are valid on their own.
+func (p Payload) ValidateAll() []error {
+	rules, err := p.Rules()
+	if err != nil {
+		return []error{validationError("Version", CodeUnsupported, p.Version, "%v", err)}
+	}
+	var errs []error
+	errs = appendInField(errs, "Account", p.Account.violations())
+	errs = appendInField(errs, "Creditor", p.Creditor.violationsAs(CreditorRole, p.Version))
+	if rules.UltimateCreditor {
+		errs = appendInField(errs, "UltimateCreditor", p.UltimateCreditor.violations(p.Version))
+	} else {
+		errs = appendInField(errs, "UltimateCreditor", p.UltimateCreditor.violationsAs(UltimateCreditorRole, p.Version))
 	}
-	// The Creditor field must not be empty, but this is not checked by the
-	// Validate() method since other Entity fields can be empty.
-	if p.Creditor.Name == "" {
-		return errors.New("No creditor name specified.")
+	errs = appendInField(errs, "CurrencyAmount", p.CurrencyAmount.violations())
+	errs = appendInField(errs, "UltimateDebtor", p.UltimateDebtor.violationsAs(UltimateDebtorRole, p.Version))
+	errs = appendInField(errs, "Reference", p.Reference.violations())
+	errs = appendInField(errs, "AdditionalInformation", p.AdditionalInformation.violations(p.Version))
+	errs = appendInField(errs, "AlternativeProcedureParameters", p.AlternativeProcedureParameters.violations(p.Version))
+	if len(errs) > 0 {
+		return errs
 	}
-	// The UltimateCreditor field is reserved for future use.
-	if p.UltimateCreditor.Name != "" {
-		return errors.New("UltimateCreditor is currently not supported.")
+	return p.crossViolations()
+}
+
+// crossViolations checks the rules that involve several fields. It assumes
+// that the fields are valid on their own.
+func (p Payload) crossViolations() []error {
+	var errs []error
+	// Accounts in Liechtenstein are only offered to creditors domiciled in
+	// Liechtenstein or Switzerland.
+	creditorCountry := NormalizeCountryCode(p.Creditor.CountryCode)
+	if code, err := CountryCodeFromAlpha3(creditorCountry); err == nil {
+		creditorCountry = code // Accepted by LenientProfile.
+	}
+	if p.Account.IBAN.CountryCode == "LI" && creditorCountry != "LI" && creditorCountry != "CH" {
+		errs = append(errs, validationError("Creditor.CountryCode", CodeInconsistent, p.Creditor.CountryCode,
+			"Creditor country %v inconsistent with LI account: %v", p.Creditor.CountryCode, p.Account.IBAN.PrintCode))
 	}
 	// If a QR-IBAN is used, Reference must contain a QRReference code.
 	// Otherwise, either no reference or a Creditor Reference must be used.
-	// A QR-IBAN has a bank clearing number (first five digits of the IBAN
-	// itself, after country code and check sum digits) between 30000 and 31999.
-	if p.Account.IBAN.BBAN[:2] == "30" || p.Account.IBAN.BBAN[:2] == "31" {
-		if p.Reference.Number == nil {
-			return fmt.Errorf("QR Reference number required for QR-IBAN: %v",
-				p.Account.IBAN.PrintCode)
-		}
-		if _, ok := p.Reference.Number.(*structref.ReferenceNumber); !ok {
-			return fmt.Errorf("QR Reference number required for QR-IBAN: %v",
-				p.Account.IBAN.PrintCode)
+	if IsQRIBAN(p.Account) {
+		if p.Reference.Type() != "QRR" {
+			errs = append(errs, validationError("Reference", CodeInconsistent, p.Reference.Number,
+				"QR Reference number required for QR-IBAN: %v", p.Account.IBAN.PrintCode))
 		}
 	} else {
-		if p.Reference.Number != nil {
-			if _, ok := p.Reference.Number.(*structref.ReferenceNumber); ok {
-				return fmt.Errorf("QR Reference not allowed for IBAN: %v",
-					p.Account.IBAN.PrintCode)
-			}
+		if p.Reference.Type() == "QRR" {
+			errs = append(errs, validationError("Reference", CodeInconsistent, p.Reference.Number,
+				"QR Reference not allowed for IBAN: %v", p.Account.IBAN.PrintCode))
 		}
 	}
-	return nil
+	return errs
 }
 
 // Validate validates an Account
 func (a AccountNumber) Validate() error {
+	return firstError(a.violations())
+}
+
+// violations returns the first violation only, since each check of an
+// account depends on the previous one.
+func (a AccountNumber) violations() []error {
 	if a.IBAN == nil {
-		return errors.New("No account specified")
+		return []error{validationError("IBAN", CodeRequired, "", "No account specified")}
 	}
 	if a.IBAN.CountryCode != "CH" && a.IBAN.CountryCode != "LI" {
-		return fmt.Errorf("Only CH and LI accounts allowed: %v", a.IBAN.PrintCode)
+		return []error{validationError("IBAN", CodeInvalidValue, a.IBAN.PrintCode,
+			"Only CH and LI accounts allowed: %v", a.IBAN.PrintCode)}
+	}
+	// CH and LI IBANs have the same format: country code, check digits,
+	// a five-digit bank clearing number (IID) and a 12-character account.
+	if len(a.IBAN.Code) != 21 {
+		return []error{validationError("IBAN", CodeInvalidFormat, a.IBAN.PrintCode,
+			"%v IBAN must have 21 characters: %v", a.IBAN.CountryCode, a.IBAN.PrintCode)}
+	}
+	if !isDigits(a.IBAN.IID()) {
+		return []error{validationError("IBAN", CodeInvalidFormat, a.IBAN.PrintCode,
+			"Bank clearing number may only contain digits 0-9: %v", a.IBAN.PrintCode)}
 	}
 	return nil
 }
 
-// Validate validates an Entity
+// IsQRIBAN returns true if the account is a QR-IBAN. A QR-IBAN has a bank
+// clearing number (first five digits of the IBAN itself, after country code
+// and check sum digits) between 30000 and 31999, both in CH and in LI. QR
+// bills to a QR-IBAN require a QR reference; other accounts take a creditor
+// reference or none. It returns false for accounts that are not valid CH or
+// LI accounts.
+func IsQRIBAN(a AccountNumber) bool {
+	if a.Validate() != nil {
+		return false
+	}
+	iid := a.IBAN.IID()
+	return iid >= "30000" && iid <= "31999"
+}
+
+// Role identifies the party that an Entity represents in a payload.
+type Role int
+
+const (
+	// CreditorRole is mandatory: the entity must not be empty.
+	CreditorRole Role = iota
+
+	// UltimateCreditorRole is reserved for future use: the entity must
+	// be empty unless Payload.EnableUltimateCreditor is set.
+	UltimateCreditorRole
+
+	// UltimateDebtorRole is optional: the entity may be empty.
+	UltimateDebtorRole
+)
+
+// ValidateAs validates an Entity with the rules of the given role. Unlike
+// Validate, which accepts an empty entity for every role, it reports a
+// missing creditor and an ultimate creditor that is set.
+func (e Entity) ValidateAs(role Role) error {
+	return firstError(e.violationsAs(role, SpecVersion20))
+}
+
+func (e Entity) violationsAs(role Role, v SpecVersion) []error {
+	switch role {
+	case CreditorRole:
+		if e.Name == "" && e.Address == nil && e.CountryCode == "" {
+			return []error{validationError("Name", CodeRequired, "", "No creditor name specified.")}
+		}
+	case UltimateCreditorRole:
+		if e.Name != "" || e.Address != nil || e.CountryCode != "" {
+			return []error{validationError("", CodeNotAllowed, e.Name, "UltimateCreditor is currently not supported.")}
+		}
+	case UltimateDebtorRole:
+	default:
+		return []error{validationError("", CodeUnsupported, role, "Unknown role: %d", role)}
+	}
+	return e.violations(v)
+}
+
+// Validate validates an Entity. An empty entity is valid; use ValidateAs
+// to check the rules of a particular role.
 func (e Entity) Validate() error {
+	return firstError(e.violations(SpecVersion20))
+}
+
+func (e Entity) violations(v SpecVersion) []error {
 	// Empty record is allowed.
 	if e.Name == "" && e.Address == nil && e.CountryCode == "" {
 		return nil
 	}
+	var errs []error
 
 	// Name is mandatory for non-empty records.
 	if e.Name == "" {
-		return errors.New("Name must be specified.")
-	}
-	if len(e.Name) > 70 {
-		return fmt.Errorf("Maximum name length is 70 characters: %v", e.Name)
+		errs = append(errs, validationError("Name", CodeRequired, "", "Name must be specified."))
+	} else if utf8.RuneCountInString(e.Name) > 70 {
+		errs = append(errs, validationError("Name", CodeTooLong, e.Name,
+			"Maximum name length is 70 characters: %v", e.Name))
 	}
-	if err := ValidateCharacterSet(e.Name); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(e.Name); err != nil {
+		errs = append(errs, inField("Name", err))
 	}
 
-	// Country code is mandatory.
-	if e.CountryCode == "" {
-		return fmt.Errorf("Country code must be specified for name: %v", e.Name)
-	}
-	if len([]rune(e.CountryCode)) > 2 {
-		return fmt.Errorf("Country should be given as two-letter code: %v", e.CountryCode)
-	}
-	if _, found := countryCodes[e.CountryCode]; !found {
-		return fmt.Errorf("Invalid country code: %v", e.CountryCode)
+	// Country code is mandatory; lower case and surrounding whitespace are
+	// accepted.
+	switch code := NormalizeCountryCode(e.CountryCode); {
+	case code == "":
+		errs = append(errs, validationError("CountryCode", CodeRequired, "",
+			"Country code must be specified for name: %v", e.Name))
+	case utf8.RuneCountInString(code) > 2:
+		errs = append(errs, validationError("CountryCode", CodeInvalidFormat, e.CountryCode,
+			"Country should be given as two-letter code: %v", e.CountryCode))
+	case !countryCodes[code]:
+		errs = append(errs, validationError("CountryCode", CodeInvalidValue, e.CountryCode,
+			"Invalid country code: %v", e.CountryCode))
 	}
 
 	// Check address type and validate recursively.
 	switch a := e.Address.(type) {
-	case CombinedAddress, StructuredAddress:
-		return a.Validate()
+	case CombinedAddress:
+		if !v.allowsCombinedAddress() {
+			errs = append(errs, validationError("Address", CodeNotAllowed, a,
+				"Combined addresses are not permitted in version %v: %v", v, e.Name))
+			break
+		}
+		errs = appendInField(errs, "Address", a.violations(v))
+	case StructuredAddress:
+		errs = appendInField(errs, "Address", a.violations(v))
 	default:
-		return fmt.Errorf("Unsupported address type: %T", a)
+		errs = append(errs, validationError("Address", CodeUnsupported, fmt.Sprintf("%T", a),
+			"Unsupported address type: %T", a))
 	}
+	return errs
+}
 
-	return nil
+// allowsCombinedAddress reports whether version v permits combined
+// addresses, which version 2.3 retires.
+func (v SpecVersion) allowsCombinedAddress() bool {
+	return v == SpecVersion20
 }
 
 // Validate validates a CombinedAddress.
 func (ca CombinedAddress) Validate() error {
+	return firstError(ca.violations(SpecVersion20))
+}
+
+func (ca CombinedAddress) violations(v SpecVersion) []error {
+	var errs []error
 	// Combined address mode.
 	if ca.AddressLine2 == "" {
-		return fmt.Errorf("Address line 2 must be set for address: %v", ca)
+		errs = append(errs, validationError("AddressLine2", CodeRequired, "",
+			"Address line 2 must be set for address: %v", ca))
 	}
-	if err := ValidateCharacterSet(ca.AddressLine1); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(ca.AddressLine1); err != nil {
+		errs = append(errs, inField("AddressLine1", err))
 	}
-	if err := ValidateCharacterSet(ca.AddressLine2); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(ca.AddressLine2); err != nil {
+		errs = append(errs, inField("AddressLine2", err))
 	}
-	if len(ca.AddressLine1) > 70 {
-		return fmt.Errorf("Maximum address line length is 70 characters: %v", ca.AddressLine1)
+	if utf8.RuneCountInString(ca.AddressLine1) > 70 {
+		errs = append(errs, validationError("AddressLine1", CodeTooLong, ca.AddressLine1,
+			"Maximum address line length is 70 characters: %v", ca.AddressLine1))
 	}
-	if len(ca.AddressLine2) > 70 {
-		return fmt.Errorf("Maximum address line length is 70 characters: %v", ca.AddressLine2)
+	if utf8.RuneCountInString(ca.AddressLine2) > 70 {
+		errs = append(errs, validationError("AddressLine2", CodeTooLong, ca.AddressLine2,
+			"Maximum address line length is 70 characters: %v", ca.AddressLine2))
 	}
-	return nil
+	return errs
 }
 
 // Validate validates a StructuredAddress.
 func (sa StructuredAddress) Validate() error {
-	if sa.PostCode == "" || sa.TownName == "" {
-		return fmt.Errorf("Must specify post code and town in address: %v", sa)
+	return firstError(sa.violations(SpecVersion20))
+}
+
+func (sa StructuredAddress) violations(v SpecVersion) []error {
+	var errs []error
+	if sa.PostCode == "" {
+		errs = append(errs, validationError("PostCode", CodeRequired, "",
+			"Must specify post code and town in address: %v", sa))
 	}
-	if err := ValidateCharacterSet(sa.StreetName); err != nil {
-		return err
+	if sa.TownName == "" {
+		errs = append(errs, validationError("TownName", CodeRequired, "",
+			"Must specify post code and town in address: %v", sa))
 	}
-	if err := ValidateCharacterSet(sa.BuildingNumber); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(sa.StreetName); err != nil {
+		errs = append(errs, inField("StreetName", err))
 	}
-	if err := ValidateCharacterSet(sa.PostCode); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(sa.BuildingNumber); err != nil {
+		errs = append(errs, inField("BuildingNumber", err))
 	}
-	if err := ValidateCharacterSet(sa.TownName); err != nil {
-		return err
+	if err := v.ValidateCharacterSet(sa.PostCode); err != nil {
+		errs = append(errs, inField("PostCode", err))
 	}
-	if len(sa.StreetName) > 70 {
-		return fmt.Errorf("Maximum street name length is 70 characters: %v", sa.StreetName)
+	if err := v.ValidateCharacterSet(sa.TownName); err != nil {
+		errs = append(errs, inField("TownName", err))
 	}
-	if len(sa.BuildingNumber) > 16 {
-		return fmt.Errorf("Maximum building number length is 16 characters: %v", sa.BuildingNumber)
+	if utf8.RuneCountInString(sa.StreetName) > 70 {
+		errs = append(errs, validationError("StreetName", CodeTooLong, sa.StreetName,
+			"Maximum street name length is 70 characters: %v", sa.StreetName))
 	}
-	if len(sa.PostCode) > 16 {
-		return fmt.Errorf("Maximum post code length is 16 characters: %v", sa.PostCode)
+	if utf8.RuneCountInString(sa.BuildingNumber) > 16 {
+		errs = append(errs, validationError("BuildingNumber", CodeTooLong, sa.BuildingNumber,
+			"Maximum building number length is 16 characters: %v", sa.BuildingNumber))
 	}
-	if len(sa.TownName) > 35 {
-		return fmt.Errorf("Maximum town name length is 35 characters: %v", sa.TownName)
+	if utf8.RuneCountInString(sa.PostCode) > 16 {
+		errs = append(errs, validationError("PostCode", CodeTooLong, sa.PostCode,
+			"Maximum post code length is 16 characters: %v", sa.PostCode))
 	}
-	return nil
+	if utf8.RuneCountInString(sa.TownName) > 35 {
+		errs = append(errs, validationError("TownName", CodeTooLong, sa.TownName,
+			"Maximum town name length is 35 characters: %v", sa.TownName))
+	}
+	return errs
 }
 
 // Validate validates a PaymentAmount.
 func (pa PaymentAmount) Validate() error {
+	return firstError(pa.violations())
+}
+
+func (pa PaymentAmount) violations() []error {
+	var errs []error
 	if pa.Currency != "CHF" && pa.Currency != "EUR" {
-		return fmt.Errorf("Currency must be CHF or EUR: %v", pa.Currency)
+		errs = append(errs, validationError("Currency", CodeInvalidValue, pa.Currency,
+			"Currency must be CHF or EUR: %v", pa.Currency))
 	}
-	if pa.Amount < 0.0 {
-		return fmt.Errorf("Amount cannot be negative: %v", pa.Amount)
+	if math.IsNaN(pa.Amount) || math.IsInf(pa.Amount, 0) {
+		errs = append(errs, validationError("Amount", CodeInvalidValue, pa.Amount,
+			"Amount must be a finite number: %v", pa.Amount))
+	} else if pa.Amount < 0.0 {
+		errs = append(errs, validationError("Amount", CodeInvalidValue, pa.Amount,
+			"Amount cannot be negative: %v", pa.Amount))
 	}
 	if len(fmt.Sprintf("%.2f", pa.Amount)) > 12 {
-		return fmt.Errorf("Amount too large: %v", pa.Amount)
+		errs = append(errs, validationError("Amount", CodeTooLong, pa.Amount, "Amount too large: %v", pa.Amount))
+	}
+	switch pa.Mode {
+	case AmountBox:
+	case AmountZero, AmountOmit:
+		if pa.Amount != 0.0 {
+			errs = append(errs, validationError("Amount", CodeNotAllowed, pa.Amount,
+				"Amount must be zero for amount mode %d: %v", pa.Mode, pa.Amount))
+		}
+	default:
+		errs = append(errs, validationError("Mode", CodeUnsupported, pa.Mode, "Unknown amount mode: %d", pa.Mode))
 	}
-	return nil
+	return errs
 }
 
 // Validate validates a payment reference.
 func (r PaymentReference) Validate() error {
-	switch v := r.Number.(type) {
-	case nil, *structref.CreditorReference, *structref.ReferenceNumber:
-		return nil
-	default:
-		return fmt.Errorf("Unknown reference type: %T", v)
+	return firstError(r.violations())
+}
+
+func (r PaymentReference) violations() []error {
+	if r.Type() == "" {
+		return []error{validationError("Number", CodeUnsupported, fmt.Sprintf("%T", r.Number),
+			"Unknown reference type: %T", r.Number)}
 	}
+	return nil
 }
 
 // Validate validates additional payment information.
 func (pi PaymentInformation) Validate() error {
-	if err := ValidateCharacterSet(pi.UnstructuredMessage); err != nil {
-		return err
-	}
-	if err := pi.StructuredMessage.Validate(); err != nil {
-		return err
+	return firstError(pi.violations(SpecVersion20))
+}
+
+func (pi PaymentInformation) violations(v SpecVersion) []error {
+	var errs []error
+	if err := v.ValidateCharacterSet(pi.UnstructuredMessage); err != nil {
+		errs = append(errs, inField("UnstructuredMessage", err))
+	}
+	errs = appendInField(errs, "StructuredMessage", pi.StructuredMessage.violations(v))
+	if raw := pi.RawBillInformation; raw != "" {
+		if pi.StructuredMessage.ToString() != "" {
+			errs = append(errs, validationError("RawBillInformation", CodeNotAllowed, raw,
+				"Raw bill information cannot be combined with a structured message: %v", raw))
+		} else if !strings.HasPrefix(raw, "//") {
+			errs = append(errs, validationError("RawBillInformation", CodeInvalidFormat, raw,
+				"Bill information must start with “//”: %v", raw))
+		} else if err := v.ValidateCharacterSet(raw); err != nil {
+			errs = append(errs, inField("RawBillInformation", err))
+		}
 	}
-	if s := pi.UnstructuredMessage + pi.StructuredMessage.ToString(); len(s) > 140 {
-		return fmt.Errorf("Maximum combined length is 140: %v", s)
+	if pi.Length() > maxInformationLength {
+		combined := pi.UnstructuredMessage + pi.BillInformation()
+		errs = append(errs, validationError("", CodeTooLong, combined,
+			"Maximum combined length is %d: %v", maxInformationLength, combined))
 	}
-	return nil
+	return errs
+}
+
+// maxInformationLength is the maximum combined length of the unstructured
+// message and the bill information.
+const maxInformationLength = 140
+
+// Length returns the combined length of the unstructured message and the
+// encoded bill information in characters, as counted by Validate.
+func (pi PaymentInformation) Length() int {
+	return utf8.RuneCountInString(pi.UnstructuredMessage) + utf8.RuneCountInString(pi.BillInformation())
+}
+
+// Remaining returns how many characters can be added to either the
+// unstructured message or the bill information before Validate reports
+// that the combined length is exceeded. The result is negative if the
+// combined length is exceeded already.
+func (pi PaymentInformation) Remaining() int {
+	return maxInformationLength - pi.Length()
 }
 
-// Validate validates alternative payment procedures.
+// Validate validates alternative payment procedures. Procedures of schemes
+// with a registered ProcedureValidator must also pass the validator.
 func (vec AlternativeProcedures) Validate() error {
+	return firstError(vec.violations(SpecVersion20))
+}
+
+func (vec AlternativeProcedures) violations(v SpecVersion) []error {
+	var errs []error
 	if len(vec) > 2 {
-		return fmt.Errorf("Maximum two alternate payment schemes allowed: %v", vec)
+		errs = append(errs, validationError("", CodeTooLong, len(vec),
+			"Maximum two alternate payment schemes allowed: %v", vec))
 	}
-	for _, ap := range vec {
-		if err := ValidateCharacterSet(ap.Label); err != nil {
-			return err
-		}
-		if err := ValidateCharacterSet(ap.Procedure); err != nil {
-			return err
-		}
-		if ap.Label == "" {
-			return fmt.Errorf("No label specified: %v", ap)
+	for i, ap := range vec {
+		errs = appendInField(errs, fmt.Sprintf("[%d]", i), ap.violations(v))
+	}
+	return errs
+}
+
+func (ap AlternativeProcedure) violations(v SpecVersion) []error {
+	var errs []error
+	if err := v.ValidateCharacterSet(ap.Label); err != nil {
+		errs = append(errs, inField("Label", err))
+	}
+	languages := make([]string, 0, len(ap.Labels))
+	for language := range ap.Labels {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		label := ap.Labels[language]
+		field := fmt.Sprintf("Labels[%v]", language)
+		if err := v.ValidateCharacterSet(label); err != nil {
+			errs = append(errs, inField(field, err))
 		}
-		if ap.Procedure == "" {
-			return fmt.Errorf("No procedure specified: %v", ap)
+		if label == "" {
+			errs = append(errs, validationError(field, CodeRequired, "",
+				"Empty label specified for language %v: %v", language, ap))
 		}
-		if len(ap.Procedure) > 100 {
-			return fmt.Errorf("Maximum field length is 100 characters: %v", ap)
+	}
+	if err := v.ValidateCharacterSet(ap.Procedure); err != nil {
+		errs = append(errs, inField("Procedure", err))
+	}
+	if ap.Label == "" {
+		errs = append(errs, validationError("Label", CodeRequired, "", "No label specified: %v", ap))
+	}
+	switch {
+	case ap.Procedure == "":
+		errs = append(errs, validationError("Procedure", CodeRequired, "", "No procedure specified: %v", ap))
+	case utf8.RuneCountInString(ap.Procedure) > 100:
+		errs = append(errs, validationError("Procedure", CodeTooLong, ap.Procedure,
+			"Maximum field length is 100 characters: %v", ap))
+	default:
+		if err := validateProcedure(ap.Procedure); err != nil {
+			if _, ok := err.(*ValidationError); !ok {
+				// Registered validators may return plain errors.
+				err = validationError("", CodeInvalidFormat, ap.Procedure, "%v", err)
+			}
+			errs = append(errs, inField("Procedure", err))
 		}
 	}
-	return nil
+	return errs
+}
+
+// NormalizeCountryCode returns code in the canonical form of ISO 3166-1,
+// i.e. in upper case without surrounding whitespace, so that “ ch” is
+// accepted as “CH”. The result is not checked against the list of codes.
+func NormalizeCountryCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
 }
 
 var countryCodes = map[string]bool{
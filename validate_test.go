@@ -15,8 +15,8 @@
 package swissqr
 
 import (
-	"github.com/krepost/structref"
 	"io"
+	"math"
 	"strings"
 	"testing"
 )
@@ -64,6 +64,30 @@ func TestValidateNoUltimateCreditorPresent(t *testing.T) {
 	}
 }
 
+func TestValidateLiechtensteinCreditorCountry(t *testing.T) {
+	var testdata = []struct {
+		country string
+		message string
+	}{
+		{"LI", ""},
+		{"CH", ""},
+		{"AT", "Creditor country AT inconsistent with LI account"},
+	}
+	for i, data := range testdata {
+		payload := minimalCorrectPayload
+		payload.Account = NewIBANOrDie("LI21088100002324013AA")
+		payload.Creditor.CountryCode = data.country
+		err := payload.Validate()
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
+
 func TestValidateCrossFieldDependencies(t *testing.T) {
 	var testdata = []struct {
 		account   AccountNumber
@@ -82,14 +106,14 @@ func TestValidateCrossFieldDependencies(t *testing.T) {
 		{
 			account: NewIBANOrDie("CH4431999123000889012"),
 			reference: PaymentReference{
-				Number: structref.NewCreditorReferenceOrDie("RF8312345678912345678912"),
+				Number: ParseCreditorReferenceOrDie("RF8312345678912345678912"),
 			},
 			message: "QR Reference number required for QR-IBAN",
 		},
 		{
 			account: NewIBANOrDie("CH4431999123000889012"),
 			reference: PaymentReference{
-				Number: structref.NewReferenceNumberOrDie("210000000003139471430009017"),
+				Number: ParseQRReferenceOrDie("210000000003139471430009017"),
 			},
 			message: "",
 		},
@@ -103,14 +127,35 @@ func TestValidateCrossFieldDependencies(t *testing.T) {
 		{
 			account: NewIBANOrDie("CH5604835012345678009"),
 			reference: PaymentReference{
-				Number: structref.NewCreditorReferenceOrDie("RF8312345678912345678912"),
+				Number: ParseCreditorReferenceOrDie("RF8312345678912345678912"),
 			},
 			message: "",
 		},
 		{
 			account: NewIBANOrDie("CH5604835012345678009"),
 			reference: PaymentReference{
-				Number: structref.NewReferenceNumberOrDie("210000000003139471430009017"),
+				Number: ParseQRReferenceOrDie("210000000003139471430009017"),
+			},
+			message: "QR Reference not allowed for IBAN",
+		},
+		{
+			// QR-IBANs in Liechtenstein use the same range of bank clearing
+			// numbers as in Switzerland.
+			account:   NewIBANOrDie("LI3530808123456789012"),
+			reference: PaymentReference{},
+			message:   "QR Reference number required for QR-IBAN",
+		},
+		{
+			account: NewIBANOrDie("LI3530808123456789012"),
+			reference: PaymentReference{
+				Number: ParseQRReferenceOrDie("210000000003139471430009017"),
+			},
+			message: "",
+		},
+		{
+			account: NewIBANOrDie("LI21088100002324013AA"),
+			reference: PaymentReference{
+				Number: ParseQRReferenceOrDie("210000000003139471430009017"),
 			},
 			message: "QR Reference not allowed for IBAN",
 		},
@@ -207,6 +252,14 @@ func TestValidateEntity(t *testing.T) {
 			},
 			message: "Country should be given as two-letter code",
 		},
+		{
+			entity: Entity{
+				Name:        "Name",
+				Address:     CombinedAddress{AddressLine2: "8000 Zürich"},
+				CountryCode: " ch ",
+			},
+			message: "",
+		},
 		{
 			entity: Entity{
 				Name:        "Name",
@@ -419,6 +472,24 @@ func TestValidateCombinedAddress(t *testing.T) {
 	}
 }
 
+func TestIsQRIBAN(t *testing.T) {
+	var testdata = []struct {
+		account  AccountNumber
+		expected bool
+	}{
+		{AccountNumber{}, false},
+		{NewIBANOrDie("CH4431999123000889012"), true},
+		{NewIBANOrDie("LI35 3080 8123 4567 8901 2"), true},
+		{NewIBANOrDie("CH5800791123000889012"), false},
+		{NewIBANOrDie("DE91100000000123456789"), false},
+	}
+	for i, data := range testdata {
+		if actual := IsQRIBAN(data.account); actual != data.expected {
+			t.Errorf("Item %v: expected %v, got %v", i, data.expected, actual)
+		}
+	}
+}
+
 func TestValidateAccount(t *testing.T) {
 	var testdata = []struct {
 		account AccountNumber
@@ -436,6 +507,34 @@ func TestValidateAccount(t *testing.T) {
 			account: NewIBANOrDie("DE91100000000123456789"),
 			message: "Only CH and LI accounts allowed",
 		},
+		{
+			account: NewIBANOrDie("LI21 0881 0000 2324 013A A"),
+			message: "",
+		},
+		{
+			account: NewIBANOrDie("LI35 3080 8123 4567 8901 2"),
+			message: "",
+		},
+		{
+			account: AccountNumber{IBAN: &IBAN{
+				Code:        "LI2108810000232401",
+				PrintCode:   "LI21 0881 0000 2324 01",
+				CountryCode: "LI",
+				CheckDigits: "21",
+				BBAN:        "08810000232401",
+			}},
+			message: "LI IBAN must have 21 characters",
+		},
+		{
+			account: AccountNumber{IBAN: &IBAN{
+				Code:        "LI21A8810000232401300",
+				PrintCode:   "LI21 A881 0000 2324 0130 0",
+				CountryCode: "LI",
+				CheckDigits: "21",
+				BBAN:        "A8810000232401300",
+			}},
+			message: "Bank clearing number may only contain digits",
+		},
 	}
 	for i, data := range testdata {
 		err := data.account.Validate()
@@ -490,6 +589,26 @@ func TestValidatePaymentAmount(t *testing.T) {
 			amount:  PaymentAmount{Currency: CHF, Amount: 1234567890.0},
 			message: "Amount too large",
 		},
+		{
+			amount:  PaymentAmount{Currency: CHF, Amount: math.NaN()},
+			message: "Amount must be a finite number",
+		},
+		{
+			amount:  PaymentAmount{Currency: CHF, Amount: math.Inf(1)},
+			message: "Amount must be a finite number",
+		},
+		{
+			amount:  PaymentAmount{Currency: EUR, Mode: AmountZero},
+			message: "",
+		},
+		{
+			amount:  PaymentAmount{Currency: CHF, Amount: 17.0, Mode: AmountOmit},
+			message: "Amount must be zero for amount mode 2",
+		},
+		{
+			amount:  PaymentAmount{Currency: CHF, Mode: 3},
+			message: "Unknown amount mode: 3",
+		},
 	}
 	for i, data := range testdata {
 		err := data.amount.Validate()
@@ -507,7 +626,7 @@ func TestValidatePaymentAmount(t *testing.T) {
 	}
 }
 
-// fakeReference implements interface structref.Printer.
+// fakeReference implements interface ReferencePrinter.
 type fakeReference struct{}
 
 func (r fakeReference) DigitalFormat() string { return "" }
@@ -528,13 +647,13 @@ func TestValidatePaymentReference(t *testing.T) {
 		},
 		{
 			ref: PaymentReference{
-				Number: structref.NewReferenceNumberOrDie("210000000003139471430009017"),
+				Number: ParseQRReferenceOrDie("210000000003139471430009017"),
 			},
 			message: "",
 		},
 		{
 			ref: PaymentReference{
-				Number: structref.NewCreditorReferenceOrDie("RF8312345678912345678912"),
+				Number: ParseCreditorReferenceOrDie("RF8312345678912345678912"),
 			},
 			message: "",
 		},
@@ -616,6 +735,21 @@ func TestValidatePaymentInformation(t *testing.T) {
 			},
 			message: "Maximum combined length is 140",
 		},
+		{
+			info:    PaymentInformation{RawBillInformation: "//XY/ref"},
+			message: "",
+		},
+		{
+			info:    PaymentInformation{RawBillInformation: "XY/ref"},
+			message: "Bill information must start with “//”",
+		},
+		{
+			info: PaymentInformation{
+				StructuredMessage:  BillInformation{CustomerReference: "ref"},
+				RawBillInformation: "//XY/ref",
+			},
+			message: "Raw bill information cannot be combined with a structured message",
+		},
 	}
 	for i, data := range testdata {
 		err := data.info.Validate()
@@ -706,6 +840,26 @@ func TestValidateAlternativeProcedures(t *testing.T) {
 			},
 			message: "Maximum field length is 100 characters",
 		},
+		{
+			procedures: AlternativeProcedures{
+				AlternativeProcedure{
+					Label:     "Name AV1",
+					Labels:    map[string]string{"fr": "Nom AV1", "it": "Nome AV1"},
+					Procedure: "Procedure X",
+				},
+			},
+			message: "",
+		},
+		{
+			procedures: AlternativeProcedures{
+				AlternativeProcedure{
+					Label:     "Name AV1",
+					Labels:    map[string]string{"fr": ""},
+					Procedure: "Procedure X",
+				},
+			},
+			message: "Empty label specified for language fr",
+		},
 	}
 	for i, data := range testdata {
 		err := data.procedures.Validate()
@@ -722,3 +876,87 @@ func TestValidateAlternativeProcedures(t *testing.T) {
 		}
 	}
 }
+
+func TestPaymentInformationRemaining(t *testing.T) {
+	var testdata = []struct {
+		info      PaymentInformation
+		remaining int
+	}{
+		{PaymentInformation{}, 140},
+		{PaymentInformation{UnstructuredMessage: "Rechnung"}, 132},
+		{PaymentInformation{UnstructuredMessage: "Café"}, 136},
+		{PaymentInformation{UnstructuredMessage: strings.Repeat("é", 140)}, 0},
+		{PaymentInformation{StructuredMessage: BillInformation{CustomerReference: "ref"}}, 140 - len("//S1/20/ref")},
+		{PaymentInformation{UnstructuredMessage: strings.Repeat("x", 141)}, -1},
+	}
+	for i, data := range testdata {
+		if actual := data.info.Remaining(); actual != data.remaining {
+			t.Errorf("Item %v: expected %v, got %v", i, data.remaining, actual)
+		}
+		if valid := data.info.Validate() == nil; valid != (data.remaining >= 0) {
+			t.Errorf("Item %v: Remaining and Validate disagree", i)
+		}
+	}
+}
+
+func TestEntityValidateAs(t *testing.T) {
+	creditor := examplePayload1.Creditor
+	var testdata = []struct {
+		entity  Entity
+		role    Role
+		message string
+	}{
+		{creditor, CreditorRole, ""},
+		{Entity{}, CreditorRole, "No creditor name specified."},
+		{Entity{Address: creditor.Address, CountryCode: "CH"}, CreditorRole, "Name must be specified."},
+		{Entity{}, UltimateCreditorRole, ""},
+		{creditor, UltimateCreditorRole, "UltimateCreditor is currently not supported."},
+		{Entity{}, UltimateDebtorRole, ""},
+		{creditor, UltimateDebtorRole, ""},
+		{Entity{Name: "Pia Rutschmann"}, UltimateDebtorRole, "Country code must be specified"},
+		{creditor, Role(7), "Unknown role: 7"},
+	}
+	for i, data := range testdata {
+		err := data.entity.ValidateAs(data.role)
+		if data.message == "" {
+			if err != nil {
+				t.Errorf("Item %v: expected no error; got %v", i, err)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), data.message) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, data.message, err)
+		}
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	if errs := minimalCorrectPayload.ValidateAll(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got: %v", errs)
+	}
+	payload := minimalCorrectPayload
+	payload.Creditor = Entity{
+		Name:        strings.Repeat("x", 71),
+		Address:     StructuredAddress{StreetName: "Rue du Lac", PostCode: "2501"},
+		CountryCode: "XX",
+	}
+	payload.CurrencyAmount = PaymentAmount{Amount: -1, Currency: "USD"}
+	payload.AdditionalInformation.UnstructuredMessage = "Invoice ☺"
+	expected := []string{
+		"Creditor.Name",
+		"Creditor.CountryCode",
+		"Creditor.Address.TownName",
+		"CurrencyAmount.Currency",
+		"CurrencyAmount.Amount",
+		"AdditionalInformation.UnstructuredMessage",
+	}
+	errs := payload.ValidateAll()
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.(*ValidationError).Field)
+	}
+	if strings.Join(fields, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected errors for %v, got: %v", expected, errs)
+	}
+	if err := payload.Validate(); err.Error() != errs[0].Error() {
+		t.Errorf("Expected first error %v, got: %v", errs[0], err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorCode classifies a ValidationError independently of the wording of
+// its message.
+type ErrorCode string
+
+const (
+	// CodeRequired reports a mandatory field that is empty.
+	CodeRequired ErrorCode = "required"
+
+	// CodeTooLong reports a field that exceeds its maximum length.
+	CodeTooLong ErrorCode = "too_long"
+
+	// CodeInvalidCharacter reports a character outside the permitted
+	// character set.
+	CodeInvalidCharacter ErrorCode = "invalid_character"
+
+	// CodeInvalidFormat reports a value that does not have the required
+	// structure, such as an IBAN of the wrong length.
+	CodeInvalidFormat ErrorCode = "invalid_format"
+
+	// CodeInvalidValue reports a well-formed value that is not permitted,
+	// such as an unknown country code or a negative amount.
+	CodeInvalidValue ErrorCode = "invalid_value"
+
+	// CodeNotAllowed reports a field that must be empty.
+	CodeNotAllowed ErrorCode = "not_allowed"
+
+	// CodeInconsistent reports fields that are valid on their own but
+	// contradict each other, such as a QR-IBAN without QR reference.
+	CodeInconsistent ErrorCode = "inconsistent"
+
+	// CodeUnsupported reports a type or mode that this package does not
+	// know, such as an unknown address type.
+	CodeUnsupported ErrorCode = "unsupported"
+)
+
+// ValidationError is the error returned by the Validate methods. Field is
+// the path of the offending field relative to the validated value, e.g.
+// “Creditor.Address.PostCode” for Payload.Validate and “PostCode” for
+// StructuredAddress.Validate; elements of lists are given with their index,
+// e.g. “AlternativeProcedureParameters[1].Label”. Field is empty if the
+// validated value as a whole is at fault. Message is the English
+// description returned by Error, and Value is the offending value.
+type ValidationError struct {
+	Field   string
+	Code    ErrorCode
+	Message string
+	Value   string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// validationError returns a *ValidationError with a formatted message.
+func validationError(field string, code ErrorCode, value interface{}, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Value:   fmt.Sprint(value),
+	}
+}
+
+// inField prefixes the field path of a *ValidationError with field, the
+// name of the enclosing field. Other errors are returned unchanged.
+func inField(field string, err error) error {
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		return err
+	}
+	qualified := *ve
+	switch {
+	case ve.Field == "":
+		qualified.Field = field
+	case strings.HasPrefix(ve.Field, "["):
+		qualified.Field = field + ve.Field
+	default:
+		qualified.Field = field + "." + ve.Field
+	}
+	return &qualified
+}
+
+// appendInField appends errs to to, with their field paths prefixed by
+// field as by inField.
+func appendInField(to []error, field string, errs []error) []error {
+	for _, err := range errs {
+		to = append(to, inField(field, err))
+	}
+	return to
+}
+
+// firstError returns the first error of errs, or nil if there is none.
+func firstError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
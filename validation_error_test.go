@@ -0,0 +1,75 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	withDebtor := func(address StructuredAddress) Payload {
+		p := minimalCorrectPayload
+		p.UltimateDebtor = Entity{Name: "Pia Rutschmann", Address: address, CountryCode: "CH"}
+		return p
+	}
+	withProcedures := func(procedures ...AlternativeProcedure) Payload {
+		p := minimalCorrectPayload
+		p.AlternativeProcedureParameters = procedures
+		return p
+	}
+	withAmount := func(amount float64) Payload {
+		p := minimalCorrectPayload
+		p.CurrencyAmount.Amount = amount
+		return p
+	}
+	withRates := func(rates TaxRates) Payload {
+		p := minimalCorrectPayload
+		p.AdditionalInformation.StructuredMessage.VATRates = rates
+		return p
+	}
+	var testdata = []struct {
+		payload Payload
+		field   string
+		code    ErrorCode
+		value   string
+	}{
+		{Payload{}, "Account.IBAN", CodeRequired, ""},
+		{withAmount(-1), "CurrencyAmount.Amount", CodeInvalidValue, "-1"},
+		{withDebtor(StructuredAddress{PostCode: "9490"}), "UltimateDebtor.Address.TownName", CodeRequired, ""},
+		{withDebtor(StructuredAddress{PostCode: "9490", TownName: "Vaduz ☺"}),
+			"UltimateDebtor.Address.TownName", CodeInvalidCharacter, "Vaduz ☺"},
+		{withProcedures(AlternativeProcedure{Label: "Name AV1", Procedure: "UV;UltraPay005;12345"},
+			AlternativeProcedure{Procedure: "XY;XYService;54321"}),
+			"AlternativeProcedureParameters[1].Label", CodeRequired, ""},
+		{withRates(TaxRates{{RatePercent: 8}, {RatePercent: -2.5}}),
+			"AdditionalInformation.StructuredMessage.VATRates[1].RatePercent", CodeInvalidValue, "-2.5"},
+	}
+	for i, item := range testdata {
+		err := item.payload.Validate()
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Errorf("Item %v: expected *ValidationError, got: %#v", i, err)
+			continue
+		}
+		if ve.Field != item.field || ve.Code != item.code || ve.Value != item.value {
+			t.Errorf("Item %v: expected %v %v %q, got: %v %v %q", i,
+				item.field, item.code, item.value, ve.Field, ve.Code, ve.Value)
+		}
+		if ve.Error() != ve.Message {
+			t.Errorf("Item %v: expected message %q, got: %q", i, ve.Message, ve.Error())
+		}
+	}
+}
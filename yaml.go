@@ -0,0 +1,401 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The YAML format of a payload is meant to be written by hand, e.g. in the
+// configuration of a billing system:
+//
+//	account: CH44 3199 9123 0008 8901 2
+//	creditor:
+//	  name: Robert Schneider AG
+//	  street: Rue du Lac
+//	  building: 1268
+//	  postcode: 2501
+//	  town: Biel
+//	  country: CH
+//	amount: 1949.75
+//	currency: CHF
+//	debtor:
+//	  name: Pia-Maria Rutschmann-Schnyder
+//	  line1: Grosse Marktgasse 28
+//	  line2: 9400 Rorschach
+//	  country: CH
+//	reference: 21 00000 00003 13947 14300 09017
+//	message: Order of 15 June 2020
+//	bill:
+//	  invoice_number: 10201409
+//	  invoice_date: 2019-05-12
+//	  vat_rates:
+//	    - rate: 7.7
+//	procedures:
+//	  - label: Name AV1
+//	    procedure: UV;UltraPay005;12345
+//
+// Addresses are structured if they have a town and combined if they have
+// address lines, unless “address_type” says “structured” or “combined”.
+// The “reference_type” QRR, SCOR or NON is derived from the reference,
+// which may contain spaces. The amount is omitted for an empty amount box,
+// and the currency defaults to CHF. The bill information accepts the keys
+// invoice_number, invoice_date, customer_reference, vat_number, vat_date,
+// vat_start and vat_end, vat_rates and import_tax_rates as lists of rate
+// and amount, and conditions as list of discount and days. The VAT number
+// may be written as UID, e.g. CHE-106.017.086. Dates are written as
+// YYYY-MM-DD. Unknown keys are errors, so that misspelled keys
+// are not ignored.
+
+// LoadYAML reads a payload in YAML format from r and validates it.
+func LoadYAML(r io.Reader) (Payload, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Payload{}, err
+	}
+	p, err := PayloadFromYAML(b)
+	if err != nil {
+		return Payload{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return Payload{}, err
+	}
+	return p, nil
+}
+
+// PayloadFromYAML converts a YAML document to a payload like LoadYAML, but
+// does not validate the payload, e.g. to report all validation problems.
+func PayloadFromYAML(b []byte) (Payload, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return Payload{}, err
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return Payload{}, errors.New("YAML document must be a mapping")
+	}
+	d := &yamlDecoder{}
+	p := d.payload(&yamlMap{m: root})
+	if d.err != nil {
+		return Payload{}, d.err
+	}
+	return p, nil
+}
+
+// yamlDecoder converts the YAML values and keeps the first error.
+type yamlDecoder struct {
+	err error
+}
+
+func (d *yamlDecoder) fail(format string, a ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf(format, a...)
+	}
+}
+
+// yamlMap is a mapping of the document; path is its position, e.g.
+// “creditor.”, for error messages. Keys that are read are recorded, so
+// that the remaining ones can be reported as unknown.
+type yamlMap struct {
+	path string
+	m    map[string]interface{}
+	used map[string]bool
+}
+
+func (y *yamlMap) get(key string) (interface{}, bool) {
+	if y.used == nil {
+		y.used = make(map[string]bool)
+	}
+	y.used[key] = true
+	v, ok := y.m[key]
+	return v, ok && v != nil
+}
+
+func (d *yamlDecoder) checkKeys(y *yamlMap) {
+	var unknown []string
+	for key := range y.m {
+		if !y.used[key] {
+			unknown = append(unknown, y.path+key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		d.fail("Unknown YAML key: %v", strings.Join(unknown, ", "))
+	}
+}
+
+// text returns a string; numbers, such as post codes, are accepted as well.
+func (d *yamlDecoder) text(y *yamlMap, key string) string {
+	v, ok := y.get(key)
+	if !ok {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	d.fail("YAML key %v%v must be text: %v", y.path, key, v)
+	return ""
+}
+
+func (d *yamlDecoder) number(y *yamlMap, key string) (float64, bool) {
+	v, ok := y.get(key)
+	if !ok {
+		return 0, false
+	}
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	d.fail("YAML key %v%v must be a number: %v", y.path, key, v)
+	return 0, false
+}
+
+func (d *yamlDecoder) date(y *yamlMap, key string) time.Time {
+	v, ok := y.get(key)
+	if !ok {
+		return time.Time{}
+	}
+	switch v := v.(type) {
+	case time.Time:
+		return v
+	case string:
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			return t
+		}
+	}
+	d.fail("YAML key %v%v must be a date YYYY-MM-DD: %v", y.path, key, v)
+	return time.Time{}
+}
+
+func (d *yamlDecoder) mapping(y *yamlMap, key string) *yamlMap {
+	v, ok := y.get(key)
+	if !ok {
+		return nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		d.fail("YAML key %v%v must be a mapping", y.path, key)
+		return nil
+	}
+	return &yamlMap{path: y.path + key + ".", m: m}
+}
+
+func (d *yamlDecoder) list(y *yamlMap, key string) []*yamlMap {
+	v, ok := y.get(key)
+	if !ok {
+		return nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		d.fail("YAML key %v%v must be a list", y.path, key)
+		return nil
+	}
+	var maps []*yamlMap
+	for n, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			d.fail("YAML key %v%v[%d] must be a mapping", y.path, key, n)
+			return nil
+		}
+		maps = append(maps, &yamlMap{path: fmt.Sprintf("%v%v[%d].", y.path, key, n), m: m})
+	}
+	return maps
+}
+
+func (d *yamlDecoder) payload(y *yamlMap) Payload {
+	var p Payload
+	if account := d.text(y, "account"); account != "" {
+		code, err := ParseIBAN(account)
+		if err != nil {
+			d.fail("YAML key account: %v", err)
+		}
+		p.Account = AccountNumber{IBAN: code}
+	}
+	if creditor := d.mapping(y, "creditor"); creditor != nil {
+		p.Creditor = d.entity(creditor)
+	}
+	p.CurrencyAmount.Currency = d.text(y, "currency")
+	if p.CurrencyAmount.Currency == "" {
+		p.CurrencyAmount.Currency = CHF
+	}
+	if amount, ok := d.number(y, "amount"); ok {
+		p.CurrencyAmount.Amount = amount
+		if amount == 0 {
+			p.CurrencyAmount.Mode = AmountZero
+		}
+	}
+	if debtor := d.mapping(y, "debtor"); debtor != nil {
+		p.UltimateDebtor = d.entity(debtor)
+	}
+	p.Reference = d.reference(d.text(y, "reference"), d.text(y, "reference_type"))
+	p.AdditionalInformation.UnstructuredMessage = d.text(y, "message")
+	if bill := d.mapping(y, "bill"); bill != nil {
+		p.AdditionalInformation.StructuredMessage = d.bill(bill)
+	}
+	for _, procedure := range d.list(y, "procedures") {
+		p.AlternativeProcedureParameters = append(p.AlternativeProcedureParameters,
+			AlternativeProcedure{
+				Label:     d.text(procedure, "label"),
+				Procedure: d.text(procedure, "procedure"),
+			})
+		d.checkKeys(procedure)
+	}
+	if version := d.text(y, "version"); version != "" {
+		var err error
+		if p.Version, err = ParseSpecVersion(version); err != nil {
+			d.fail("YAML key version: %v", err)
+		}
+	}
+	d.checkKeys(y)
+	return p
+}
+
+func (d *yamlDecoder) entity(y *yamlMap) Entity {
+	e, err := newEntity(
+		d.text(y, "name"), d.text(y, "country"), d.text(y, "address_type"),
+		StructuredAddress{
+			StreetName:     d.text(y, "street"),
+			BuildingNumber: d.text(y, "building"),
+			PostCode:       d.text(y, "postcode"),
+			TownName:       d.text(y, "town"),
+		},
+		CombinedAddress{
+			AddressLine1: d.text(y, "line1"),
+			AddressLine2: d.text(y, "line2"),
+		})
+	if err != nil {
+		d.fail("YAML key %vaddress_type: %v", y.path, err)
+	}
+	d.checkKeys(y)
+	return e
+}
+
+// newEntity returns an entity with either the structured or the combined
+// address. The address type “structured” or “combined” may be empty; then
+// the address is combined if it has address lines.
+func newEntity(name, country, addressType string, structured StructuredAddress, combined CombinedAddress) (Entity, error) {
+	e := Entity{Name: name, CountryCode: country}
+	addressType = strings.ToLower(addressType)
+	if addressType == "" {
+		addressType = "structured"
+		if combined != (CombinedAddress{}) {
+			addressType = "combined"
+		}
+	}
+	switch addressType {
+	case "structured":
+		if combined != (CombinedAddress{}) {
+			return Entity{}, fmt.Errorf("Address lines given for structured address: %v", name)
+		}
+		e.Address = structured
+	case "combined":
+		if structured != (StructuredAddress{}) {
+			return Entity{}, fmt.Errorf("Street, building, post code or town given for combined address: %v", name)
+		}
+		e.Address = combined
+	default:
+		return Entity{}, fmt.Errorf("Address type must be structured or combined: %v", addressType)
+	}
+	return e, nil
+}
+
+func (d *yamlDecoder) reference(reference, referenceType string) PaymentReference {
+	ref, err := newReference(reference, referenceType)
+	if err != nil {
+		d.fail("YAML key reference: %v", err)
+	}
+	return ref
+}
+
+// newReference parses a reference that may contain spaces. The reference
+// type QRR, SCOR or NON may be empty; then it is derived from the reference.
+func newReference(reference, referenceType string) (PaymentReference, error) {
+	reference = strings.ReplaceAll(reference, " ", "")
+	referenceType = strings.ToUpper(referenceType)
+	if referenceType == "" {
+		switch {
+		case reference == "":
+			referenceType = "NON"
+		case strings.HasPrefix(strings.ToUpper(reference), "RF"):
+			referenceType = "SCOR"
+		default:
+			referenceType = "QRR"
+		}
+	}
+	return parseReference(referenceType, reference)
+}
+
+func (d *yamlDecoder) bill(y *yamlMap) BillInformation {
+	bi := BillInformation{
+		InvoiceNumber:     d.text(y, "invoice_number"),
+		InvoiceDate:       dates{Date: d.date(y, "invoice_date")},
+		CustomerReference: d.text(y, "customer_reference"),
+		VATNumber:         vatNumberReplacer.Replace(d.text(y, "vat_number")),
+		VATRates:          d.taxRates(y, "vat_rates"),
+		VATImportTaxRates: d.taxRates(y, "import_tax_rates"),
+	}
+	if date := d.date(y, "vat_date"); !date.IsZero() {
+		bi.VATDates = dates{Date: date}
+	}
+	start, end := d.date(y, "vat_start"), d.date(y, "vat_end")
+	if !start.IsZero() || !end.IsZero() {
+		if !bi.VATDates.Date.IsZero() || start.IsZero() || end.IsZero() {
+			d.fail("YAML key %vvat_start and %vvat_end must be given together instead of vat_date", y.path, y.path)
+		}
+		bi.VATDates = dates{Date: start, End: end}
+	}
+	for _, condition := range d.list(y, "conditions") {
+		discount, _ := d.number(condition, "discount")
+		days, _ := d.number(condition, "days")
+		bi.Conditions = append(bi.Conditions, PaymentCondition{
+			DiscountPercent: discount,
+			NumberOfDays:    int(days),
+		})
+		d.checkKeys(condition)
+	}
+	d.checkKeys(y)
+	return bi
+}
+
+// vatNumberReplacer turns a UID such as “CHE-106.017.086” into the digits
+// used in the bill information.
+var vatNumberReplacer = strings.NewReplacer("CHE", "", "-", "", ".", "", " ", "")
+
+func (d *yamlDecoder) taxRates(y *yamlMap, key string) TaxRates {
+	var rates TaxRates
+	for _, rate := range d.list(y, key) {
+		percent, _ := d.number(rate, "rate")
+		amount, _ := d.number(rate, "amount")
+		rates = append(rates, TaxRate{RatePercent: percent, Amount: amount})
+		d.checkKeys(rate)
+	}
+	return rates
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package swissqr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const yamlExample = `
+account: CH44 3199 9123 0008 8901 2
+creditor:
+  name: Robert Schneider AG
+  street: Rue du Lac
+  building: 1268
+  postcode: 2501
+  town: Biel
+  country: CH
+amount: 1949.75
+debtor:
+  name: Pia-Maria Rutschmann-Schnyder
+  street: Grosse Marktgasse
+  building: 28
+  postcode: 9400
+  town: Rorschach
+  country: CH
+reference: 21 00000 00003 13947 14300 09017
+message: Auftrag vom 18.06.2020
+bill:
+  invoice_number: 10201409
+  invoice_date: 2019-05-12
+  customer_reference: 140.000-53
+  vat_number: CHE-106.017.086
+  vat_date: 2018-05-08
+  vat_rates:
+    - rate: 7.7
+  conditions:
+    - discount: 2
+      days: 10
+    - discount: 0
+      days: 30
+procedures:
+  - label: Name AV1
+    procedure: UV;UltraPay005;12345
+  - label: Name AV2
+    procedure: XY;XYService;54321
+`
+
+func TestLoadYAML(t *testing.T) {
+	p, err := LoadYAML(strings.NewReader(yamlExample))
+	if err != nil {
+		t.Fatalf("Could not load payload: %v", err)
+	}
+	var expected, actual bytes.Buffer
+	if err := examplePayload2.Serialize(&expected); err != nil {
+		t.Fatalf("Could not serialize payload: %v", err)
+	}
+	if err := p.Serialize(&actual); err != nil {
+		t.Fatalf("Could not serialize loaded payload: %v", err)
+	}
+	if expected.String() != actual.String() {
+		t.Errorf("Expected:\n\n%#v\n\nGot:\n\n%#v\n\n", expected.String(), actual.String())
+	}
+}
+
+func TestPayloadFromYAML(t *testing.T) {
+	for i, test := range []struct {
+		input string
+		check func(Payload) bool
+	}{
+		{
+			"creditor:\n  name: A\n  line1: Rue du Lac 1268\n  line2: 2501 Biel\n",
+			func(p Payload) bool {
+				_, ok := p.Creditor.Address.(CombinedAddress)
+				return ok
+			},
+		},
+		{
+			"amount: 0\ncurrency: EUR\n",
+			func(p Payload) bool {
+				return p.CurrencyAmount.Mode == AmountZero && p.CurrencyAmount.Currency == EUR
+			},
+		},
+		{
+			"reference: RF18 5390 0754 7034\n",
+			func(p Payload) bool { return p.Reference.Number != nil },
+		},
+		{
+			"bill:\n  vat_start: 2019-05-01\n  vat_end: 2019-05-31\n",
+			func(p Payload) bool {
+				return p.AdditionalInformation.StructuredMessage.ToString() == "//S1/31/190501190531"
+			},
+		},
+	} {
+		p, err := PayloadFromYAML([]byte(test.input))
+		if err != nil {
+			t.Errorf("Item %v: expected no error, got: %v", i, err)
+			continue
+		}
+		if !test.check(p) {
+			t.Errorf("Item %v: unexpected payload: %#v", i, p)
+		}
+	}
+}
+
+func TestPayloadFromYAMLErrors(t *testing.T) {
+	for i, test := range []struct {
+		input    string
+		expected string
+	}{
+		{"- account\n", "YAML document must be a mapping"},
+		{"acount: CH44 3199 9123 0008 8901 2\n", "Unknown YAML key: acount"},
+		{"creditor:\n  strete: Rue du Lac\n", "Unknown YAML key: creditor.strete"},
+		{"creditor:\n  address_type: combined\n  town: Biel\n", "given for combined address: "},
+		{"creditor:\n  address_type: foreign\n", "Address type must be structured or combined"},
+		{"amount: many\n", "YAML key amount must be a number"},
+		{"reference: 123\nreference_type: NON\n", "Reference must be empty for type NON"},
+		{"bill:\n  invoice_date: 12.05.2019\n", "must be a date YYYY-MM-DD"},
+		{"bill:\n  vat_start: 2019-05-01\n", "must be given together"},
+		{"procedures:\n  - label: A\n    procdure: B\n", "Unknown YAML key: procedures[0].procdure"},
+	} {
+		_, err := PayloadFromYAML([]byte(test.input))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Item %v: expected error %#v, got: %v", i, test.expected, err)
+		}
+	}
+}